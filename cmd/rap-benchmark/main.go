@@ -66,10 +66,10 @@ func main() {
 					fmt.Fprintf(c.App.Writer, "Original Size: %s\n\n", kb(originalBytesRead))
 
 					encoders := []encoding.Encoder{
-						event.NewEncoder(),
+						event.NewEncoder(event.Columnar),
 						position.NewEncoder(position.Oct48),
 						euler.NewEncoder(euler.Raw32),
-						enum.NewEncoder(),
+						enum.NewEncoder(enum.RunLength),
 					}
 
 					recBuf := bytes.Buffer{}
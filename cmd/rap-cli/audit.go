@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/recolude/rap/format/audit"
+	"github.com/recolude/rap/format/encoding"
+	"github.com/recolude/rap/format/encoding/boolean"
+	"github.com/recolude/rap/format/encoding/enum"
+	"github.com/recolude/rap/format/encoding/euler"
+	"github.com/recolude/rap/format/encoding/event"
+	"github.com/recolude/rap/format/encoding/float"
+	"github.com/recolude/rap/format/encoding/floatvec"
+	"github.com/recolude/rap/format/encoding/gaze"
+	"github.com/recolude/rap/format/encoding/integer"
+	"github.com/recolude/rap/format/encoding/position"
+	"github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/recolude/rap/format/encoding/span"
+	"github.com/recolude/rap/format/encoding/structure"
+	"github.com/recolude/rap/format/encoding/vector2"
+	rapio "github.com/recolude/rap/format/io"
+)
+
+type encoderSettings struct {
+	position  string
+	euler     string
+	float     string
+	enum      string
+	integer   string
+	vector2   string
+	skeleton  string
+	gaze      string
+	floatvec  string
+	structure string
+	event     string
+	time      string
+}
+
+func unknownTechnique(kind, technique string) error {
+	return fmt.Errorf("unrecognized %s technique: '%s'", kind, technique)
+}
+
+func (s encoderSettings) build() ([]encoding.Encoder, rapio.TimeStorageTechnique, error) {
+	var positionTechnique position.StorageTechnique
+	switch strings.ToLower(s.position) {
+	case "raw64":
+		positionTechnique = position.Raw64
+	case "raw32":
+		positionTechnique = position.Raw32
+	case "oct24":
+		positionTechnique = position.Oct24
+	case "oct48":
+		positionTechnique = position.Oct48
+	default:
+		return nil, 0, unknownTechnique("position", s.position)
+	}
+
+	var eulerTechnique euler.StorageTechnique
+	switch strings.ToLower(s.euler) {
+	case "raw64":
+		eulerTechnique = euler.Raw64
+	case "raw32":
+		eulerTechnique = euler.Raw32
+	case "raw16":
+		eulerTechnique = euler.Raw16
+	default:
+		return nil, 0, unknownTechnique("euler", s.euler)
+	}
+
+	var floatTechnique float.StorageTechnique
+	switch strings.ToLower(s.float) {
+	case "raw64":
+		floatTechnique = float.Raw64
+	case "raw32":
+		floatTechnique = float.Raw32
+	case "bst16":
+		floatTechnique = float.BST16
+	default:
+		return nil, 0, unknownTechnique("float", s.float)
+	}
+
+	var enumTechnique enum.StorageTechnique
+	switch strings.ToLower(s.enum) {
+	case "raw":
+		enumTechnique = enum.Raw
+	case "runlength":
+		enumTechnique = enum.RunLength
+	case "bitpacked":
+		enumTechnique = enum.BitPacked
+	default:
+		return nil, 0, unknownTechnique("enum", s.enum)
+	}
+
+	var integerTechnique integer.StorageTechnique
+	switch strings.ToLower(s.integer) {
+	case "raw":
+		integerTechnique = integer.Raw
+	case "zigzagdelta":
+		integerTechnique = integer.ZigZagDelta
+	case "frameofreference":
+		integerTechnique = integer.FrameOfReference
+	default:
+		return nil, 0, unknownTechnique("int", s.integer)
+	}
+
+	var vector2Technique vector2.StorageTechnique
+	switch strings.ToLower(s.vector2) {
+	case "raw64":
+		vector2Technique = vector2.Raw64
+	case "raw32":
+		vector2Technique = vector2.Raw32
+	case "quad32":
+		vector2Technique = vector2.Quad32
+	case "quad16":
+		vector2Technique = vector2.Quad16
+	default:
+		return nil, 0, unknownTechnique("vector2", s.vector2)
+	}
+
+	var skeletonTechnique skeleton.StorageTechnique
+	switch strings.ToLower(s.skeleton) {
+	case "raw32":
+		skeletonTechnique = skeleton.Raw32
+	case "quantized16":
+		skeletonTechnique = skeleton.Quantized16
+	case "quantized8":
+		skeletonTechnique = skeleton.Quantized8
+	default:
+		return nil, 0, unknownTechnique("skeleton", s.skeleton)
+	}
+
+	var gazeTechnique gaze.StorageTechnique
+	switch strings.ToLower(s.gaze) {
+	case "raw32":
+		gazeTechnique = gaze.Raw32
+	case "compact":
+		gazeTechnique = gaze.Compact
+	default:
+		return nil, 0, unknownTechnique("gaze", s.gaze)
+	}
+
+	var floatvecTechnique floatvec.StorageTechnique
+	switch strings.ToLower(s.floatvec) {
+	case "raw64":
+		floatvecTechnique = floatvec.Raw64
+	case "raw32":
+		floatvecTechnique = floatvec.Raw32
+	case "bst16":
+		floatvecTechnique = floatvec.BST16
+	case "deltabst16":
+		floatvecTechnique = floatvec.DeltaBST16
+	default:
+		return nil, 0, unknownTechnique("floatvec", s.floatvec)
+	}
+
+	var structureTechnique structure.StorageTechnique
+	switch strings.ToLower(s.structure) {
+	case "raw64":
+		structureTechnique = structure.Raw64
+	case "raw32":
+		structureTechnique = structure.Raw32
+	case "bst16":
+		structureTechnique = structure.BST16
+	default:
+		return nil, 0, unknownTechnique("struct", s.structure)
+	}
+
+	var eventTechnique event.StorageTechnique
+	switch strings.ToLower(s.event) {
+	case "row":
+		eventTechnique = event.Row
+	case "columnar":
+		eventTechnique = event.Columnar
+	default:
+		return nil, 0, unknownTechnique("event", s.event)
+	}
+
+	var timeTechnique rapio.TimeStorageTechnique
+	switch strings.ToLower(s.time) {
+	case "raw64":
+		timeTechnique = rapio.Raw64
+	case "raw32":
+		timeTechnique = rapio.Raw32
+	case "bst16":
+		timeTechnique = rapio.BST16
+	default:
+		return nil, 0, unknownTechnique("time", s.time)
+	}
+
+	encoders := []encoding.Encoder{
+		event.NewEncoder(eventTechnique),
+		position.NewEncoder(positionTechnique),
+		euler.NewEncoder(eulerTechnique),
+		enum.NewEncoder(enumTechnique),
+		float.NewEncoder(floatTechnique),
+		integer.NewEncoder(integerTechnique),
+		vector2.NewEncoder(vector2Technique),
+		boolean.NewEncoder(),
+		span.NewEncoder(),
+		skeleton.NewEncoder(skeletonTechnique),
+		gaze.NewEncoder(gazeTechnique),
+		floatvec.NewEncoder(floatvecTechnique),
+		structure.NewEncoder(structureTechnique),
+	}
+
+	return encoders, timeTechnique, nil
+}
+
+func printAudit(out io.Writer, report audit.Report) {
+	fmt.Fprintf(out, "Total Size: %s\n", printSize(int64(report.TotalBytes)))
+	for _, stream := range report.Streams {
+		fmt.Fprintf(out, "\n%s/%s (%s)\n", stream.Path, stream.Name, stream.Signature)
+		fmt.Fprintf(out, "  Captures:          %d\n", stream.Captures)
+		fmt.Fprintf(out, "  Size:              %s\n", printSize(int64(stream.Bytes)))
+		fmt.Fprintf(out, "  Bytes Per Capture: %.2f\n", stream.BytesPerCapture)
+		fmt.Fprintf(out, "  Value Error:       max %g, mean %g, rms %g, worst capture %d\n", stream.Value.Max, stream.Value.Mean, stream.Value.RMS, stream.Value.WorstIndex)
+		fmt.Fprintf(out, "  Time Error:        max %g, mean %g, rms %g, worst capture %d\n", stream.Time.Max, stream.Time.Mean, stream.Time.RMS, stream.Time.WorstIndex)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Audit(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+
+	filePath := filepath.Join(t.TempDir(), "audit.rap")
+	file, err := os.Create(filePath)
+	if assert.NoError(t, err) == false {
+		return
+	}
+
+	_, writeErr := io.NewRecoludeWriter(file).Write(format.NewRecording(
+		"",
+		"parent",
+		[]format.CaptureCollection{
+			position.NewCollection(
+				"Position",
+				[]position.Capture{
+					position.NewCapture(1, 2, 3, 4),
+					position.NewCapture(2, 3, 4, 5),
+					position.NewCapture(3, 1, 4, 6),
+				},
+			),
+		},
+		nil,
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	))
+	file.Close()
+
+	// ACT ====================================================================
+	err = app.Run([]string{"rap-cli", "audit", "-f", filePath, "--position", "raw64", "--time", "raw64"})
+
+	// ASSERT =================================================================
+	assert.NoError(t, writeErr)
+	assert.NoError(t, err)
+	assert.Equal(t, "", appErrOut.String())
+	assert.Contains(t, appOut.String(), "parent/Position (recolude.position)\n")
+	assert.Contains(t, appOut.String(), "  Captures:          3\n")
+	assert.Contains(t, appOut.String(), "  Value Error:       max 0, mean 0, rms 0, worst capture 0\n")
+	assert.Contains(t, appOut.String(), "  Time Error:        max 0, mean 0, rms 0, worst capture 0\n")
+}
+
+func Test_Audit_UnknownTechnique(t *testing.T) {
+	_, _, err := encoderSettings{
+		position:  "oct24",
+		euler:     "raw16",
+		float:     "bst16",
+		enum:      "rle",
+		integer:   "zigzagdelta",
+		vector2:   "quad16",
+		skeleton:  "quantized16",
+		gaze:      "compact",
+		floatvec:  "bst16",
+		structure: "raw32",
+		event:     "columnar",
+		time:      "bst16",
+	}.build()
+
+	assert.EqualError(t, err, "unrecognized enum technique: 'rle'")
+}
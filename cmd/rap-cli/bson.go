@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/format/parsing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// recordingFromBSONDump reads every document in a .bson dump. A dump with a
+// single document is returned as is, while dumps with more than one are
+// gathered up as children of an otherwise empty recording.
+func recordingFromBSONDump(in io.Reader) (format.Recording, error) {
+	recordings := make([]format.Recording, 0)
+	for {
+		doc, err := bson.NewFromIOReader(in)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		recording, err := parsing.FromBSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		recordings = append(recordings, recording)
+	}
+
+	switch len(recordings) {
+	case 0:
+		return nil, errors.New("bson dump contains no documents")
+	case 1:
+		return recordings[0], nil
+	}
+
+	return format.NewRecording("", "bson dump", nil, recordings, metadata.EmptyBlock(), nil, nil), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/format/parsing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BSON(t *testing.T) {
+	// ARRANGE ================================================================
+	rapIn := bytes.Buffer{}
+	_, writeErr := io.NewRecoludeWriter(&rapIn).Write(
+		format.NewRecording(
+			"some-id",
+			"parent",
+			[]format.CaptureCollection{
+				position.NewCollection("Position", []position.Capture{position.NewCapture(1, 2, 3, 4)}),
+			},
+			nil,
+			metadata.NewBlock(map[string]metadata.Property{
+				"level": metadata.NewStringProperty("castle"),
+			}),
+			nil,
+			nil,
+		),
+	)
+
+	bsonOut := bytes.Buffer{}
+	rapOut := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+
+	// ACT ====================================================================
+	toErr := BuildApp(&rapIn, &bsonOut, &errOut).Run([]string{"rap-cli", "to-bson"})
+	bsonData := append([]byte{}, bsonOut.Bytes()...)
+	fromErr := BuildApp(&bsonOut, &rapOut, &errOut).Run([]string{"rap-cli", "from-bson"})
+	recording, _, loadErr := io.Load(&rapOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, writeErr)
+	assert.NoError(t, toErr)
+	assert.NoError(t, fromErr)
+	assert.NoError(t, loadErr)
+	assert.Equal(t, "", errOut.String())
+
+	fromDoc, err := parsing.FromBSON(bsonData)
+	assert.NoError(t, err)
+	if assert.NotNil(t, fromDoc) {
+		assert.Equal(t, "some-id", fromDoc.ID())
+	}
+
+	if assert.NotNil(t, recording) {
+		assert.Equal(t, "some-id", recording.ID())
+		assert.Equal(t, "parent", recording.Name())
+		assert.Equal(t, "castle", recording.Metadata().Mapping()["level"].String())
+		if assert.Len(t, recording.CaptureCollections(), 1) {
+			assert.Equal(t, 1, recording.CaptureCollections()[0].Length())
+		}
+	}
+}
+
+func Test_BSON_DumpWithManyDocuments(t *testing.T) {
+	// ARRANGE ================================================================
+	dump := bytes.Buffer{}
+	for _, id := range []string{"a", "b"} {
+		doc, err := parsing.ToBSON(format.NewRecording(id, id, nil, nil, metadata.EmptyBlock(), nil, nil))
+		assert.NoError(t, err)
+		dump.Write(doc)
+	}
+
+	rapOut := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+
+	// ACT ====================================================================
+	err := BuildApp(&dump, &rapOut, &errOut).Run([]string{"rap-cli", "from-bson"})
+	recording, _, loadErr := io.Load(&rapOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, loadErr)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.Recordings(), 2) {
+		assert.Equal(t, "a", recording.Recordings()[0].ID())
+		assert.Equal(t, "b", recording.Recordings()[1].ID())
+	}
+}
+
+func Test_BSON_EmptyDump(t *testing.T) {
+	// ARRANGE ================================================================
+	rapOut := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+
+	// ACT ====================================================================
+	err := BuildApp(&bytes.Buffer{}, &rapOut, &errOut).Run([]string{"rap-cli", "from-bson"})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "bson dump contains no documents")
+}
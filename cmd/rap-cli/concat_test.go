@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Concat(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+
+	aPath := filepath.Join(t.TempDir(), "a.rap")
+	writeClientRecording(t, aPath, "client", 0)
+	bPath := filepath.Join(t.TempDir(), "b.rap")
+	writeClientRecording(t, bPath, "client", 0)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "concat", "--rebase", "--gap", "1", aPath, bPath})
+	joined, _, loadErr := io.Load(&appOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, loadErr)
+	assert.Equal(t, "", appErrOut.String())
+	if assert.NotNil(t, joined) && assert.Len(t, joined.CaptureCollections(), 1) {
+		events := joined.CaptureCollections()[0]
+		if assert.Equal(t, 4, events.Length()) {
+			assert.InDelta(t, 1, events.CaptureAt(0).Time(), 0.01)
+			assert.InDelta(t, 3, events.CaptureAt(1).Time(), 0.01)
+			assert.InDelta(t, 4, events.CaptureAt(2).Time(), 0.01)
+			assert.InDelta(t, 6, events.CaptureAt(3).Time(), 0.01)
+		}
+	}
+}
+
+func Test_Concat_TooFewFiles(t *testing.T) {
+	// ARRANGE ================================================================
+	app := BuildApp(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "concat", "only.rap"})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "concat requires at least two files")
+}
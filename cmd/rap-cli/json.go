@@ -1,12 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/EliCDavis/vector"
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/structure"
 	"github.com/recolude/rap/format/metadata"
 )
 
@@ -44,6 +47,13 @@ func toJson(out io.Writer, recording format.Recording, depth int) error {
 		fmt.Fprintf(out, "%s{\n", subsubIndentation)
 		fmt.Fprintf(out, "%s\t\"name\": \"%s\",\n", subsubIndentation, collection.Name())
 		fmt.Fprintf(out, "%s\t\"signature\" : \"%s\",\n", subsubIndentation, collection.Signature())
+
+		collectionMetadataJSONData, err := metadata.NewMetadataProperty(collection.Metadata()).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\t\"metadata\": %s,\n", subsubIndentation, string(collectionMetadataJSONData))
+
 		fmt.Fprintf(out, "%s\t\"count\" : %d", subsubIndentation, collection.Length())
 		if collection.Signature() == "recolude.event" {
 			fmt.Fprintf(out, ",\n%s\t\"captures\": [\n", subsubIndentation)
@@ -70,6 +80,10 @@ func toJson(out io.Writer, recording format.Recording, depth int) error {
 				}
 			}
 			fmt.Fprintf(out, "%s\t]\n", subsubIndentation)
+		} else if structCollection, ok := collection.(structure.Collection); ok {
+			if err := writeStructCaptures(out, structCollection, subsubIndentation); err != nil {
+				return err
+			}
 		} else {
 			fmt.Fprint(out, "\n")
 		}
@@ -113,3 +127,80 @@ func toJson(out io.Writer, recording format.Recording, depth int) error {
 	fmt.Fprintf(out, "%s}", indentation)
 	return nil
 }
+
+type structFieldJSON struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Options []string `json:"options,omitempty"`
+}
+
+// structValueJSON converts a field's value into something that marshals the
+// same way the parser expects to read it back.
+func structValueJSON(field structure.Field, value interface{}) interface{} {
+	switch v := value.(type) {
+	case vector.Vector3:
+		return map[string]float64{"x": v.X(), "y": v.Y(), "z": v.Z()}
+	case int:
+		if field.Type == structure.Enum {
+			return field.Options[v]
+		}
+	}
+	return value
+}
+
+func writeStructCaptures(out io.Writer, collection structure.Collection, indentation string) error {
+	fields := collection.Schema().Fields()
+	schema := make([]structFieldJSON, len(fields))
+	for i, field := range fields {
+		schema[i] = structFieldJSON{Name: field.Name, Type: field.Type.String()}
+		if field.Type == structure.Enum {
+			schema[i].Options = field.Options
+		}
+	}
+
+	schemaJSONData, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, ",\n%s\t\"schema\": %s", indentation, string(schemaJSONData))
+	fmt.Fprintf(out, ",\n%s\t\"captures\": [\n", indentation)
+
+	for capIndex := 0; capIndex < collection.Length(); capIndex++ {
+		capture := collection.CaptureAt(capIndex).(structure.Capture)
+
+		// Marshal field by field to keep the schema's ordering
+		dataBuilder := strings.Builder{}
+		dataBuilder.WriteString("{")
+		for i, field := range fields {
+			nameJSONData, err := json.Marshal(field.Name)
+			if err != nil {
+				return err
+			}
+
+			valueJSONData, err := json.Marshal(structValueJSON(field, capture.Value(i)))
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				dataBuilder.WriteString(",")
+			}
+			dataBuilder.Write(nameJSONData)
+			dataBuilder.WriteString(":")
+			dataBuilder.Write(valueJSONData)
+		}
+		dataBuilder.WriteString("}")
+
+		fmt.Fprintf(out, "%s\t\t{\n", indentation)
+		fmt.Fprintf(out, "%s\t\t\t\"time\": %f,\n", indentation, capture.Time())
+		fmt.Fprintf(out, "%s\t\t\t\"data\": %s\n", indentation, dataBuilder.String())
+		fmt.Fprintf(out, "%s\t\t}", indentation)
+		if capIndex < collection.Length()-1 {
+			fmt.Fprintf(out, ",\n")
+		} else {
+			fmt.Fprintf(out, "\n")
+		}
+	}
+	fmt.Fprintf(out, "%s\t]\n", indentation)
+	return nil
+}
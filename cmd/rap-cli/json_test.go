@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/EliCDavis/vector"
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/collection/event"
 	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/structure"
 	"github.com/recolude/rap/format/io"
 	"github.com/recolude/rap/format/metadata"
 	"github.com/stretchr/testify/assert"
@@ -94,11 +96,13 @@ func Test_JSON(t *testing.T) {
 		{
 			"name": "Position",
 			"signature" : "recolude.position",
+			"metadata": {},
 			"count" : 1
 		},
 		{
 			"name": "My Events",
 			"signature" : "recolude.event",
+			"metadata": {},
 			"count" : 2,
 			"captures": [
 				{
@@ -130,11 +134,13 @@ func Test_JSON(t *testing.T) {
 				{
 					"name": "Position",
 					"signature" : "recolude.position",
+					"metadata": {},
 					"count" : 1
 				},
 				{
 					"name": "Position2",
 					"signature" : "recolude.position",
+					"metadata": {},
 					"count" : 1
 				}
 			],
@@ -143,3 +149,69 @@ func Test_JSON(t *testing.T) {
 	]
 }`, appOut.String())
 }
+
+func Test_JSON_StructCollection(t *testing.T) {
+	// ARRANGE ================================================================
+	appIn := bytes.Buffer{}
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&appIn, &appOut, &appErrOut)
+	if assert.NotNil(t, app) == false {
+		return
+	}
+
+	schema, err := structure.NewSchema([]structure.Field{
+		{Name: "health", Type: structure.Float},
+		{Name: "velocity", Type: structure.Vector3},
+		{Name: "weapon", Type: structure.Enum, Options: []string{"sword", "bow"}},
+	})
+	assert.NoError(t, err)
+
+	rapWriter := io.NewRecoludeWriter(&appIn)
+	_, writeErr := rapWriter.Write(
+		format.NewRecording(
+			"",
+			"parent",
+			[]format.CaptureCollection{
+				structure.NewCollection("Player", schema, []structure.Capture{
+					structure.NewCapture(1, []interface{}{87.5, vector.NewVector3(1, 0, -2), 1}),
+				}).WithMetadata(metadata.NewBlock(map[string]metadata.Property{
+					"units": metadata.NewStringProperty("meters"),
+				})),
+			},
+			nil,
+			metadata.EmptyBlock(),
+			nil,
+			nil,
+		),
+	)
+
+	// ACT ====================================================================
+	err = app.Run([]string{"rap-cli", "to-json"})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, writeErr)
+	assert.Equal(t, "", appErrOut.String())
+	assert.Equal(t, `{
+	"id": "",
+	"name": "parent",
+	"metadata": {},
+	"collections": [
+		{
+			"name": "Player",
+			"signature" : "recolude.struct",
+			"metadata": {"units":"meters"},
+			"count" : 1,
+			"schema": [{"name":"health","type":"float"},{"name":"velocity","type":"vector3"},{"name":"weapon","type":"enum","options":["sword","bow"]}],
+			"captures": [
+				{
+					"time": 1.000000,
+					"data": {"health":87.5,"velocity":{"x":1,"y":0,"z":-2},"weapon":"bow"}
+				}
+			]
+		}
+	],
+	"recordings": []
+}`, appOut.String())
+}
@@ -7,15 +7,28 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/audit"
 	"github.com/recolude/rap/format/encoding"
+	"github.com/recolude/rap/format/encoding/boolean"
 	"github.com/recolude/rap/format/encoding/enum"
 	"github.com/recolude/rap/format/encoding/euler"
 	"github.com/recolude/rap/format/encoding/event"
+	"github.com/recolude/rap/format/encoding/float"
+	"github.com/recolude/rap/format/encoding/floatvec"
+	"github.com/recolude/rap/format/encoding/gaze"
+	"github.com/recolude/rap/format/encoding/integer"
 	"github.com/recolude/rap/format/encoding/position"
+	"github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/recolude/rap/format/encoding/span"
+	"github.com/recolude/rap/format/encoding/structure"
+	"github.com/recolude/rap/format/encoding/vector2"
 	rapio "github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
 	"github.com/recolude/rap/format/parsing"
+	"github.com/recolude/rap/format/query"
 	"github.com/urfave/cli/v2"
 )
 
@@ -23,6 +36,28 @@ func kb(byteCount int) string {
 	return fmt.Sprintf("%d kb", byteCount/1024)
 }
 
+// defaultEncoders are the encoders commands building recordings from other
+// formats use. Commands transforming existing recordings write them with
+// rapio.NewLosslessWriter instead, so they don't lose precision every time
+// they're run.
+func defaultEncoders() []encoding.Encoder {
+	return []encoding.Encoder{
+		event.NewEncoder(event.Columnar),
+		position.NewEncoder(position.Oct24),
+		euler.NewEncoder(euler.Raw16),
+		enum.NewEncoder(enum.RunLength),
+		float.NewEncoder(float.BST16),
+		integer.NewEncoder(integer.ZigZagDelta),
+		vector2.NewEncoder(vector2.Quad16),
+		boolean.NewEncoder(),
+		span.NewEncoder(),
+		skeleton.NewEncoder(skeleton.Quantized16),
+		gaze.NewEncoder(gaze.Compact),
+		floatvec.NewEncoder(floatvec.BST16),
+		structure.NewEncoder(structure.Raw32),
+	}
+}
+
 func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 	return &cli.App{
 		Name:  "RAP CLI",
@@ -47,6 +82,7 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						Required: true,
 						Usage:    "File to turn to summarize",
 					},
+					pathFlag,
 				},
 				Usage: "Summarizes a file",
 				Action: func(c *cli.Context) error {
@@ -68,10 +104,131 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						return err
 					}
 
+					recording, err = selectRecording(recording, c.String("path"))
+					if err != nil {
+						return err
+					}
+
 					printSummary(c.App.Writer, recording, size)
 					return nil
 				},
 			},
+			{
+				Name: "audit",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Required: true,
+						Usage:    "File to audit",
+					},
+					pathFlag,
+					&cli.StringFlag{
+						Name:  "position",
+						Value: "oct24",
+						Usage: "Position technique (raw64, raw32, oct48, oct24)",
+					},
+					&cli.StringFlag{
+						Name:  "euler",
+						Value: "raw16",
+						Usage: "Euler technique (raw64, raw32, raw16)",
+					},
+					&cli.StringFlag{
+						Name:  "float",
+						Value: "bst16",
+						Usage: "Float technique (raw64, raw32, bst16)",
+					},
+					&cli.StringFlag{
+						Name:  "enum",
+						Value: "runlength",
+						Usage: "Enum technique (raw, runlength, bitpacked)",
+					},
+					&cli.StringFlag{
+						Name:  "int",
+						Value: "zigzagdelta",
+						Usage: "Int technique (raw, zigzagdelta, frameofreference)",
+					},
+					&cli.StringFlag{
+						Name:  "vector2",
+						Value: "quad16",
+						Usage: "Vector2 technique (raw64, raw32, quad32, quad16)",
+					},
+					&cli.StringFlag{
+						Name:  "skeleton",
+						Value: "quantized16",
+						Usage: "Skeleton technique (raw32, quantized16, quantized8)",
+					},
+					&cli.StringFlag{
+						Name:  "gaze",
+						Value: "compact",
+						Usage: "Gaze technique (raw32, compact)",
+					},
+					&cli.StringFlag{
+						Name:  "floatvec",
+						Value: "bst16",
+						Usage: "Float vector technique (raw64, raw32, bst16, deltabst16)",
+					},
+					&cli.StringFlag{
+						Name:  "struct",
+						Value: "raw32",
+						Usage: "Struct float and vector3 field technique (raw64, raw32, bst16)",
+					},
+					&cli.StringFlag{
+						Name:  "event",
+						Value: "columnar",
+						Usage: "Event technique (row, columnar)",
+					},
+					&cli.StringFlag{
+						Name:  "time",
+						Value: "bst16",
+						Usage: "Time technique (raw64, raw32, bst16)",
+					},
+				},
+				Usage: "Reports the error lossy encoders introduce into a file",
+				Action: func(c *cli.Context) error {
+					file, err := os.Open(c.String("file"))
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+
+					recording, _, err := rapio.Load(file)
+					if err != nil {
+						return err
+					}
+
+					recording, err = selectRecording(recording, c.String("path"))
+					if err != nil {
+						return err
+					}
+
+					encoders, timeTechnique, err := encoderSettings{
+						position:  c.String("position"),
+						euler:     c.String("euler"),
+						float:     c.String("float"),
+						enum:      c.String("enum"),
+						integer:   c.String("int"),
+						vector2:   c.String("vector2"),
+						skeleton:  c.String("skeleton"),
+						gaze:      c.String("gaze"),
+						floatvec:  c.String("floatvec"),
+						structure: c.String("struct"),
+						event:     c.String("event"),
+						time:      c.String("time"),
+					}.build()
+					if err != nil {
+						return err
+					}
+
+					report, err := audit.Audit(recording, encoders, timeTechnique)
+					if err != nil {
+						return err
+					}
+
+					printAudit(c.App.Writer, report)
+					return nil
+				},
+			},
 			{
 				Name: "to-json",
 				Flags: []cli.Flag{
@@ -81,6 +238,7 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						Required: false,
 						Usage:    "File to turn to JSON",
 					},
+					pathFlag,
 				},
 				Usage: "Transforms a file to json",
 				Action: func(c *cli.Context) error {
@@ -107,6 +265,11 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						return errors.New("can not build json from nil recording")
 					}
 
+					recording, err := selectRecording(recording, c.String("path"))
+					if err != nil {
+						return err
+					}
+
 					return toJson(c.App.Writer, recording, 0)
 				},
 			},
@@ -166,18 +329,412 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						rapStream = file
 					}
 
-					encoders := []encoding.Encoder{
-						event.NewEncoder(),
-						position.NewEncoder(position.Oct24),
-						euler.NewEncoder(euler.Raw16),
-						enum.NewEncoder(),
+					recordingWriter := rapio.NewWriter(defaultEncoders(), true, rapStream, rapio.BST16)
+					_, err = recordingWriter.Write(builtRecording)
+					return err
+				},
+			},
+			{
+				Name: "to-bson",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Required: false,
+						Usage:    "File to turn to BSON",
+					},
+					&cli.StringFlag{
+						Name:     "out",
+						Aliases:  []string{"o"},
+						Required: false,
+						Usage:    ".bson file to write document too",
+					},
+					pathFlag,
+				},
+				Usage: "Transforms a file to a bson document",
+				Action: func(c *cli.Context) error {
+					rapStream := c.App.Reader
+					if c.IsSet("file") {
+						file, err := os.Open(c.String("file"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						rapStream = file
+					}
+
+					recording, _, err := rapio.Load(rapStream)
+					if err != nil {
+						return err
+					}
+
+					recording, err = selectRecording(recording, c.String("path"))
+					if err != nil {
+						return err
+					}
+
+					bsonData, err := parsing.ToBSON(recording)
+					if err != nil {
+						return err
+					}
+
+					bsonStream := c.App.Writer
+					if c.IsSet("out") {
+						file, err := os.Create(c.String("out"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						bsonStream = file
+					}
+
+					_, err = bsonStream.Write(bsonData)
+					return err
+				},
+			},
+			{
+				Name: "from-bson",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "in",
+						Aliases:  []string{"i"},
+						Required: false,
+						Usage:    ".bson file to build recording from",
+					},
+					&cli.StringFlag{
+						Name:     "out",
+						Aliases:  []string{"o"},
+						Required: false,
+						Usage:    "file to write recording too",
+					},
+				},
+				Usage: "Transforms a bson dump to RAP, nesting recordings under a single root if the dump holds more than one document",
+				Action: func(c *cli.Context) error {
+					bsonStream := c.App.Reader
+					if c.IsSet("in") {
+						file, err := os.Open(c.String("in"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						bsonStream = file
+					}
+
+					builtRecording, err := recordingFromBSONDump(bsonStream)
+					if err != nil {
+						return err
 					}
 
-					recordingWriter := rapio.NewWriter(encoders, true, rapStream, rapio.BST16)
+					rapStream := c.App.Writer
+					if c.IsSet("out") {
+						file, err := os.Create(c.String("out"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						rapStream = file
+					}
+
+					recordingWriter := rapio.NewWriter(defaultEncoders(), true, rapStream, rapio.BST16)
 					_, err = recordingWriter.Write(builtRecording)
 					return err
 				},
 			},
+			{
+				Name:  "metadata",
+				Usage: "Utils around recording metadata",
+				Subcommands: []*cli.Command{
+					{
+						Name: "diff",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "from",
+								Required: true,
+								Usage:    "Recording whose metadata to compare from",
+							},
+							&cli.StringFlag{
+								Name:     "to",
+								Required: true,
+								Usage:    "Recording whose metadata to compare to",
+							},
+							pathFlag,
+						},
+						Usage: "Lists every metadata path added, removed or changed between two recordings",
+						Action: func(c *cli.Context) error {
+							blocks := make([]metadata.Block, 2)
+							for i, path := range []string{c.String("from"), c.String("to")} {
+								file, err := os.Open(path)
+								if err != nil {
+									return err
+								}
+								defer file.Close()
+
+								recording, _, err := rapio.Load(file)
+								if err != nil {
+									return err
+								}
+
+								recording, err = selectRecording(recording, c.String("path"))
+								if err != nil {
+									return err
+								}
+								blocks[i] = recording.Metadata()
+							}
+
+							for _, change := range metadata.Diff(blocks[0], blocks[1]) {
+								fmt.Fprintln(c.App.Writer, change.String())
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "query",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Required: true,
+						Usage:    "File to query",
+					},
+					pathFlag,
+					&cli.StringFlag{
+						Name:  "output",
+						Value: "table",
+						Usage: "How to print the results (table, json)",
+					},
+				},
+				ArgsUsage: "<query>",
+				Usage:     "Runs a query against a file, such as \"select count(*) from captures where name == 'Damage'\"",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return errors.New("query must be provided as a single argument")
+					}
+
+					q, err := query.Parse(c.Args().First())
+					if err != nil {
+						return err
+					}
+
+					output := strings.ToLower(c.String("output"))
+					if output != "table" && output != "json" {
+						return fmt.Errorf("unrecognized output: '%s'", c.String("output"))
+					}
+
+					file, err := os.Open(c.String("file"))
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+
+					recording, _, err := rapio.Load(file)
+					if err != nil {
+						return err
+					}
+
+					recording, err = selectRecording(recording, c.String("path"))
+					if err != nil {
+						return err
+					}
+
+					if output == "json" {
+						return printQueryJSON(c.App.Writer, q.Run(recording))
+					}
+					return printQueryTable(c.App.Writer, q.Run(recording))
+				},
+			},
+			{
+				Name: "merge",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "out",
+						Aliases:  []string{"o"},
+						Required: false,
+						Usage:    "file to write merged recording too",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Value: "merged",
+						Usage: "Name of the recording each file is nested under",
+					},
+					&cli.BoolFlag{
+						Name:  "match-ids",
+						Usage: "Combine recordings sharing an ID instead of nesting each file under a new recording",
+					},
+					&cli.Float64SliceFlag{
+						Name:  "offset",
+						Usage: "Seconds to shift the captures of each file by, in the order the files are given",
+					},
+					&cli.BoolFlag{
+						Name:  "align",
+						Usage: "Estimate the offset of each file from events it shares with the first file",
+					},
+					&cli.StringSliceFlag{
+						Name:  "align-event",
+						Usage: "Only align files on events with this name",
+					},
+					&cli.StringFlag{
+						Name:  "ids",
+						Value: "rename",
+						Usage: "What to do with recordings sharing an ID (rename, clear, fail)",
+					},
+					&cli.StringFlag{
+						Name:  "metadata",
+						Value: "first",
+						Usage: "Which value to keep when metadata conflicts (first, last, fail)",
+					},
+				},
+				ArgsUsage: "<file> <file> [file...]",
+				Usage:     "Merges recordings made at the same time into a single recording",
+				Action: func(c *cli.Context) error {
+					merged, err := mergeFiles(c)
+					if err != nil {
+						return err
+					}
+
+					rapStream := c.App.Writer
+					if c.IsSet("out") {
+						file, err := os.Create(c.String("out"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						rapStream = file
+					}
+
+					recordingWriter := rapio.NewLosslessWriter(rapStream)
+					_, err = recordingWriter.Write(merged)
+					return err
+				},
+			},
+			{
+				Name: "concat",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "out",
+						Aliases:  []string{"o"},
+						Required: false,
+						Usage:    "file to write concatenated recording too",
+					},
+					&cli.BoolFlag{
+						Name:  "rebase",
+						Usage: "Shift each file to begin where the one before it ended",
+					},
+					&cli.Float64Flag{
+						Name:  "gap",
+						Usage: "Seconds to leave between files when rebasing",
+					},
+				},
+				ArgsUsage: "<file> <file> [file...]",
+				Usage:     "Appends recordings end to end, joining subjects and collections they share",
+				Action: func(c *cli.Context) error {
+					if c.NArg() < 2 {
+						return errors.New("concat requires at least two files")
+					}
+
+					recordings, err := loadRecordings(c.Args().Slice())
+					if err != nil {
+						return err
+					}
+
+					joined, err := format.Concat(
+						recordings,
+						format.RebaseInConcat(c.Bool("rebase")),
+						format.GapInConcat(c.Float64("gap")),
+					)
+					if err != nil {
+						return err
+					}
+
+					rapStream := c.App.Writer
+					if c.IsSet("out") {
+						file, err := os.Create(c.String("out"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						rapStream = file
+					}
+
+					recordingWriter := rapio.NewLosslessWriter(rapStream)
+					_, err = recordingWriter.Write(joined)
+					return err
+				},
+			},
+			{
+				Name: "retime",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Required: false,
+						Usage:    "File to retime",
+					},
+					&cli.StringFlag{
+						Name:     "out",
+						Aliases:  []string{"o"},
+						Required: false,
+						Usage:    "file to write retimed recording too",
+					},
+					&cli.StringSliceFlag{
+						Name:  "remove-pause",
+						Usage: "Cut a pause out of the timeline, given as start:end",
+					},
+					&cli.StringSliceFlag{
+						Name:  "warp",
+						Usage: "Move a time to another, given as from:to, stretching the times between",
+					},
+					&cli.BoolFlag{
+						Name:  "rezero",
+						Usage: "Shift time so the recording begins at zero",
+					},
+					&cli.Float64Flag{
+						Name:  "scale",
+						Usage: "Multiply every time by this factor",
+					},
+					&cli.Float64Flag{
+						Name:  "shift",
+						Usage: "Seconds to add to every time",
+					},
+				},
+				Usage: "Shifts, scales, re-zeros or warps every time in a recording, applied in the order pauses, warps, rezero, scale then shift",
+				Action: func(c *cli.Context) error {
+					rapStream := c.App.Reader
+					if c.IsSet("file") {
+						file, err := os.Open(c.String("file"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						rapStream = file
+					}
+
+					recording, _, err := rapio.Load(rapStream)
+					if err != nil {
+						return err
+					}
+
+					retimed, err := retimeRecording(c, recording)
+					if err != nil {
+						return err
+					}
+
+					outStream := c.App.Writer
+					if c.IsSet("out") {
+						file, err := os.Create(c.String("out"))
+						if err != nil {
+							return err
+						}
+						defer file.Close()
+						outStream = file
+					}
+
+					recordingWriter := rapio.NewLosslessWriter(outStream)
+					_, err = recordingWriter.Write(retimed)
+					return err
+				},
+			},
 			{
 				Name: "upgrade",
 				Flags: []cli.Flag{
@@ -201,14 +758,7 @@ func BuildApp(in io.Reader, out io.Writer, errOut io.Writer) *cli.App {
 						return err
 					}
 
-					encoders := []encoding.Encoder{
-						event.NewEncoder(),
-						position.NewEncoder(position.Oct24),
-						euler.NewEncoder(euler.Raw16),
-						enum.NewEncoder(),
-					}
-
-					recordingWriter := rapio.NewWriter(encoders, true, c.App.Writer, rapio.BST16)
+					recordingWriter := rapio.NewWriter(defaultEncoders(), true, c.App.Writer, rapio.BST16)
 					_, err = recordingWriter.Write(recording)
 					return err
 				},
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/recolude/rap/format"
+	rapio "github.com/recolude/rap/format/io"
+	"github.com/urfave/cli/v2"
+)
+
+// loadRecordings loads every file provided, in order.
+func loadRecordings(paths []string) ([]format.Recording, error) {
+	recordings := make([]format.Recording, len(paths))
+	for i, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		recordings[i], _, err = rapio.Load(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return recordings, nil
+}
+
+func mergeOptions(c *cli.Context, files int) ([]format.MergeOption, error) {
+	options := []format.MergeOption{
+		format.NameOfMerge(c.String("name")),
+		format.MatchIDsInMerge(c.Bool("match-ids")),
+	}
+
+	offsets := c.Float64Slice("offset")
+	if len(offsets) > files {
+		return nil, fmt.Errorf("%d offsets were provided but there are only %d files", len(offsets), files)
+	}
+	for i, offset := range offsets {
+		options = append(options, format.OffsetInMerge(i, offset))
+	}
+
+	if c.Bool("align") || c.IsSet("align-event") {
+		options = append(options, format.AlignEventsInMerge(c.StringSlice("align-event")...))
+	}
+
+	switch strings.ToLower(c.String("ids")) {
+	case "rename":
+		options = append(options, format.IDCollisionsInMerge(format.RenameCollidingIDs))
+	case "clear":
+		options = append(options, format.IDCollisionsInMerge(format.ClearCollidingIDs))
+	case "fail":
+		options = append(options, format.IDCollisionsInMerge(format.FailOnCollidingIDs))
+	default:
+		return nil, fmt.Errorf("unrecognized id collision resolution: '%s'", c.String("ids"))
+	}
+
+	switch strings.ToLower(c.String("metadata")) {
+	case "first":
+		options = append(options, format.MetadataInMerge(format.PreferFirstMetadata))
+	case "last":
+		options = append(options, format.MetadataInMerge(format.PreferLastMetadata))
+	case "fail":
+		options = append(options, format.MetadataInMerge(format.FailOnMetadataConflicts))
+	default:
+		return nil, fmt.Errorf("unrecognized metadata strategy: '%s'", c.String("metadata"))
+	}
+
+	return options, nil
+}
+
+func mergeFiles(c *cli.Context) (format.Recording, error) {
+	if c.NArg() < 2 {
+		return nil, errors.New("merge requires at least two files")
+	}
+
+	options, err := mergeOptions(c, c.NArg())
+	if err != nil {
+		return nil, err
+	}
+
+	recordings, err := loadRecordings(c.Args().Slice())
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Merge(recordings, options...)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func writeClientRecording(t *testing.T, path, id string, start float64) {
+	file, err := os.Create(path)
+	if assert.NoError(t, err) == false {
+		return
+	}
+	defer file.Close()
+
+	_, err = io.NewRecoludeWriter(file).Write(format.NewBuilder().
+		SetID(id).
+		SetName("session").
+		AddCollection(event.NewCollection("Events", []event.Capture{
+			event.NewCapture(start+1, "RoundStart", metadata.EmptyBlock()),
+			event.NewCapture(start+3, "RoundEnd", metadata.EmptyBlock()),
+		})).
+		Build())
+	assert.NoError(t, err)
+}
+
+func Test_Merge(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+
+	aPath := filepath.Join(t.TempDir(), "a.rap")
+	writeClientRecording(t, aPath, "client", 0)
+	bPath := filepath.Join(t.TempDir(), "b.rap")
+	writeClientRecording(t, bPath, "client", 10)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "merge", "--align", "--name", "match", aPath, bPath})
+	merged, _, loadErr := io.Load(&appOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, loadErr)
+	assert.Equal(t, "", appErrOut.String())
+	if assert.NotNil(t, merged) && assert.Len(t, merged.Recordings(), 2) {
+		assert.Equal(t, "match", merged.Name())
+		assert.Equal(t, "client", merged.Recordings()[0].ID())
+		assert.Equal(t, "client-2", merged.Recordings()[1].ID())
+		assert.InDelta(t, 1, merged.Recordings()[1].CaptureCollections()[0].Start(), 0.01)
+	}
+}
+
+func Test_Merge_FloatCollections(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &bytes.Buffer{})
+
+	paths := []string{filepath.Join(t.TempDir(), "a.rap"), filepath.Join(t.TempDir(), "b.rap")}
+	for _, path := range paths {
+		file, err := os.Create(path)
+		if assert.NoError(t, err) == false {
+			return
+		}
+		_, err = io.NewRecoludeWriter(file).Write(format.NewBuilder().
+			SetName("session").
+			AddCollection(float.NewCollection("Health", []float.Capture{float.NewCapture(1, 100)})).
+			Build())
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+	}
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "merge", paths[0], paths[1]})
+	merged, _, loadErr := io.Load(&appOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, loadErr)
+	if assert.NotNil(t, merged) && assert.Len(t, merged.Recordings(), 2) {
+		assert.Equal(t, "recolude.float", merged.Recordings()[1].CaptureCollections()[0].Signature())
+	}
+}
+
+func Test_Merge_BadArguments(t *testing.T) {
+	// ARRANGE ================================================================
+	aPath := filepath.Join(t.TempDir(), "a.rap")
+	writeClientRecording(t, aPath, "client", 0)
+	newApp := func() *cli.App {
+		return BuildApp(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	}
+
+	// ACT ====================================================================
+	tooFewErr := newApp().Run([]string{"rap-cli", "merge", aPath})
+	offsetErr := newApp().Run([]string{"rap-cli", "merge", "--offset", "1", "--offset", "2", "--offset", "3", aPath, aPath})
+	idsErr := newApp().Run([]string{"rap-cli", "merge", "--ids", "shuffle", aPath, aPath})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, tooFewErr, "merge requires at least two files")
+	assert.EqualError(t, offsetErr, "3 offsets were provided but there are only 2 files")
+	assert.EqualError(t, idsErr, "unrecognized id collision resolution: 'shuffle'")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMetadataRecording(t *testing.T, path string, block metadata.Block) {
+	file, err := os.Create(path)
+	if assert.NoError(t, err) == false {
+		return
+	}
+	defer file.Close()
+
+	_, err = io.NewRecoludeWriter(file).Write(format.NewRecording("", "session", nil, nil, block, nil, nil))
+	assert.NoError(t, err)
+}
+
+func Test_MetadataDiff(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+
+	fromPath := filepath.Join(t.TempDir(), "from.rap")
+	writeMetadataRecording(t, fromPath, metadata.NewBlock(map[string]metadata.Property{
+		"level": metadata.NewStringProperty("castle"),
+		"score": metadata.NewIntProperty(10),
+	}))
+
+	toPath := filepath.Join(t.TempDir(), "to.rap")
+	writeMetadataRecording(t, toPath, metadata.NewBlock(map[string]metadata.Property{
+		"level": metadata.NewStringProperty("forest"),
+		"mode":  metadata.NewStringProperty("coop"),
+	}))
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "metadata", "diff", "--from", fromPath, "--to", toPath})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "", appErrOut.String())
+	assert.Equal(t, "~ /level: castle -> forest\n- /score: 10\n+ /mode: coop\n", appOut.String())
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/recolude/rap/format"
+	"github.com/urfave/cli/v2"
+)
+
+var pathFlag = &cli.StringFlag{
+	Name:    "path",
+	Aliases: []string{"p"},
+	Usage:   "Only operate on the recordings or collections matching the path, such as \"*/Enemies/enemy-12#Position\"",
+}
+
+// selectRecording narrows the recording down to what the path selects. A
+// path selecting a single recording resolves to that recording. Anything
+// else is gathered into a new recording, with selected collections kept
+// alongside the recording they came from. Selections nested within a selected
+// recording are already part of it, and so are left out.
+func selectRecording(rec format.Recording, path string) (format.Recording, error) {
+	if path == "" {
+		return rec, nil
+	}
+
+	selections, err := format.Select(rec, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("nothing in recording matches path '%s'", path)
+	}
+
+	// Selections arrive in walk order, so ancestors are always seen before
+	// anything nested within them
+	selectedPaths := make([]string, 0)
+	nested := func(selection format.Selection) bool {
+		for _, selectedPath := range selectedPaths {
+			if strings.HasPrefix(selection.Path, selectedPath+"/") || (selection.Collection != nil && selection.Path == selectedPath) {
+				return true
+			}
+		}
+		return false
+	}
+
+	children := make([]*format.Builder, 0)
+	recordings := make([]format.Recording, 0)
+	lastPath := ""
+	grouping := false
+	for _, selection := range selections {
+		if nested(selection) {
+			continue
+		}
+
+		if selection.Collection == nil {
+			selectedPaths = append(selectedPaths, selection.Path)
+			children = append(children, format.BuilderFrom(selection.Recording))
+			recordings = append(recordings, selection.Recording)
+			grouping = false
+			continue
+		}
+
+		if !grouping || selection.Path != lastPath {
+			children = append(children, format.NewBuilder().
+				SetID(selection.Recording.ID()).
+				SetName(selection.Path).
+				SetMetadata(selection.Recording.Metadata()))
+			lastPath = selection.Path
+			grouping = true
+		}
+		children[len(children)-1].AddCollection(selection.Collection)
+	}
+
+	if len(children) == 1 && len(recordings) == 1 {
+		return recordings[0], nil
+	}
+
+	if len(children) == 1 {
+		return children[0].Build(), nil
+	}
+
+	selected := format.NewBuilder().SetName(path)
+	for _, child := range children {
+		selected.AddChild(child.Build())
+	}
+	return selected.Build(), nil
+}
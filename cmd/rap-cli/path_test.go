@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func pathTestRecording() format.Recording {
+	enemy := func(name string) format.Recording {
+		return format.NewBuilder().
+			SetName(name).
+			AddCollection(position.NewCollection("Position", nil), position.NewCollection("Rotation", nil)).
+			Build()
+	}
+
+	return format.NewBuilder().
+		SetName("session").
+		AddChild(enemy("enemy-1"), enemy("enemy-2")).
+		Build()
+}
+
+func Test_SelectRecording(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := pathTestRecording()
+
+	// ACT ====================================================================
+	whole, wholeErr := selectRecording(rec, "")
+	single, singleErr := selectRecording(rec, "session/enemy-2")
+	many, manyErr := selectRecording(rec, "session/*")
+	collections, collectionsErr := selectRecording(rec, "session/enemy-1#Rot*")
+	allCollections, allCollectionsErr := selectRecording(rec, "session/enemy-1#*")
+	everything, everythingErr := selectRecording(rec, "**")
+	subtrees, subtreesErr := selectRecording(rec, "session/*/**")
+	_, missingErr := selectRecording(rec, "session/boss")
+
+	// ASSERT =================================================================
+	assert.NoError(t, wholeErr)
+	assert.Equal(t, rec, whole)
+
+	assert.NoError(t, singleErr)
+	assert.Equal(t, "enemy-2", single.Name())
+
+	assert.NoError(t, manyErr)
+	assert.Equal(t, "session/*", many.Name())
+	if assert.Len(t, many.Recordings(), 2) {
+		assert.Equal(t, "enemy-1", many.Recordings()[0].Name())
+		assert.Equal(t, "enemy-2", many.Recordings()[1].Name())
+	}
+
+	assert.NoError(t, collectionsErr)
+	assert.Equal(t, "session/enemy-1", collections.Name())
+	assert.Empty(t, collections.Recordings())
+	if assert.Len(t, collections.CaptureCollections(), 1) {
+		assert.Equal(t, "Rotation", collections.CaptureCollections()[0].Name())
+	}
+
+	assert.NoError(t, allCollectionsErr)
+	assert.Equal(t, "session/enemy-1", allCollections.Name())
+	if assert.Len(t, allCollections.CaptureCollections(), 2) {
+		assert.Equal(t, "Position", allCollections.CaptureCollections()[0].Name())
+		assert.Equal(t, "Rotation", allCollections.CaptureCollections()[1].Name())
+	}
+
+	assert.NoError(t, everythingErr)
+	assert.Equal(t, rec, everything)
+
+	assert.NoError(t, subtreesErr)
+	if assert.Len(t, subtrees.Recordings(), 2) {
+		assert.Equal(t, "enemy-1", subtrees.Recordings()[0].Name())
+		assert.Equal(t, "enemy-2", subtrees.Recordings()[1].Name())
+	}
+
+	assert.EqualError(t, missingErr, "nothing in recording matches path 'session/boss'")
+}
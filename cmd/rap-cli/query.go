@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/recolude/rap/format/query"
+)
+
+func queryCellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+func printQueryTable(out io.Writer, result query.Result) error {
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(result.Columns, "\t"))
+	for _, row := range result.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = queryCellString(value)
+		}
+		fmt.Fprintln(table, strings.Join(cells, "\t"))
+	}
+	return table.Flush()
+}
+
+// printQueryJSON writes the rows as an array of objects, keeping the keys of
+// each object in the order the columns were selected. JSON has no way to
+// represent NaN or infinity, so they're written as null. Nothing is written
+// unless every row could be encoded.
+func printQueryJSON(out io.Writer, result query.Result) error {
+	buf := bytes.Buffer{}
+	buf.WriteString("[")
+	for rowIndex, row := range result.Rows {
+		if rowIndex > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for i, value := range row {
+			if number, ok := value.(float64); ok && (math.IsNaN(number) || math.IsInf(number, 0)) {
+				value = nil
+			}
+
+			key, err := json.Marshal(result.Columns[i])
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s: %s", key, data)
+		}
+		buf.WriteString("}")
+	}
+	if len(result.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := buf.WriteTo(out)
+	return err
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/format/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeQueryRecording(t *testing.T) string {
+	filePath := filepath.Join(t.TempDir(), "query.rap")
+	file, err := os.Create(filePath)
+	if assert.NoError(t, err) == false {
+		return filePath
+	}
+	defer file.Close()
+
+	damage := func(time float64, amount int) event.Capture {
+		return event.NewCapture(time, "Damage", metadata.NewBlock(map[string]metadata.Property{
+			"amount": metadata.NewIntProperty(amount),
+		}))
+	}
+
+	_, err = io.NewRecoludeWriter(file).Write(format.NewBuilder().
+		SetName("match").
+		AddChild(
+			format.NewBuilder().
+				SetName("alice").
+				AddCollection(event.NewCollection("Events", []event.Capture{damage(1, 60), damage(2, 20)})).
+				Build(),
+			format.NewBuilder().
+				SetName("bob").
+				AddCollection(event.NewCollection("Events", []event.Capture{damage(3, 90)})).
+				Build(),
+		).
+		Build())
+	assert.NoError(t, err)
+	return filePath
+}
+
+func Test_Query_Table(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+	filePath := writeQueryRecording(t)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "query", "-f", filePath, "select recording.name, time, metadata.amount from captures where metadata.amount > 50"})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "", appErrOut.String())
+	assert.Equal(t, "recording.name  time  metadata.amount\nalice           1     60\nbob             3     90\n", appOut.String())
+}
+
+func Test_Query_JSON(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+	filePath := writeQueryRecording(t)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "query", "-f", filePath, "-p", "match/alice", "--output", "json", "select count(*), avg(metadata.amount) from captures"})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "", appErrOut.String())
+	assert.Equal(t, "[\n  {\"count(*)\": 2, \"avg(metadata.amount)\": 40}\n]\n", appOut.String())
+}
+
+func Test_PrintQueryJSON_NonFiniteValues(t *testing.T) {
+	// ARRANGE ================================================================
+	out := bytes.Buffer{}
+	result := query.Result{
+		Columns: []string{"value", "name"},
+		Rows: [][]interface{}{
+			{math.NaN(), "a"},
+			{math.Inf(1), "b"},
+			{1.5, "c"},
+		},
+	}
+
+	// ACT ====================================================================
+	err := printQueryJSON(&out, result)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  {\"value\": null, \"name\": \"a\"},\n  {\"value\": null, \"name\": \"b\"},\n  {\"value\": 1.5, \"name\": \"c\"}\n]\n", out.String())
+}
+
+func Test_Query_BadQuery(t *testing.T) {
+	// ARRANGE ================================================================
+	app := BuildApp(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	filePath := writeQueryRecording(t)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "query", "-f", filePath, "select colour from recordings"})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "unknown field 'colour' at position 7")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/recolude/rap/format"
+	"github.com/urfave/cli/v2"
+)
+
+func parseTimePair(flag, value string) (float64, float64, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%s must be two times separated by ':', such as 10:12.5, not '%s'", flag, value)
+	}
+
+	first, firstErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	second, secondErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if firstErr != nil || secondErr != nil {
+		return 0, 0, fmt.Errorf("%s must be two times separated by ':', such as 10:12.5, not '%s'", flag, value)
+	}
+	return first, second, nil
+}
+
+// retimeRecording applies the time flags in a fixed order: pauses are removed
+// first, then warps are applied, then the recording is re-zeroed, scaled and
+// finally shifted.
+func retimeRecording(c *cli.Context, rec format.Recording) (format.Recording, error) {
+	pauses := make([]format.Pause, 0)
+	for _, value := range c.StringSlice("remove-pause") {
+		start, end, err := parseTimePair("remove-pause", value)
+		if err != nil {
+			return nil, err
+		}
+		pauses = append(pauses, format.Pause{Start: start, End: end})
+	}
+
+	points := make([]format.WarpPoint, 0)
+	for _, value := range c.StringSlice("warp") {
+		from, to, err := parseTimePair("warp", value)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, format.WarpPoint{From: from, To: to})
+	}
+
+	if len(pauses) > 0 {
+		mapping, err := format.RemovePauses(pauses...)
+		if err != nil {
+			return nil, err
+		}
+		rec, err = format.Retime(rec, mapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(points) > 0 {
+		mapping, err := format.Warp(points...)
+		if err != nil {
+			return nil, err
+		}
+		rec, err = format.Retime(rec, mapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mapping := format.Shift(0)
+	if c.Bool("rezero") {
+		mapping = format.Rezero(rec)
+	}
+	if c.IsSet("scale") {
+		scale, err := format.Scale(c.Float64("scale"))
+		if err != nil {
+			return nil, err
+		}
+		mapping = mapping.Then(scale)
+	}
+	if c.IsSet("shift") {
+		mapping = mapping.Then(format.Shift(c.Float64("shift")))
+	}
+
+	return format.Retime(rec, mapping)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Retime(t *testing.T) {
+	// ARRANGE ================================================================
+	appOut := bytes.Buffer{}
+	appErrOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &appErrOut)
+
+	filePath := filepath.Join(t.TempDir(), "client.rap")
+	writeClientRecording(t, filePath, "client", 100)
+
+	// ACT ====================================================================
+	err := app.Run([]string{"rap-cli", "retime", "-f", filePath, "--remove-pause", "102:102.5", "--rezero", "--scale", "2", "--shift", "1"})
+	retimed, _, loadErr := io.Load(&appOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, loadErr)
+	assert.Equal(t, "", appErrOut.String())
+	if assert.NotNil(t, retimed) && assert.Len(t, retimed.CaptureCollections(), 1) {
+		events := retimed.CaptureCollections()[0]
+		assert.InDelta(t, 1, events.CaptureAt(0).Time(), 0.01)
+		assert.InDelta(t, 4, events.CaptureAt(1).Time(), 0.01)
+	}
+}
+
+func Test_Retime_BadFlags(t *testing.T) {
+	// ARRANGE ================================================================
+	filePath := filepath.Join(t.TempDir(), "client.rap")
+	writeClientRecording(t, filePath, "client", 0)
+
+	// ACT ====================================================================
+	scaleErr := BuildApp(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}).Run([]string{"rap-cli", "retime", "-f", filePath, "--scale", "0"})
+	warpErr := BuildApp(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}).Run([]string{"rap-cli", "retime", "-f", filePath, "--warp", "10"})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, scaleErr, "scale must be greater than zero")
+	assert.EqualError(t, warpErr, "warp must be two times separated by ':', such as 10:12.5, not '10'")
+}
+
+func Test_Retime_ZeroShiftKeepsValues(t *testing.T) {
+	// ARRANGE ================================================================
+	positions := []position.Capture{
+		position.NewCapture(0.1234567, 123.456789, -987.654321, 0.000123),
+		position.NewCapture(1234.5678, -0.001234, 4567.891, 12.3456789),
+	}
+	floats := []float.Capture{
+		float.NewCapture(0.1234567, 98.7654321),
+		float.NewCapture(1234.5678, -0.00123456),
+	}
+
+	filePath := filepath.Join(t.TempDir(), "precise.rap")
+	file, err := os.Create(filePath)
+	if assert.NoError(t, err) == false {
+		return
+	}
+	_, err = io.NewLosslessWriter(file).Write(format.NewBuilder().
+		SetName("precise").
+		AddCollection(position.NewCollection("Position", positions), float.NewCollection("Health", floats)).
+		Build())
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	appOut := bytes.Buffer{}
+	app := BuildApp(&bytes.Buffer{}, &appOut, &bytes.Buffer{})
+
+	// ACT ====================================================================
+	runErr := app.Run([]string{"rap-cli", "retime", "-f", filePath, "--shift", "0"})
+	retimed, _, loadErr := io.Load(&appOut)
+
+	// ASSERT =================================================================
+	assert.NoError(t, runErr)
+	assert.NoError(t, loadErr)
+	if assert.NotNil(t, retimed) == false || assert.Len(t, retimed.CaptureCollections(), 2) == false {
+		return
+	}
+
+	for _, collection := range retimed.CaptureCollections() {
+		switch collection.Signature() {
+		case "recolude.position":
+			for i, capture := range collection.Captures() {
+				assert.InEpsilon(t, positions[i].Time(), capture.Time(), 1e-6)
+				assert.InEpsilon(t, positions[i].Position().X(), capture.(position.Capture).Position().X(), 1e-6)
+				assert.InEpsilon(t, positions[i].Position().Y(), capture.(position.Capture).Position().Y(), 1e-6)
+				assert.InEpsilon(t, positions[i].Position().Z(), capture.(position.Capture).Position().Z(), 1e-6)
+			}
+		case "recolude.float":
+			for i, capture := range collection.Captures() {
+				assert.InEpsilon(t, floats[i].Time(), capture.Time(), 1e-6)
+				assert.InEpsilon(t, floats[i].Value(), capture.(float.Capture).Value(), 1e-6)
+			}
+		default:
+			t.Errorf("unexpected collection %s", collection.Signature())
+		}
+	}
+}
@@ -5,10 +5,18 @@ import (
 	"io"
 
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
 	"github.com/recolude/rap/format/collection/enum"
 	"github.com/recolude/rap/format/collection/euler"
 	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
 	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
 )
 
 type summary struct {
@@ -16,39 +24,52 @@ type summary struct {
 	eventCaptureCount    int
 	eulerCaptureCount    int
 	enumCaptureCount     int
+	intCaptureCount      int
+	vector2CaptureCount  int
+	boolCaptureCount     int
+	spanCaptureCount     int
+	skeletonCaptureCount int
+	gazeCaptureCount     int
+	floatvecCaptureCount int
+	structCaptureCount   int
 	otherCaptureCount    int
 }
 
-func (s summary) Combine(other summary) summary {
-	return summary{
-		positionCaptureCount: s.positionCaptureCount + other.positionCaptureCount,
-		eventCaptureCount:    s.eventCaptureCount + other.eventCaptureCount,
-		eulerCaptureCount:    s.eulerCaptureCount + other.eulerCaptureCount,
-		enumCaptureCount:     s.enumCaptureCount + other.enumCaptureCount,
-		otherCaptureCount:    s.otherCaptureCount + other.otherCaptureCount,
-	}
-}
-
 func summarize(recording format.Recording) summary {
 	curSummary := summary{}
-	for _, collection := range recording.CaptureCollections() {
-		switch v := collection.(type) {
-		case event.Collection:
-			curSummary.eventCaptureCount += v.Length()
-		case position.Collection:
-			curSummary.positionCaptureCount += v.Length()
-		case enum.Collection:
-			curSummary.enumCaptureCount += v.Length()
-		case euler.Collection:
-			curSummary.eulerCaptureCount += v.Length()
-		default:
-			curSummary.otherCaptureCount += collection.Length()
+	format.Walk(recording, func(rec format.Recording, path string, depth int) error {
+		for _, collection := range rec.CaptureCollections() {
+			switch v := collection.(type) {
+			case event.Collection:
+				curSummary.eventCaptureCount += v.Length()
+			case position.Collection:
+				curSummary.positionCaptureCount += v.Length()
+			case enum.Collection:
+				curSummary.enumCaptureCount += v.Length()
+			case euler.Collection:
+				curSummary.eulerCaptureCount += v.Length()
+			case integer.Collection:
+				curSummary.intCaptureCount += v.Length()
+			case vector2.Collection:
+				curSummary.vector2CaptureCount += v.Length()
+			case boolean.Collection:
+				curSummary.boolCaptureCount += v.Length()
+			case span.Collection:
+				curSummary.spanCaptureCount += v.Length()
+			case skeleton.Collection:
+				curSummary.skeletonCaptureCount += v.Length()
+			case gaze.Collection:
+				curSummary.gazeCaptureCount += v.Length()
+			case floatvec.Collection:
+				curSummary.floatvecCaptureCount += v.Length()
+			case structure.Collection:
+				curSummary.structCaptureCount += v.Length()
+			default:
+				curSummary.otherCaptureCount += collection.Length()
+			}
 		}
-	}
-
-	for _, rec := range recording.Recordings() {
-		curSummary = curSummary.Combine(summarize(rec))
-	}
+		return nil
+	})
 
 	return curSummary
 }
@@ -83,5 +104,13 @@ func printSummary(out io.Writer, recording format.Recording, size int64) {
 	fmt.Fprintf(out, "Total Euler Captures:    %d\n", recSummary.eulerCaptureCount)
 	fmt.Fprintf(out, "Total Event Captures:    %d\n", recSummary.eventCaptureCount)
 	fmt.Fprintf(out, "Total Enum Captures:     %d\n", recSummary.enumCaptureCount)
+	fmt.Fprintf(out, "Total Int Captures:      %d\n", recSummary.intCaptureCount)
+	fmt.Fprintf(out, "Total Vector2 Captures:  %d\n", recSummary.vector2CaptureCount)
+	fmt.Fprintf(out, "Total Bool Captures:     %d\n", recSummary.boolCaptureCount)
+	fmt.Fprintf(out, "Total Span Captures:     %d\n", recSummary.spanCaptureCount)
+	fmt.Fprintf(out, "Total Skeleton Captures: %d\n", recSummary.skeletonCaptureCount)
+	fmt.Fprintf(out, "Total Gaze Captures:     %d\n", recSummary.gazeCaptureCount)
+	fmt.Fprintf(out, "Total FloatVec Captures: %d\n", recSummary.floatvecCaptureCount)
+	fmt.Fprintf(out, "Total Struct Captures:   %d\n", recSummary.structCaptureCount)
 	fmt.Fprintf(out, "Total Other Captures:    %d\n", recSummary.otherCaptureCount)
 }
@@ -90,6 +90,14 @@ func Test_Summarize(t *testing.T) {
 	answerBuilder.WriteString("Total Euler Captures:    0\n")
 	answerBuilder.WriteString("Total Event Captures:    0\n")
 	answerBuilder.WriteString("Total Enum Captures:     0\n")
+	answerBuilder.WriteString("Total Int Captures:      0\n")
+	answerBuilder.WriteString("Total Vector2 Captures:  0\n")
+	answerBuilder.WriteString("Total Bool Captures:     0\n")
+	answerBuilder.WriteString("Total Span Captures:     0\n")
+	answerBuilder.WriteString("Total Skeleton Captures: 0\n")
+	answerBuilder.WriteString("Total Gaze Captures:     0\n")
+	answerBuilder.WriteString("Total FloatVec Captures: 0\n")
+	answerBuilder.WriteString("Total Struct Captures:   0\n")
 	answerBuilder.WriteString("Total Other Captures:    0\n")
 
 	out := bytes.Buffer{}
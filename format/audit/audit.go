@@ -0,0 +1,347 @@
+package audit
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/euler"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
+	"github.com/recolude/rap/format/encoding"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+)
+
+// ErrorStats summarizes the difference between original and decoded values
+// across all captures of a stream.
+type ErrorStats struct {
+	Max  float64
+	Mean float64
+	RMS  float64
+
+	// WorstIndex is the index of the capture that had the largest error, or
+	// -1 if the stream had no captures.
+	WorstIndex int
+}
+
+// StreamReport details how much precision a single capture collection lost
+// after being written and read back.
+type StreamReport struct {
+	// Path is the names of all recordings leading to the stream, joined by
+	// "/"
+	Path      string
+	Name      string
+	Signature string
+	Captures  int
+
+	// Bytes is the compressed size of the stream when written to a file by
+	// itself, minus the cost of an empty recording.
+	Bytes           int
+	BytesPerCapture float64
+
+	Value ErrorStats
+	Time  ErrorStats
+}
+
+// Report is the result of auditing a recording with a set of encoders.
+type Report struct {
+	TotalBytes int
+	Streams    []StreamReport
+}
+
+type accumulator struct {
+	max        float64
+	sum        float64
+	sumSquared float64
+	worstIndex int
+	count      int
+}
+
+func newAccumulator() accumulator {
+	return accumulator{worstIndex: -1}
+}
+
+func (a *accumulator) add(index int, err float64) {
+	if a.worstIndex == -1 || err > a.max {
+		a.max = err
+		a.worstIndex = index
+	}
+	a.sum += err
+	a.sumSquared += err * err
+	a.count++
+}
+
+func (a accumulator) stats() ErrorStats {
+	if a.count == 0 {
+		return ErrorStats{WorstIndex: -1}
+	}
+	return ErrorStats{
+		Max:        a.max,
+		Mean:       a.sum / float64(a.count),
+		RMS:        math.Sqrt(a.sumSquared / float64(a.count)),
+		WorstIndex: a.worstIndex,
+	}
+}
+
+func wrappedAngleDifference(a, b float64) float64 {
+	diff := math.Mod(math.Abs(a-b), 360)
+	if diff > 180 {
+		return 360 - diff
+	}
+	return diff
+}
+
+func metadataEqual(a, b metadata.Block) bool {
+	if len(a.Mapping()) != len(b.Mapping()) {
+		return false
+	}
+	for key, val := range a.Mapping() {
+		other, ok := b.Mapping()[key]
+		if !ok || !reflect.DeepEqual(val, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// captureError measures how far the decoded capture strayed from the
+// original. Positions report distance, rotations and gaze directions report
+// the angular distance in degrees, and discrete types report 1 on mismatch.
+// Skeletons are measured by skeletonError instead, as they need their
+// collection's definition.
+func captureError(expected, actual format.Capture) float64 {
+	switch e := expected.(type) {
+	case position.Capture:
+		a := actual.(position.Capture)
+		return e.Position().Distance(a.Position())
+
+	case vector2.Capture:
+		return e.Value().Distance(actual.(vector2.Capture).Value())
+
+	case euler.Capture:
+		a := actual.(euler.Capture)
+		return vector.NewVector3(
+			wrappedAngleDifference(e.EulerZXY().X(), a.EulerZXY().X()),
+			wrappedAngleDifference(e.EulerZXY().Y(), a.EulerZXY().Y()),
+			wrappedAngleDifference(e.EulerZXY().Z(), a.EulerZXY().Z()),
+		).Length()
+
+	case gaze.Capture:
+		dot := e.Direction().Dot(actual.(gaze.Capture).Direction())
+		return math.Acos(math.Max(-1, math.Min(1, dot))) * 180.0 / math.Pi
+
+	case float.Capture:
+		return math.Abs(e.Value() - actual.(float.Capture).Value())
+
+	case floatvec.Capture:
+		worst := 0.0
+		a := actual.(floatvec.Capture)
+		for i, v := range e.Values() {
+			worst = math.Max(worst, math.Abs(v-a.Values()[i]))
+		}
+		return worst
+
+	case structure.Capture:
+		return structError(e, actual.(structure.Capture))
+
+	case integer.Capture:
+		return math.Abs(float64(e.Value() - actual.(integer.Capture).Value()))
+
+	case enum.Capture:
+		if e.Value() != actual.(enum.Capture).Value() {
+			return 1
+		}
+		return 0
+
+	case boolean.Capture:
+		if e.Value() != actual.(boolean.Capture).Value() {
+			return 1
+		}
+		return 0
+
+	case event.Capture:
+		a := actual.(event.Capture)
+		if e.Name() != a.Name() || !metadataEqual(e.Metadata(), a.Metadata()) {
+			return 1
+		}
+		return 0
+
+	case span.Capture:
+		a := actual.(span.Capture)
+		if e.Label() != a.Label() || e.Duration() != a.Duration() || !metadataEqual(e.Metadata(), a.Metadata()) {
+			return 1
+		}
+		return 0
+	}
+
+	if expected.String() != actual.String() {
+		return 1
+	}
+	return 0
+}
+
+// skeletonError measures the furthest any joint strayed from its original
+// world space position.
+func skeletonError(definition skeleton.Skeleton) func(expected, actual format.Capture) float64 {
+	return func(expected, actual format.Capture) float64 {
+		expectedPositions := definition.WorldPositions(expected.(skeleton.Capture))
+		actualPositions := definition.WorldPositions(actual.(skeleton.Capture))
+		worst := expected.(skeleton.Capture).Root().Distance(actual.(skeleton.Capture).Root())
+		for i := range expectedPositions {
+			worst = math.Max(worst, expectedPositions[i].Distance(actualPositions[i]))
+		}
+		return worst
+	}
+}
+
+func writeToBuffer(rec format.Recording, encoders []encoding.Encoder, timeStorageTechnique io.TimeStorageTechnique) (*bytes.Buffer, error) {
+	buf := &bytes.Buffer{}
+	_, err := io.NewWriter(encoders, true, buf, timeStorageTechnique).Write(rec)
+	return buf, err
+}
+
+type auditor struct {
+	encoders             []encoding.Encoder
+	timeStorageTechnique io.TimeStorageTechnique
+	emptySize            int
+}
+
+func (a auditor) streamSize(collection format.CaptureCollection) (int, error) {
+	buf, err := writeToBuffer(
+		format.NewRecording("", "", []format.CaptureCollection{collection}, nil, metadata.EmptyBlock(), nil, nil),
+		a.encoders,
+		a.timeStorageTechnique,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return buf.Len() - a.emptySize, nil
+}
+
+func (a auditor) compareRecordings(path string, original, decoded format.Recording) ([]StreamReport, error) {
+	reports := make([]StreamReport, 0, len(original.CaptureCollections()))
+
+	for collectionIndex, expected := range original.CaptureCollections() {
+		actual := decoded.CaptureCollections()[collectionIndex]
+
+		size, err := a.streamSize(expected)
+		if err != nil {
+			return nil, err
+		}
+
+		errorFunc := captureError
+		if skeletonCollection, ok := expected.(skeleton.Collection); ok {
+			errorFunc = skeletonError(skeletonCollection.Skeleton())
+		}
+
+		valueErr := newAccumulator()
+		timeErr := newAccumulator()
+		for i := 0; i < expected.Length() && i < actual.Length(); i++ {
+			valueErr.add(i, errorFunc(expected.CaptureAt(i), actual.CaptureAt(i)))
+			timeErr.add(i, math.Abs(expected.CaptureAt(i).Time()-actual.CaptureAt(i).Time()))
+		}
+
+		report := StreamReport{
+			Path:      path,
+			Name:      expected.Name(),
+			Signature: expected.Signature(),
+			Captures:  expected.Length(),
+			Bytes:     size,
+			Value:     valueErr.stats(),
+			Time:      timeErr.stats(),
+		}
+		if expected.Length() > 0 {
+			report.BytesPerCapture = float64(size) / float64(expected.Length())
+		}
+		reports = append(reports, report)
+	}
+
+	for childIndex, child := range original.Recordings() {
+		childReports, err := a.compareRecordings(path+"/"+child.Name(), child, decoded.Recordings()[childIndex])
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, childReports...)
+	}
+
+	return reports, nil
+}
+
+// Audit writes the recording with the encoders and time storage technique
+// provided, reads it back, and reports how much error was introduced into
+// each capture collection.
+func Audit(rec format.Recording, encoders []encoding.Encoder, timeStorageTechnique io.TimeStorageTechnique) (Report, error) {
+	buf, err := writeToBuffer(rec, encoders, timeStorageTechnique)
+	if err != nil {
+		return Report{}, err
+	}
+	totalBytes := buf.Len()
+
+	decoded, _, err := io.NewReader(encoders, buf).Read()
+	if err != nil {
+		return Report{}, err
+	}
+
+	emptyBuf, err := writeToBuffer(
+		format.NewRecording("", "", nil, nil, metadata.EmptyBlock(), nil, nil),
+		encoders,
+		timeStorageTechnique,
+	)
+	if err != nil {
+		return Report{}, err
+	}
+
+	a := auditor{
+		encoders:             encoders,
+		timeStorageTechnique: timeStorageTechnique,
+		emptySize:            emptyBuf.Len(),
+	}
+
+	streams, err := a.compareRecordings(rec.Name(), rec, decoded)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return Report{
+		TotalBytes: totalBytes,
+		Streams:    streams,
+	}, nil
+}
+
+// structError reports the largest error of any field within the capture.
+// Numeric fields report their difference, while strings, bools and enums
+// report 1 on mismatch.
+func structError(expected, actual structure.Capture) float64 {
+	worst := 0.0
+	for i, value := range expected.Values() {
+		switch v := value.(type) {
+		case float64:
+			worst = math.Max(worst, math.Abs(v-actual.Value(i).(float64)))
+
+		case int64:
+			worst = math.Max(worst, math.Abs(float64(v-actual.Value(i).(int64))))
+
+		case vector.Vector3:
+			worst = math.Max(worst, v.Distance(actual.Value(i).(vector.Vector3)))
+
+		default:
+			if value != actual.Value(i) {
+				worst = math.Max(worst, 1)
+			}
+		}
+	}
+	return worst
+}
@@ -0,0 +1,178 @@
+package audit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/audit"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/euler"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/encoding"
+	enumEncoding "github.com/recolude/rap/format/encoding/enum"
+	eulerEncoding "github.com/recolude/rap/format/encoding/euler"
+	positionEncoding "github.com/recolude/rap/format/encoding/position"
+	skeletonEncoding "github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildRecording() format.Recording {
+	positions := make([]position.Capture, 200)
+	rotations := make([]euler.Capture, 200)
+	for i := range positions {
+		t := float64(i) / 30.0
+		positions[i] = position.NewCapture(t, math.Sin(t)*10, math.Cos(t)*10, t)
+		rotations[i] = euler.NewEulerZXYCapture(t, float64(i), -float64(i), 0)
+	}
+
+	return format.NewRecording(
+		"",
+		"Root",
+		nil,
+		[]format.Recording{
+			format.NewRecording(
+				"",
+				"Player",
+				[]format.CaptureCollection{
+					position.NewCollection("Position", positions),
+					euler.NewCollection("Rotation", rotations),
+					enum.NewCollection("State", []string{"idle", "run"}, []enum.Capture{
+						enum.NewCapture(0, 0),
+						enum.NewCapture(1, 1),
+					}),
+				},
+				nil,
+				metadata.EmptyBlock(),
+				nil,
+				nil,
+			),
+		},
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	)
+}
+
+func Test_Audit_Lossless(t *testing.T) {
+	// ARRANGE ================================================================
+	encoders := []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Raw64),
+		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
+	}
+
+	// ACT ====================================================================
+	report, err := audit.Audit(buildRecording(), encoders, io.Raw64)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Greater(t, report.TotalBytes, 0)
+	if assert.Len(t, report.Streams, 3) {
+		for _, stream := range report.Streams {
+			assert.Equal(t, "Root/Player", stream.Path)
+			assert.Equal(t, 0.0, stream.Value.Max, stream.Name)
+			assert.Equal(t, 0.0, stream.Time.Max, stream.Name)
+			assert.Greater(t, stream.Bytes, 0)
+		}
+		assert.Equal(t, "Position", report.Streams[0].Name)
+		assert.Equal(t, "recolude.position", report.Streams[0].Signature)
+		assert.Equal(t, 200, report.Streams[0].Captures)
+	}
+}
+
+func Test_Audit_Lossy(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := buildRecording()
+	lossless, err := audit.Audit(rec, []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Raw64),
+		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
+	}, io.Raw64)
+	assert.NoError(t, err)
+
+	// ACT ====================================================================
+	report, err := audit.Audit(rec, []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Oct24),
+		eulerEncoding.NewEncoder(eulerEncoding.Raw16),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
+	}, io.BST16)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	if assert.Len(t, report.Streams, 3) {
+		positionReport := report.Streams[0]
+		assert.Greater(t, positionReport.Value.Max, 0.0)
+		assert.LessOrEqual(t, positionReport.Value.Mean, positionReport.Value.RMS)
+		assert.LessOrEqual(t, positionReport.Value.RMS, positionReport.Value.Max)
+		assert.GreaterOrEqual(t, positionReport.Value.WorstIndex, 0)
+		assert.Less(t, positionReport.BytesPerCapture, lossless.Streams[0].BytesPerCapture)
+
+		rotationReport := report.Streams[1]
+		assert.Greater(t, rotationReport.Value.Max, 0.0)
+		assert.Less(t, rotationReport.Value.Max, 1.0)
+
+		enumReport := report.Streams[2]
+		assert.Equal(t, 0.0, enumReport.Value.Max)
+	}
+	assert.Less(t, report.TotalBytes, lossless.TotalBytes)
+}
+
+func Test_Audit_EmptyCollection(t *testing.T) {
+	rec := format.NewRecording("", "Root", []format.CaptureCollection{
+		position.NewCollection("Nothing", nil),
+	}, nil, metadata.EmptyBlock(), nil, nil)
+
+	report, err := audit.Audit(rec, []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Oct24),
+	}, io.BST16)
+
+	assert.NoError(t, err)
+	if assert.Len(t, report.Streams, 1) {
+		assert.Equal(t, -1, report.Streams[0].Value.WorstIndex)
+		assert.Equal(t, 0.0, report.Streams[0].BytesPerCapture)
+	}
+}
+
+func Test_Audit_SkeletonMeasuresJointDistance(t *testing.T) {
+	definition, err := skeleton.NewSkeleton([]skeleton.Joint{
+		{Name: "Hips", Parent: -1},
+		{Name: "Spine", Parent: 0, Offset: vector.NewVector3(0, 1, 0)},
+		{Name: "Head", Parent: 1, Offset: vector.NewVector3(0, 1, 0)},
+	})
+	assert.NoError(t, err)
+
+	poses := make([]skeleton.Capture, 100)
+	for i := range poses {
+		angle := float64(i) * 1.7
+		poses[i] = skeleton.NewCapture(float64(i)/30.0, vector.NewVector3(float64(i), 0, 0), []vector.Vector3{
+			vector.NewVector3(angle, 0, 0),
+			vector.NewVector3(0, 0, -angle),
+			vector.NewVector3(angle/2, angle, 0),
+		})
+	}
+
+	rec := format.NewRecording("", "Root", []format.CaptureCollection{
+		skeleton.NewCollection("Avatar", definition, poses),
+	}, nil, metadata.EmptyBlock(), nil, nil)
+
+	lossless, err := audit.Audit(rec, []encoding.Encoder{
+		skeletonEncoding.NewEncoder(skeletonEncoding.Raw32),
+	}, io.Raw64)
+	assert.NoError(t, err)
+
+	report, err := audit.Audit(rec, []encoding.Encoder{
+		skeletonEncoding.NewEncoder(skeletonEncoding.Quantized8),
+	}, io.Raw64)
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Streams, 1) && assert.Len(t, lossless.Streams, 1) {
+		assert.Less(t, lossless.Streams[0].Value.Max, 0.0001)
+		assert.Greater(t, report.Streams[0].Value.Max, 0.0)
+		assert.Less(t, report.Streams[0].Value.Max, 0.1)
+	}
+}
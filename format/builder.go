@@ -0,0 +1,231 @@
+package format
+
+import "github.com/recolude/rap/format/metadata"
+
+// Builder assembles a recording piece by piece. Unlike recordings themselves,
+// builders are mutable; every method changes the builder it's called on and
+// returns it so calls can be chained. Build can be called any number of times,
+// and each recording produced is unaffected by later changes to the builder.
+type Builder struct {
+	id                 string
+	name               string
+	metadata           metadata.Block
+	captureCollections []CaptureCollection
+	recordings         []Recording
+	binaries           []Binary
+	binaryReferences   []BinaryReference
+}
+
+// NewBuilder creates a builder for an empty recording.
+func NewBuilder() *Builder {
+	return &Builder{
+		metadata:           metadata.EmptyBlock(),
+		captureCollections: make([]CaptureCollection, 0),
+		recordings:         make([]Recording, 0),
+		binaries:           make([]Binary, 0),
+		binaryReferences:   make([]BinaryReference, 0),
+	}
+}
+
+// BuilderFrom creates a builder starting with everything the recording
+// contains. The recording itself is never modified.
+func BuilderFrom(rec Recording) *Builder {
+	return &Builder{
+		id:                 rec.ID(),
+		name:               rec.Name(),
+		metadata:           rec.Metadata(),
+		captureCollections: append(make([]CaptureCollection, 0, len(rec.CaptureCollections())), rec.CaptureCollections()...),
+		recordings:         append(make([]Recording, 0, len(rec.Recordings())), rec.Recordings()...),
+		binaries:           append(make([]Binary, 0, len(rec.Binaries())), rec.Binaries()...),
+		binaryReferences:   append(make([]BinaryReference, 0, len(rec.BinaryReferences())), rec.BinaryReferences()...),
+	}
+}
+
+// SetID sets the ID of the recording.
+func (b *Builder) SetID(id string) *Builder {
+	b.id = id
+	return b
+}
+
+// SetName sets the name of the recording.
+func (b *Builder) SetName(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// SetMetadata replaces the metadata of the recording with the block.
+func (b *Builder) SetMetadata(block metadata.Block) *Builder {
+	b.metadata = block
+	return b
+}
+
+// AddCollection appends collections to the recording.
+func (b *Builder) AddCollection(collections ...CaptureCollection) *Builder {
+	b.captureCollections = append(b.captureCollections, collections...)
+	return b
+}
+
+// RemoveCollection removes every collection with the name provided.
+func (b *Builder) RemoveCollection(name string) *Builder {
+	kept := make([]CaptureCollection, 0, len(b.captureCollections))
+	for _, collection := range b.captureCollections {
+		if collection.Name() != name {
+			kept = append(kept, collection)
+		}
+	}
+	b.captureCollections = kept
+	return b
+}
+
+// ReplaceCollection swaps the first collection with the name provided for the
+// collection given, keeping its position. Other collections of the same name
+// are removed. If no collection has the name, the collection is appended.
+func (b *Builder) ReplaceCollection(name string, collection CaptureCollection) *Builder {
+	kept := make([]CaptureCollection, 0, len(b.captureCollections))
+	replaced := false
+	for _, existing := range b.captureCollections {
+		if existing.Name() != name {
+			kept = append(kept, existing)
+		} else if !replaced {
+			kept = append(kept, collection)
+			replaced = true
+		}
+	}
+	if !replaced {
+		kept = append(kept, collection)
+	}
+	b.captureCollections = kept
+	return b
+}
+
+// AddChild appends child recordings.
+func (b *Builder) AddChild(children ...Recording) *Builder {
+	b.recordings = append(b.recordings, children...)
+	return b
+}
+
+// RemoveChild removes every child recording with the name provided.
+func (b *Builder) RemoveChild(name string) *Builder {
+	kept := make([]Recording, 0, len(b.recordings))
+	for _, child := range b.recordings {
+		if child.Name() != name {
+			kept = append(kept, child)
+		}
+	}
+	b.recordings = kept
+	return b
+}
+
+// ReplaceChild swaps the first child recording with the name provided for the
+// recording given, keeping its position. Other children of the same name are
+// removed. If no child has the name, the recording is appended.
+func (b *Builder) ReplaceChild(name string, child Recording) *Builder {
+	kept := make([]Recording, 0, len(b.recordings))
+	replaced := false
+	for _, existing := range b.recordings {
+		if existing.Name() != name {
+			kept = append(kept, existing)
+		} else if !replaced {
+			kept = append(kept, child)
+			replaced = true
+		}
+	}
+	if !replaced {
+		kept = append(kept, child)
+	}
+	b.recordings = kept
+	return b
+}
+
+// AddBinary appends binaries to the recording.
+func (b *Builder) AddBinary(binaries ...Binary) *Builder {
+	b.binaries = append(b.binaries, binaries...)
+	return b
+}
+
+// RemoveBinary removes every binary with the name provided.
+func (b *Builder) RemoveBinary(name string) *Builder {
+	kept := make([]Binary, 0, len(b.binaries))
+	for _, binary := range b.binaries {
+		if binary.Name() != name {
+			kept = append(kept, binary)
+		}
+	}
+	b.binaries = kept
+	return b
+}
+
+// ReplaceBinary swaps the first binary with the name provided for the binary
+// given, keeping its position. Other binaries of the same name are removed.
+// If no binary has the name, the binary is appended.
+func (b *Builder) ReplaceBinary(name string, binary Binary) *Builder {
+	kept := make([]Binary, 0, len(b.binaries))
+	replaced := false
+	for _, existing := range b.binaries {
+		if existing.Name() != name {
+			kept = append(kept, existing)
+		} else if !replaced {
+			kept = append(kept, binary)
+			replaced = true
+		}
+	}
+	if !replaced {
+		kept = append(kept, binary)
+	}
+	b.binaries = kept
+	return b
+}
+
+// AddBinaryReference appends references to binaries stored elsewhere.
+func (b *Builder) AddBinaryReference(references ...BinaryReference) *Builder {
+	b.binaryReferences = append(b.binaryReferences, references...)
+	return b
+}
+
+// RemoveBinaryReference removes every binary reference with the name
+// provided.
+func (b *Builder) RemoveBinaryReference(name string) *Builder {
+	kept := make([]BinaryReference, 0, len(b.binaryReferences))
+	for _, reference := range b.binaryReferences {
+		if reference.Name() != name {
+			kept = append(kept, reference)
+		}
+	}
+	b.binaryReferences = kept
+	return b
+}
+
+// ReplaceBinaryReference swaps the first binary reference with the name
+// provided for the reference given, keeping its position. Other references of
+// the same name are removed. If no reference has the name, the reference is
+// appended.
+func (b *Builder) ReplaceBinaryReference(name string, reference BinaryReference) *Builder {
+	kept := make([]BinaryReference, 0, len(b.binaryReferences))
+	replaced := false
+	for _, existing := range b.binaryReferences {
+		if existing.Name() != name {
+			kept = append(kept, existing)
+		} else if !replaced {
+			kept = append(kept, reference)
+			replaced = true
+		}
+	}
+	if !replaced {
+		kept = append(kept, reference)
+	}
+	b.binaryReferences = kept
+	return b
+}
+
+// Build creates a recording from the builder's current state.
+func (b *Builder) Build() Recording {
+	return NewRecording(
+		b.id,
+		b.name,
+		append(make([]CaptureCollection, 0, len(b.captureCollections)), b.captureCollections...),
+		append(make([]Recording, 0, len(b.recordings)), b.recordings...),
+		b.metadata,
+		append(make([]Binary, 0, len(b.binaries)), b.binaries...),
+		append(make([]BinaryReference, 0, len(b.binaryReferences)), b.binaryReferences...),
+	)
+}
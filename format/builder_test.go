@@ -0,0 +1,117 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectionNames(rec format.Recording) []string {
+	names := make([]string, len(rec.CaptureCollections()))
+	for i, collection := range rec.CaptureCollections() {
+		names[i] = collection.Name()
+	}
+	return names
+}
+
+func Test_Builder(t *testing.T) {
+	// ARRANGE ================================================================
+	block := metadata.NewBlock(map[string]metadata.Property{
+		"level": metadata.NewStringProperty("castle"),
+	})
+
+	// ACT ====================================================================
+	rec := format.NewBuilder().
+		SetID("id").
+		SetName("name").
+		SetMetadata(block).
+		AddCollection(position.NewCollection("a", nil), position.NewCollection("b", nil)).
+		AddChild(format.NewBuilder().SetName("child").Build()).
+		AddBinary(io.NewBinary("bin", []byte{1}, metadata.EmptyBlock())).
+		AddBinaryReference(io.NewBinaryReference("ref", "uri", 1, metadata.EmptyBlock())).
+		Build()
+
+	// ASSERT =================================================================
+	assert.Equal(t, "id", rec.ID())
+	assert.Equal(t, "name", rec.Name())
+	assert.Equal(t, block, rec.Metadata())
+	assert.Equal(t, []string{"a", "b"}, collectionNames(rec))
+	if assert.Len(t, rec.Recordings(), 1) {
+		assert.Equal(t, "child", rec.Recordings()[0].Name())
+	}
+	assert.Len(t, rec.Binaries(), 1)
+	assert.Len(t, rec.BinaryReferences(), 1)
+}
+
+func Test_BuilderFrom_LeavesOriginalAlone(t *testing.T) {
+	// ARRANGE ================================================================
+	original := format.NewRecording(
+		"id",
+		"name",
+		[]format.CaptureCollection{
+			position.NewCollection("a", nil),
+			position.NewCollection("b", nil),
+			position.NewCollection("c", nil),
+		},
+		[]format.Recording{
+			format.NewBuilder().SetName("first").Build(),
+			format.NewBuilder().SetName("second").Build(),
+		},
+		metadata.EmptyBlock(),
+		[]format.Binary{io.NewBinary("bin", []byte{1}, metadata.EmptyBlock())},
+		[]format.BinaryReference{
+			io.NewBinaryReference("ref", "a", 1, metadata.EmptyBlock()),
+			io.NewBinaryReference("other", "b", 2, metadata.EmptyBlock()),
+			io.NewBinaryReference("ref", "c", 3, metadata.EmptyBlock()),
+		},
+	)
+
+	// ACT ====================================================================
+	rec := format.BuilderFrom(original).
+		SetName("renamed").
+		RemoveCollection("a").
+		ReplaceCollection("c", position.NewCollection("c2", nil)).
+		ReplaceCollection("missing", position.NewCollection("d", nil)).
+		ReplaceChild("first", format.NewBuilder().SetName("replaced").Build()).
+		RemoveChild("second").
+		RemoveBinary("bin").
+		ReplaceBinaryReference("ref", io.NewBinaryReference("ref", "d", 4, metadata.EmptyBlock())).
+		Build()
+
+	// ASSERT =================================================================
+	assert.Equal(t, "id", rec.ID())
+	assert.Equal(t, "renamed", rec.Name())
+	assert.Equal(t, []string{"b", "c2", "d"}, collectionNames(rec))
+	if assert.Len(t, rec.Recordings(), 1) {
+		assert.Equal(t, "replaced", rec.Recordings()[0].Name())
+	}
+	assert.Empty(t, rec.Binaries())
+	if assert.Len(t, rec.BinaryReferences(), 2) {
+		assert.Equal(t, "d", rec.BinaryReferences()[0].URI())
+		assert.Equal(t, "other", rec.BinaryReferences()[1].Name())
+	}
+
+	assert.Equal(t, "name", original.Name())
+	assert.Equal(t, []string{"a", "b", "c"}, collectionNames(original))
+	assert.Len(t, original.Recordings(), 2)
+	assert.Len(t, original.Binaries(), 1)
+	assert.Len(t, original.BinaryReferences(), 3)
+}
+
+func Test_Builder_BuiltRecordingsAreIndependent(t *testing.T) {
+	// ARRANGE ================================================================
+	builder := format.NewBuilder().AddCollection(position.NewCollection("a", nil))
+	first := builder.Build()
+
+	// ACT ====================================================================
+	builder.AddCollection(position.NewCollection("b", nil)).ReplaceCollection("a", position.NewCollection("z", nil))
+	second := builder.Build()
+
+	// ASSERT =================================================================
+	assert.Equal(t, []string{"a"}, collectionNames(first))
+	assert.Equal(t, []string{"z", "b"}, collectionNames(second))
+}
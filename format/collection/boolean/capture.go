@@ -0,0 +1,27 @@
+package boolean
+
+import "fmt"
+
+type Capture struct {
+	time  float64
+	value bool
+}
+
+func NewCapture(time float64, value bool) Capture {
+	return Capture{
+		time:  time,
+		value: value,
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) Value() bool {
+	return c.value
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf("[%.2f] - %t", c.time, c.value)
+}
@@ -0,0 +1,178 @@
+package boolean
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+)
+
+// Interval is a span of time in which a boolean collection's value was true.
+type Interval struct {
+	Start float64
+	End   float64
+}
+
+// Duration is how long the interval lasted.
+func (i Interval) Duration() float64 {
+	return i.End - i.Start
+}
+
+// Collection is a series of state transitions. The first capture is the
+// initial state, and every capture afterwards flips the value.
+type Collection struct {
+	name     string
+	metadata metadata.Block
+	captures []Capture
+}
+
+// NewCollection builds a collection out of the captures provided, dropping
+// any capture that does not change the value of the one preceding it.
+func NewCollection(name string, captures []Capture) Collection {
+	transitions := make([]Capture, 0, len(captures))
+	for _, c := range captures {
+		if len(transitions) > 0 && transitions[len(transitions)-1].Value() == c.Value() {
+			continue
+		}
+		transitions = append(transitions, c)
+	}
+	return Collection{
+		name:     name,
+		metadata: metadata.EmptyBlock(),
+		captures: transitions,
+	}
+}
+
+func (c Collection) Name() string {
+	return c.name
+}
+
+func (c Collection) Metadata() metadata.Block {
+	return c.metadata
+}
+
+func (c Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	c.metadata = block
+	return c
+}
+
+func (Collection) Signature() string {
+	return "recolude.bool"
+}
+
+func (c Collection) Captures() []format.Capture {
+	returnVal := make([]format.Capture, len(c.captures))
+	for i := range c.captures {
+		returnVal[i] = c.captures[i]
+	}
+	return returnVal
+}
+
+// Slice keeps all transitions that fall within the time range provided. If
+// the collection already had a value before the beginning of the slice, a
+// capture is inserted at the beginning to preserve it.
+func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
+	slicedCaptures := make([]Capture, 0)
+
+	if value, known := c.ValueAt(beginning); known && !math.IsInf(beginning, -1) && beginning < end {
+		slicedCaptures = append(slicedCaptures, NewCapture(beginning, value))
+	}
+
+	for _, capture := range c.captures {
+		if format.CaptureFallsWithin(capture, beginning, end) {
+			slicedCaptures = append(slicedCaptures, capture)
+		}
+	}
+	return NewCollection(c.Name(), slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), captures).WithMetadata(c.metadata), nil
+}
+
+func (c Collection) Start() float64 {
+	return c.captures[0].Time()
+}
+
+func (c Collection) End() float64 {
+	return c.captures[len(c.captures)-1].Time()
+}
+
+func (c Collection) Length() int {
+	return len(c.captures)
+}
+
+func (c Collection) CaptureAt(index int) format.Capture {
+	return c.captures[index]
+}
+
+// ValueAt returns the value of the collection at the time provided. The
+// second return value is false when the time falls before the first capture,
+// as the value is not known yet.
+func (c Collection) ValueAt(time float64) (bool, bool) {
+	value := false
+	known := false
+	for _, capture := range c.captures {
+		if capture.Time() > time {
+			break
+		}
+		value = capture.Value()
+		known = true
+	}
+	return value, known
+}
+
+// IntervalsTrue returns every span of time between beginning and end in which
+// the collection's value was true.
+func (c Collection) IntervalsTrue(beginning, end float64) []Interval {
+	intervals := make([]Interval, 0)
+	if end <= beginning {
+		return intervals
+	}
+
+	for i, capture := range c.captures {
+		if !capture.Value() {
+			continue
+		}
+
+		intervalEnd := end
+		if i+1 < len(c.captures) {
+			intervalEnd = math.Min(c.captures[i+1].Time(), end)
+		}
+
+		intervalStart := math.Max(capture.Time(), beginning)
+		if intervalStart < intervalEnd {
+			intervals = append(intervals, Interval{Start: intervalStart, End: intervalEnd})
+		}
+	}
+
+	return intervals
+}
+
+// TimeTrue returns how long the collection's value was true between
+// beginning and end.
+func (c Collection) TimeTrue(beginning, end float64) float64 {
+	total := 0.0
+	for _, interval := range c.IntervalsTrue(beginning, end) {
+		total += interval.Duration()
+	}
+	return total
+}
@@ -0,0 +1,121 @@
+package boolean_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func grounded() boolean.Collection {
+	return boolean.NewCollection("Grounded", []boolean.Capture{
+		boolean.NewCapture(1, true),
+		boolean.NewCapture(2, true),
+		boolean.NewCapture(3, false),
+		boolean.NewCapture(5, true),
+		boolean.NewCapture(8, false),
+	})
+}
+
+func Test_Collection_DropsRedundantCaptures(t *testing.T) {
+	// ACT ====================================================================
+	collection := grounded()
+
+	// ASSERT =================================================================
+	assert.Equal(t, "Grounded", collection.Name())
+	assert.Equal(t, "recolude.bool", collection.Signature())
+	assert.Equal(t, 4, collection.Length())
+	assert.Equal(t, boolean.NewCapture(1, true), collection.CaptureAt(0))
+	assert.Equal(t, boolean.NewCapture(3, false), collection.CaptureAt(1))
+	assert.Equal(t, 1.0, collection.Start())
+	assert.Equal(t, 8.0, collection.End())
+}
+
+func Test_Collection_ValueAt(t *testing.T) {
+	collection := grounded()
+
+	tests := map[string]struct {
+		time  float64
+		value bool
+		known bool
+	}{
+		"before first":       {time: 0, value: false, known: false},
+		"on first":           {time: 1, value: true, known: true},
+		"on redundant":       {time: 2, value: true, known: true},
+		"between":            {time: 4, value: false, known: true},
+		"on last transition": {time: 8, value: false, known: true},
+		"after last":         {time: 100, value: false, known: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, known := collection.ValueAt(tc.time)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.known, known)
+		})
+	}
+}
+
+func Test_Collection_IntervalsTrue(t *testing.T) {
+	collection := grounded()
+
+	assert.Equal(t, []boolean.Interval{{Start: 1, End: 3}, {Start: 5, End: 8}}, collection.IntervalsTrue(0, 10))
+	assert.Equal(t, []boolean.Interval{{Start: 2, End: 3}, {Start: 5, End: 6}}, collection.IntervalsTrue(2, 6))
+	assert.Len(t, collection.IntervalsTrue(3, 5), 0)
+	assert.Len(t, collection.IntervalsTrue(6, 2), 0)
+
+	assert.Equal(t, 5.0, collection.TimeTrue(0, 10))
+	assert.Equal(t, 2.0, collection.TimeTrue(2, 6))
+}
+
+func Test_Collection_IntervalsTrue_OpenEnded(t *testing.T) {
+	collection := boolean.NewCollection("Held", []boolean.Capture{
+		boolean.NewCapture(1, false),
+		boolean.NewCapture(4, true),
+	})
+
+	assert.Equal(t, []boolean.Interval{{Start: 4, End: 10}}, collection.IntervalsTrue(0, 10))
+	assert.Equal(t, 6.0, collection.TimeTrue(0, 10))
+}
+
+func Test_Collection_SliceKeepsInitialState(t *testing.T) {
+	// ACT ====================================================================
+	sliced := grounded().Slice(4, 6).(boolean.Collection)
+
+	// ASSERT =================================================================
+	if assert.Equal(t, 2, sliced.Length()) {
+		assert.Equal(t, boolean.NewCapture(4, false), sliced.CaptureAt(0))
+		assert.Equal(t, boolean.NewCapture(5, true), sliced.CaptureAt(1))
+	}
+}
+
+func Test_Collection_SliceOnTransition(t *testing.T) {
+	sliced := grounded().Slice(3, 6).(boolean.Collection)
+
+	if assert.Equal(t, 2, sliced.Length()) {
+		assert.Equal(t, boolean.NewCapture(3, false), sliced.CaptureAt(0))
+		assert.Equal(t, boolean.NewCapture(5, true), sliced.CaptureAt(1))
+	}
+}
+
+func Test_Collection_SliceBeforeStart(t *testing.T) {
+	sliced := grounded().Slice(-5, 2).(boolean.Collection)
+
+	if assert.Equal(t, 1, sliced.Length()) {
+		assert.Equal(t, boolean.NewCapture(1, true), sliced.CaptureAt(0))
+	}
+}
+
+func Test_Collection_FormatSlice(t *testing.T) {
+	rec := format.NewRecording("", "", []format.CaptureCollection{grounded()}, nil, metadata.EmptyBlock(), nil, nil)
+
+	sliced := format.Slice(rec, format.BeginningOfSlice(6), format.EndOfSlice(10))
+
+	collection := sliced.CaptureCollections()[0].(boolean.Collection)
+	value, known := collection.ValueAt(6)
+	assert.True(t, known)
+	assert.True(t, value)
+	assert.Equal(t, 2.0, collection.TimeTrue(6, 10))
+}
@@ -1,11 +1,15 @@
 package enum
 
 import (
+	"fmt"
+
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
 )
 
 type Collection struct {
 	name        string
+	metadata    metadata.Block
 	enumMembers []string
 	captures    []Capture
 }
@@ -13,6 +17,7 @@ type Collection struct {
 func NewCollection(name string, enumMembers []string, captures []Capture) Collection {
 	return Collection{
 		name:        name,
+		metadata:    metadata.EmptyBlock(),
 		enumMembers: enumMembers,
 		captures:    captures,
 	}
@@ -22,6 +27,15 @@ func (s Collection) Name() string {
 	return s.name
 }
 
+func (s Collection) Metadata() metadata.Block {
+	return s.metadata
+}
+
+func (s Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	s.metadata = block
+	return s
+}
+
 func (s Collection) EnumMembers() []string {
 	return s.enumMembers
 }
@@ -45,7 +59,52 @@ func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
 			slicedCaptures = append(slicedCaptures, c)
 		}
 	}
-	return NewCollection(c.Name(), c.EnumMembers(), slicedCaptures)
+	return NewCollection(c.Name(), c.EnumMembers(), slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), c.EnumMembers(), mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+
+	members := append(make([]string, 0, len(c.enumMembers)), c.enumMembers...)
+	memberIndices := make(map[string]int, len(members))
+	for i, member := range members {
+		if _, exists := memberIndices[member]; !exists {
+			memberIndices[member] = i
+		}
+	}
+
+	remapped := make([]int, len(appended.enumMembers))
+	for i, member := range appended.enumMembers {
+		index, exists := memberIndices[member]
+		if !exists {
+			index = len(members)
+			members = append(members, member)
+			memberIndices[member] = index
+		}
+		remapped[i] = index
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	for _, capture := range appended.captures {
+		if capture.value >= 0 && capture.value < len(remapped) {
+			capture.value = remapped[capture.value]
+		}
+		captures = append(captures, capture)
+	}
+	return NewCollection(c.Name(), members, captures).WithMetadata(c.metadata), nil
 }
 
 func (c Collection) Start() float64 {
@@ -1,17 +1,22 @@
 package float
 
 import (
+	"fmt"
+
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
 )
 
 type Collection struct {
 	name     string
+	metadata metadata.Block
 	captures []Capture
 }
 
 func NewCollection(name string, captures []Capture) Collection {
 	return Collection{
 		name:     name,
+		metadata: metadata.EmptyBlock(),
 		captures: captures,
 	}
 }
@@ -20,6 +25,15 @@ func (s Collection) Name() string {
 	return s.name
 }
 
+func (s Collection) Metadata() metadata.Block {
+	return s.metadata
+}
+
+func (s Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	s.metadata = block
+	return s
+}
+
 func (s Collection) Captures() []format.Capture {
 	returnVal := make([]format.Capture, len(s.captures))
 	for i := range s.captures {
@@ -39,7 +53,28 @@ func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
 			slicedCaptures = append(slicedCaptures, c)
 		}
 	}
-	return NewCollection(c.Name(), slicedCaptures)
+	return NewCollection(c.Name(), slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), captures).WithMetadata(c.metadata), nil
 }
 
 func (c Collection) Start() float64 {
@@ -0,0 +1,36 @@
+package floatvec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capture is the value of every channel of a collection at a single point in
+// time, ordered the same as the collection's channels.
+type Capture struct {
+	time   float64
+	values []float64
+}
+
+func NewCapture(time float64, values []float64) Capture {
+	return Capture{
+		time:   time,
+		values: values,
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) Values() []float64 {
+	return c.values
+}
+
+func (c Capture) String() string {
+	values := make([]string, len(c.values))
+	for i, v := range c.values {
+		values[i] = fmt.Sprintf("%.2f", v)
+	}
+	return fmt.Sprintf("[%.2f] - %s", c.time, strings.Join(values, ", "))
+}
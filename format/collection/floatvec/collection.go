@@ -0,0 +1,130 @@
+package floatvec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/metadata"
+)
+
+// Collection is a series of fixed width float vectors, like blendshape
+// weights or controller axes, where every capture has a value for each named
+// channel.
+type Collection struct {
+	name     string
+	metadata metadata.Block
+	channels []string
+	captures []Capture
+}
+
+func NewCollection(name string, channels []string, captures []Capture) Collection {
+	return Collection{
+		name:     name,
+		metadata: metadata.EmptyBlock(),
+		channels: channels,
+		captures: captures,
+	}
+}
+
+func (c Collection) Name() string {
+	return c.name
+}
+
+func (c Collection) Metadata() metadata.Block {
+	return c.metadata
+}
+
+func (c Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	c.metadata = block
+	return c
+}
+
+func (Collection) Signature() string {
+	return "recolude.floatvec"
+}
+
+// Channels are the names of each value within a capture.
+func (c Collection) Channels() []string {
+	return c.channels
+}
+
+// ChannelIndex returns the index of the channel with the name provided, or -1
+// if the collection has no such channel.
+func (c Collection) ChannelIndex(name string) int {
+	for i, channel := range c.channels {
+		if channel == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Channel pulls a single channel out of the collection.
+func (c Collection) Channel(index int) float.Collection {
+	captures := make([]float.Capture, len(c.captures))
+	for i, capture := range c.captures {
+		captures[i] = float.NewCapture(capture.Time(), capture.Values()[index])
+	}
+	return float.NewCollection(c.channels[index], captures)
+}
+
+func (c Collection) Captures() []format.Capture {
+	returnVal := make([]format.Capture, len(c.captures))
+	for i := range c.captures {
+		returnVal[i] = c.captures[i]
+	}
+	return returnVal
+}
+
+func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
+	slicedCaptures := make([]Capture, 0)
+	for _, c := range c.captures {
+		if format.CaptureFallsWithin(c, beginning, end) {
+			slicedCaptures = append(slicedCaptures, c)
+		}
+	}
+	return NewCollection(c.Name(), c.channels, slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), c.channels, mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+	if !reflect.DeepEqual(c.channels, appended.channels) {
+		return nil, errors.New("can not append float vector collections with different channels")
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), c.channels, captures).WithMetadata(c.metadata), nil
+}
+
+func (c Collection) Start() float64 {
+	return c.captures[0].Time()
+}
+
+func (c Collection) End() float64 {
+	return c.captures[len(c.captures)-1].Time()
+}
+
+func (c Collection) Length() int {
+	return len(c.captures)
+}
+
+func (c Collection) CaptureAt(index int) format.Capture {
+	return c.captures[index]
+}
@@ -0,0 +1,34 @@
+package floatvec_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Collection(t *testing.T) {
+	// ARRANGE ================================================================
+	collection := floatvec.NewCollection("Face", []string{"jawOpen", "blinkLeft"}, []floatvec.Capture{
+		floatvec.NewCapture(1, []float64{0.1, 0}),
+		floatvec.NewCapture(2, []float64{0.5, 1}),
+		floatvec.NewCapture(3, []float64{0.2, 0}),
+	})
+
+	// ACT ====================================================================
+	sliced := collection.Slice(1.5, 3).(floatvec.Collection)
+	blink := collection.Channel(collection.ChannelIndex("blinkLeft"))
+
+	// ASSERT =================================================================
+	assert.Equal(t, "Face", collection.Name())
+	assert.Equal(t, "recolude.floatvec", collection.Signature())
+	assert.Equal(t, -1, collection.ChannelIndex("mouthSmile"))
+	assert.Equal(t, "[2.00] - 0.50, 1.00", collection.CaptureAt(1).String())
+
+	assert.Equal(t, []string{"jawOpen", "blinkLeft"}, sliced.Channels())
+	assert.Equal(t, 1, sliced.Length())
+
+	assert.Equal(t, "blinkLeft", blink.Name())
+	assert.Equal(t, float.NewCapture(2, 1), blink.CaptureAt(1))
+}
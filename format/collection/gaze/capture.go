@@ -0,0 +1,75 @@
+package gaze
+
+import (
+	"fmt"
+
+	"github.com/EliCDavis/vector"
+)
+
+// Capture is a single sample from an eye tracker.
+type Capture struct {
+	time          float64
+	origin        vector.Vector3
+	direction     vector.Vector3
+	leftOpenness  float64
+	rightOpenness float64
+	pupilDiameter float64
+	confidence    float64
+}
+
+// NewCapture builds a gaze sample. The direction is normalized, openness and
+// confidence are expected to range from 0 to 1, and pupil diameter is in
+// millimeters.
+func NewCapture(time float64, origin, direction vector.Vector3, leftOpenness, rightOpenness, pupilDiameter, confidence float64) Capture {
+	if direction.Length() > 0 {
+		direction = direction.Normalized()
+	}
+	return Capture{
+		time:          time,
+		origin:        origin,
+		direction:     direction,
+		leftOpenness:  leftOpenness,
+		rightOpenness: rightOpenness,
+		pupilDiameter: pupilDiameter,
+		confidence:    confidence,
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) Origin() vector.Vector3 {
+	return c.origin
+}
+
+func (c Capture) Direction() vector.Vector3 {
+	return c.direction
+}
+
+func (c Capture) LeftOpenness() float64 {
+	return c.leftOpenness
+}
+
+func (c Capture) RightOpenness() float64 {
+	return c.rightOpenness
+}
+
+func (c Capture) PupilDiameter() float64 {
+	return c.pupilDiameter
+}
+
+func (c Capture) Confidence() float64 {
+	return c.confidence
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf(
+		"[%.2f] Gaze - %.2f, %.2f, %.2f (confidence %.2f)",
+		c.time,
+		c.direction.X(),
+		c.direction.Y(),
+		c.direction.Z(),
+		c.confidence,
+	)
+}
@@ -0,0 +1,188 @@
+package gaze
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+)
+
+type Collection struct {
+	name     string
+	metadata metadata.Block
+	captures []Capture
+}
+
+func NewCollection(name string, captures []Capture) Collection {
+	return Collection{
+		name:     name,
+		metadata: metadata.EmptyBlock(),
+		captures: captures,
+	}
+}
+
+func (c Collection) Name() string {
+	return c.name
+}
+
+func (c Collection) Metadata() metadata.Block {
+	return c.metadata
+}
+
+func (c Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	c.metadata = block
+	return c
+}
+
+func (Collection) Signature() string {
+	return "recolude.gaze"
+}
+
+func (c Collection) Captures() []format.Capture {
+	returnVal := make([]format.Capture, len(c.captures))
+	for i := range c.captures {
+		returnVal[i] = c.captures[i]
+	}
+	return returnVal
+}
+
+func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
+	slicedCaptures := make([]Capture, 0)
+	for _, c := range c.captures {
+		if format.CaptureFallsWithin(c, beginning, end) {
+			slicedCaptures = append(slicedCaptures, c)
+		}
+	}
+	return NewCollection(c.Name(), slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), captures).WithMetadata(c.metadata), nil
+}
+
+func (c Collection) Start() float64 {
+	return c.captures[0].Time()
+}
+
+func (c Collection) End() float64 {
+	return c.captures[len(c.captures)-1].Time()
+}
+
+func (c Collection) Length() int {
+	return len(c.captures)
+}
+
+func (c Collection) CaptureAt(index int) format.Capture {
+	return c.captures[index]
+}
+
+// Movement is how the eye was behaving over a series of gaze samples.
+type Movement int
+
+const (
+	// Fixation is when the eye is holding steady on a target
+	Fixation Movement = iota
+
+	// Saccade is a rapid movement of the eye between fixations
+	Saccade
+)
+
+func (m Movement) String() string {
+	if m == Saccade {
+		return "saccade"
+	}
+	return "fixation"
+}
+
+// Segment is a consecutive run of gaze samples that share the same movement.
+// Indexes are inclusive.
+type Segment struct {
+	Movement   Movement
+	StartIndex int
+	EndIndex   int
+	Start      float64
+	End        float64
+}
+
+// angleBetween returns the angle in degrees between two normalized
+// directions.
+func angleBetween(a, b Capture) float64 {
+	dot := math.Max(-1, math.Min(1, a.Direction().Dot(b.Direction())))
+	return math.Acos(dot) * 180.0 / math.Pi
+}
+
+// ClassifyIVT labels every sample as a fixation or a saccade using the
+// velocity-threshold identification (I-VT) algorithm. Samples moving at an
+// angular velocity above the threshold (in degrees per second) are
+// saccades. Consecutive samples with the same label are grouped into
+// segments.
+func (c Collection) ClassifyIVT(velocityThreshold float64) []Segment {
+	segments := make([]Segment, 0)
+	if len(c.captures) == 0 {
+		return segments
+	}
+
+	movements := make([]Movement, len(c.captures))
+	for i := 1; i < len(c.captures); i++ {
+		movements[i] = movements[i-1]
+		elapsed := c.captures[i].Time() - c.captures[i-1].Time()
+		if elapsed <= 0 {
+			continue
+		}
+
+		movements[i] = Fixation
+		if angleBetween(c.captures[i-1], c.captures[i])/elapsed > velocityThreshold {
+			movements[i] = Saccade
+		}
+	}
+
+	// The first sample has no velocity of its own, so it takes on the
+	// movement of the sample after it
+	if len(movements) > 1 {
+		movements[0] = movements[1]
+	}
+
+	current := Segment{Movement: movements[0], Start: c.captures[0].Time()}
+	for i := 1; i < len(c.captures); i++ {
+		if movements[i] == current.Movement {
+			continue
+		}
+		current.EndIndex = i - 1
+		current.End = c.captures[i-1].Time()
+		segments = append(segments, current)
+		current = Segment{Movement: movements[i], StartIndex: i, Start: c.captures[i].Time()}
+	}
+	current.EndIndex = len(c.captures) - 1
+	current.End = c.captures[len(c.captures)-1].Time()
+
+	return append(segments, current)
+}
+
+// Fixations returns only the fixation segments found by ClassifyIVT.
+func (c Collection) Fixations(velocityThreshold float64) []Segment {
+	fixations := make([]Segment, 0)
+	for _, segment := range c.ClassifyIVT(velocityThreshold) {
+		if segment.Movement == Fixation {
+			fixations = append(fixations, segment)
+		}
+	}
+	return fixations
+}
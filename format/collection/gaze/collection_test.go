@@ -0,0 +1,89 @@
+package gaze_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/stretchr/testify/assert"
+)
+
+// lookAt builds a gaze sample looking the number of degrees provided to the
+// right of forward.
+func lookAt(time, degrees float64) gaze.Capture {
+	radians := degrees * math.Pi / 180.0
+	return gaze.NewCapture(
+		time,
+		vector.Vector3Zero(),
+		vector.NewVector3(math.Sin(radians), 0, math.Cos(radians)),
+		1, 1, 3.5, 1,
+	)
+}
+
+func Test_Capture_NormalizesDirection(t *testing.T) {
+	capture := gaze.NewCapture(1, vector.Vector3Zero(), vector.NewVector3(0, 0, 10), 0.5, 0.75, 3.2, 0.9)
+
+	assert.Equal(t, vector.NewVector3(0, 0, 1), capture.Direction())
+	assert.Equal(t, 0.5, capture.LeftOpenness())
+	assert.Equal(t, 0.75, capture.RightOpenness())
+	assert.Equal(t, 3.2, capture.PupilDiameter())
+	assert.Equal(t, 0.9, capture.Confidence())
+}
+
+func Test_Collection(t *testing.T) {
+	collection := gaze.NewCollection("Eyes", []gaze.Capture{lookAt(1, 0), lookAt(2, 0), lookAt(3, 0)})
+
+	assert.Equal(t, "Eyes", collection.Name())
+	assert.Equal(t, "recolude.gaze", collection.Signature())
+	assert.Equal(t, 2, collection.Slice(1.5, 4).Length())
+}
+
+func Test_Collection_ClassifyIVT(t *testing.T) {
+	// 100hz tracker holding still, darting 20 degrees over 50ms, then holding
+	// still again
+	captures := make([]gaze.Capture, 0)
+	for i := 0; i < 10; i++ {
+		captures = append(captures, lookAt(float64(i)*0.01, 0.01*float64(i)))
+	}
+	for i := 1; i <= 5; i++ {
+		captures = append(captures, lookAt(0.09+float64(i)*0.01, float64(i)*4))
+	}
+	for i := 1; i <= 10; i++ {
+		captures = append(captures, lookAt(0.14+float64(i)*0.01, 20))
+	}
+	collection := gaze.NewCollection("Eyes", captures)
+
+	// ACT ====================================================================
+	segments := collection.ClassifyIVT(30)
+
+	// ASSERT =================================================================
+	if assert.Len(t, segments, 3) {
+		assert.Equal(t, gaze.Fixation, segments[0].Movement)
+		assert.Equal(t, 0, segments[0].StartIndex)
+		assert.Equal(t, 9, segments[0].EndIndex)
+
+		assert.Equal(t, gaze.Saccade, segments[1].Movement)
+		assert.Equal(t, 10, segments[1].StartIndex)
+		assert.Equal(t, 14, segments[1].EndIndex)
+		assert.InDelta(t, 0.10, segments[1].Start, 0.0000001)
+		assert.InDelta(t, 0.14, segments[1].End, 0.0000001)
+
+		assert.Equal(t, gaze.Fixation, segments[2].Movement)
+		assert.Equal(t, 15, segments[2].StartIndex)
+		assert.Equal(t, 24, segments[2].EndIndex)
+	}
+
+	assert.Len(t, collection.Fixations(30), 2)
+	assert.Equal(t, "saccade", gaze.Saccade.String())
+}
+
+func Test_Collection_ClassifyIVT_Empty(t *testing.T) {
+	assert.Len(t, gaze.NewCollection("Eyes", nil).ClassifyIVT(30), 0)
+
+	single := gaze.NewCollection("Eyes", []gaze.Capture{lookAt(1, 0)}).ClassifyIVT(30)
+	if assert.Len(t, single, 1) {
+		assert.Equal(t, gaze.Fixation, single[0].Movement)
+		assert.Equal(t, 0, single[0].EndIndex)
+	}
+}
@@ -0,0 +1,27 @@
+package integer
+
+import "fmt"
+
+type Capture struct {
+	time  float64
+	value int64
+}
+
+func NewCapture(time float64, value int64) Capture {
+	return Capture{
+		time:  time,
+		value: value,
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf("[%.2f] - %d", c.time, c.value)
+}
+
+func (c Capture) Value() int64 {
+	return c.value
+}
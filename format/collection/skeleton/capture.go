@@ -0,0 +1,40 @@
+package skeleton
+
+import (
+	"fmt"
+
+	"github.com/EliCDavis/vector"
+)
+
+// Capture is a single pose of a skeleton. Rotations are local to each
+// joint's parent, stored as ZXY euler angles in degrees, and ordered the same
+// as the skeleton's joints.
+type Capture struct {
+	time      float64
+	root      vector.Vector3
+	rotations []vector.Vector3
+}
+
+func NewCapture(time float64, root vector.Vector3, rotations []vector.Vector3) Capture {
+	return Capture{
+		time:      time,
+		root:      root,
+		rotations: rotations,
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) Root() vector.Vector3 {
+	return c.root
+}
+
+func (c Capture) Rotations() []vector.Vector3 {
+	return c.rotations
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf("[%.2f] Root - %.2f, %.2f, %.2f (%d joints)", c.time, c.root.X(), c.root.Y(), c.root.Z(), len(c.rotations))
+}
@@ -0,0 +1,131 @@
+package skeleton
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+)
+
+type Collection struct {
+	name     string
+	metadata metadata.Block
+	skeleton Skeleton
+	captures []Capture
+}
+
+func NewCollection(name string, skeleton Skeleton, captures []Capture) Collection {
+	return Collection{
+		name:     name,
+		metadata: metadata.EmptyBlock(),
+		skeleton: skeleton,
+		captures: captures,
+	}
+}
+
+func (c Collection) Name() string {
+	return c.name
+}
+
+func (c Collection) Metadata() metadata.Block {
+	return c.metadata
+}
+
+func (c Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	c.metadata = block
+	return c
+}
+
+func (Collection) Signature() string {
+	return "recolude.skeleton"
+}
+
+func (c Collection) Skeleton() Skeleton {
+	return c.skeleton
+}
+
+func (c Collection) Captures() []format.Capture {
+	returnVal := make([]format.Capture, len(c.captures))
+	for i := range c.captures {
+		returnVal[i] = c.captures[i]
+	}
+	return returnVal
+}
+
+func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
+	slicedCaptures := make([]Capture, 0)
+	for _, c := range c.captures {
+		if format.CaptureFallsWithin(c, beginning, end) {
+			slicedCaptures = append(slicedCaptures, c)
+		}
+	}
+	return NewCollection(c.Name(), c.skeleton, slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.time = mapping(capture.time)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), c.skeleton, mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+	if !reflect.DeepEqual(c.skeleton, appended.skeleton) {
+		return nil, errors.New("can not append skeleton collections with different skeletons")
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), c.skeleton, captures).WithMetadata(c.metadata), nil
+}
+
+func (c Collection) Start() float64 {
+	return c.captures[0].Time()
+}
+
+func (c Collection) End() float64 {
+	return c.captures[len(c.captures)-1].Time()
+}
+
+func (c Collection) Length() int {
+	return len(c.captures)
+}
+
+func (c Collection) CaptureAt(index int) format.Capture {
+	return c.captures[index]
+}
+
+// PoseAt returns the most recent pose at or before the time provided. The
+// second return value is false if no pose had been captured yet.
+func (c Collection) PoseAt(time float64) (Capture, bool) {
+	pose := Capture{}
+	found := false
+	for _, capture := range c.captures {
+		if capture.Time() > time {
+			break
+		}
+		pose = capture
+		found = true
+	}
+	return pose, found
+}
+
+// WorldPositionsAt computes where every joint was in world space at the time
+// provided, using the most recent pose at or before it.
+func (c Collection) WorldPositionsAt(time float64) ([]vector.Vector3, bool) {
+	pose, found := c.PoseAt(time)
+	if !found {
+		return nil, false
+	}
+	return c.skeleton.WorldPositions(pose), true
+}
@@ -0,0 +1,137 @@
+package skeleton
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/EliCDavis/vector"
+)
+
+// Joint is a single bone within a skeleton.
+type Joint struct {
+	Name string
+
+	// Parent is the index of the joint this one is attached to, or -1 if the
+	// joint is a root.
+	Parent int
+
+	// Offset is the joint's position relative to its parent when the parent
+	// has no rotation. Root joints are offset from the root position of the
+	// capture.
+	Offset vector.Vector3
+}
+
+// Skeleton is a hierarchy of joints, where every joint's parent comes
+// before it.
+type Skeleton struct {
+	joints []Joint
+}
+
+// NewSkeleton builds a skeleton from the joints provided, returning an error
+// if any joint references a parent that does not come before it.
+func NewSkeleton(joints []Joint) (Skeleton, error) {
+	for i, joint := range joints {
+		if joint.Parent < -1 || joint.Parent >= i {
+			return Skeleton{}, fmt.Errorf("joint %s's parent %d must come before it", joint.Name, joint.Parent)
+		}
+	}
+	copied := make([]Joint, len(joints))
+	copy(copied, joints)
+	return Skeleton{joints: copied}, nil
+}
+
+func (s Skeleton) Joints() []Joint {
+	joints := make([]Joint, len(s.joints))
+	copy(joints, s.joints)
+	return joints
+}
+
+func (s Skeleton) JointCount() int {
+	return len(s.joints)
+}
+
+// JointIndex returns the index of the joint with the name provided, or -1 if
+// the skeleton has no such joint.
+func (s Skeleton) JointIndex(name string) int {
+	for i, joint := range s.joints {
+		if joint.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// WorldPositions computes where every joint is in world space for the pose
+// provided. Rotations are interpreted as ZXY euler angles in degrees.
+func (s Skeleton) WorldPositions(pose Capture) []vector.Vector3 {
+	positions := make([]vector.Vector3, len(s.joints))
+	rotations := make([]rotation, len(s.joints))
+
+	for i, joint := range s.joints {
+		local := identity()
+		if i < len(pose.rotations) {
+			local = eulerZXY(pose.rotations[i])
+		}
+
+		if joint.Parent == -1 {
+			positions[i] = pose.root.Add(joint.Offset)
+			rotations[i] = local
+			continue
+		}
+
+		parentRotation := rotations[joint.Parent]
+		positions[i] = positions[joint.Parent].Add(parentRotation.apply(joint.Offset))
+		rotations[i] = parentRotation.mult(local)
+	}
+
+	return positions
+}
+
+// rotation is a row major 3x3 rotation matrix
+type rotation [9]float64
+
+func identity() rotation {
+	return rotation{1, 0, 0, 0, 1, 0, 0, 0, 1}
+}
+
+func (r rotation) mult(o rotation) rotation {
+	var out rotation
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			out[row*3+col] = r[row*3]*o[col] + r[row*3+1]*o[3+col] + r[row*3+2]*o[6+col]
+		}
+	}
+	return out
+}
+
+func (r rotation) apply(v vector.Vector3) vector.Vector3 {
+	return vector.NewVector3(
+		r[0]*v.X()+r[1]*v.Y()+r[2]*v.Z(),
+		r[3]*v.X()+r[4]*v.Y()+r[5]*v.Z(),
+		r[6]*v.X()+r[7]*v.Y()+r[8]*v.Z(),
+	)
+}
+
+// eulerZXY builds a rotation that rotates around Z, then X, then Y.
+func eulerZXY(degrees vector.Vector3) rotation {
+	x := degrees.X() * math.Pi / 180.0
+	y := degrees.Y() * math.Pi / 180.0
+	z := degrees.Z() * math.Pi / 180.0
+
+	rx := rotation{
+		1, 0, 0,
+		0, math.Cos(x), -math.Sin(x),
+		0, math.Sin(x), math.Cos(x),
+	}
+	ry := rotation{
+		math.Cos(y), 0, math.Sin(y),
+		0, 1, 0,
+		-math.Sin(y), 0, math.Cos(y),
+	}
+	rz := rotation{
+		math.Cos(z), -math.Sin(z), 0,
+		math.Sin(z), math.Cos(z), 0,
+		0, 0, 1,
+	}
+	return ry.mult(rx).mult(rz)
+}
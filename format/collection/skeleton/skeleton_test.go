@@ -0,0 +1,95 @@
+package skeleton_test
+
+import (
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/stretchr/testify/assert"
+)
+
+func arm(t *testing.T) skeleton.Skeleton {
+	s, err := skeleton.NewSkeleton([]skeleton.Joint{
+		{Name: "Shoulder", Parent: -1, Offset: vector.NewVector3(0, 1, 0)},
+		{Name: "Elbow", Parent: 0, Offset: vector.NewVector3(1, 0, 0)},
+		{Name: "Wrist", Parent: 1, Offset: vector.NewVector3(1, 0, 0)},
+	})
+	assert.NoError(t, err)
+	return s
+}
+
+func assertVectorInDelta(t *testing.T, expected, actual vector.Vector3) {
+	assert.InDelta(t, expected.X(), actual.X(), 0.00001)
+	assert.InDelta(t, expected.Y(), actual.Y(), 0.00001)
+	assert.InDelta(t, expected.Z(), actual.Z(), 0.00001)
+}
+
+func Test_NewSkeleton_RejectsParentsAfterChildren(t *testing.T) {
+	_, err := skeleton.NewSkeleton([]skeleton.Joint{
+		{Name: "Hips", Parent: 1},
+		{Name: "Spine", Parent: -1},
+	})
+	assert.EqualError(t, err, "joint Hips's parent 1 must come before it")
+}
+
+func Test_Skeleton_JointIndex(t *testing.T) {
+	s := arm(t)
+	assert.Equal(t, 3, s.JointCount())
+	assert.Equal(t, 1, s.JointIndex("Elbow"))
+	assert.Equal(t, -1, s.JointIndex("Knee"))
+}
+
+func Test_Skeleton_WorldPositions_BindPose(t *testing.T) {
+	s := arm(t)
+	pose := skeleton.NewCapture(0, vector.NewVector3(10, 0, 0), []vector.Vector3{
+		vector.Vector3Zero(), vector.Vector3Zero(), vector.Vector3Zero(),
+	})
+
+	positions := s.WorldPositions(pose)
+
+	if assert.Len(t, positions, 3) {
+		assertVectorInDelta(t, vector.NewVector3(10, 1, 0), positions[0])
+		assertVectorInDelta(t, vector.NewVector3(11, 1, 0), positions[1])
+		assertVectorInDelta(t, vector.NewVector3(12, 1, 0), positions[2])
+	}
+}
+
+func Test_Skeleton_WorldPositions_RotationsCompose(t *testing.T) {
+	s := arm(t)
+
+	// Bend the shoulder 90 degrees around Z, then the elbow another 90
+	pose := skeleton.NewCapture(0, vector.Vector3Zero(), []vector.Vector3{
+		vector.NewVector3(0, 0, 90), vector.NewVector3(0, 0, 90), vector.Vector3Zero(),
+	})
+
+	positions := s.WorldPositions(pose)
+
+	if assert.Len(t, positions, 3) {
+		assertVectorInDelta(t, vector.NewVector3(0, 1, 0), positions[0])
+		assertVectorInDelta(t, vector.NewVector3(0, 2, 0), positions[1])
+		assertVectorInDelta(t, vector.NewVector3(-1, 2, 0), positions[2])
+	}
+}
+
+func Test_Collection_WorldPositionsAt(t *testing.T) {
+	s := arm(t)
+	still := []vector.Vector3{vector.Vector3Zero(), vector.Vector3Zero(), vector.Vector3Zero()}
+	collection := skeleton.NewCollection("Avatar", s, []skeleton.Capture{
+		skeleton.NewCapture(1, vector.NewVector3(0, 0, 0), still),
+		skeleton.NewCapture(2, vector.NewVector3(5, 0, 0), still),
+	})
+
+	assert.Equal(t, "recolude.skeleton", collection.Signature())
+	assert.Equal(t, s, collection.Skeleton())
+
+	_, found := collection.WorldPositionsAt(0.5)
+	assert.False(t, found)
+
+	positions, found := collection.WorldPositionsAt(2.5)
+	assert.True(t, found)
+	assertVectorInDelta(t, vector.NewVector3(7, 1, 0), positions[2])
+
+	sliced := collection.Slice(1.5, 3).(skeleton.Collection)
+	assert.Equal(t, 1, sliced.Length())
+	assert.Equal(t, s, sliced.Skeleton())
+}
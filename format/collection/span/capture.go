@@ -0,0 +1,65 @@
+package span
+
+import (
+	"fmt"
+
+	"github.com/recolude/rap/format/metadata"
+)
+
+// Capture is something that occurred over a period of time, like an ability
+// being channeled or a line of dialog being spoken.
+type Capture struct {
+	start float64
+	end   float64
+	label string
+	block metadata.Block
+}
+
+func NewCapture(start, end float64, label string, block metadata.Block) Capture {
+	return Capture{
+		start: start,
+		end:   end,
+		label: label,
+		block: block,
+	}
+}
+
+// Time is when the span started
+func (c Capture) Time() float64 {
+	return c.start
+}
+
+func (c Capture) Start() float64 {
+	return c.start
+}
+
+func (c Capture) End() float64 {
+	return c.end
+}
+
+func (c Capture) Duration() float64 {
+	return c.end - c.start
+}
+
+func (c Capture) Label() string {
+	return c.label
+}
+
+func (c Capture) Metadata() metadata.Block {
+	return c.block
+}
+
+// Contains is whether or not the span was active at the time provided. Spans
+// include their start but not their end.
+func (c Capture) Contains(time float64) bool {
+	return time >= c.start && time < c.end
+}
+
+// Overlaps is whether or not the two spans were active at the same time.
+func (c Capture) Overlaps(other Capture) bool {
+	return c.start < other.end && other.start < c.end
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf("[%.2f - %.2f] %s", c.start, c.end, c.label)
+}
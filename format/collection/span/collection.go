@@ -0,0 +1,157 @@
+package span
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+)
+
+type Collection struct {
+	name     string
+	metadata metadata.Block
+	captures []Capture
+}
+
+func NewCollection(name string, captures []Capture) Collection {
+	return Collection{
+		name:     name,
+		metadata: metadata.EmptyBlock(),
+		captures: captures,
+	}
+}
+
+func (c Collection) Name() string {
+	return c.name
+}
+
+func (c Collection) Metadata() metadata.Block {
+	return c.metadata
+}
+
+func (c Collection) WithMetadata(block metadata.Block) format.CaptureCollection {
+	c.metadata = block
+	return c
+}
+
+func (Collection) Signature() string {
+	return "recolude.span"
+}
+
+func (c Collection) Captures() []format.Capture {
+	returnVal := make([]format.Capture, len(c.captures))
+	for i := range c.captures {
+		returnVal[i] = c.captures[i]
+	}
+	return returnVal
+}
+
+// Slice keeps every span that was active at some point between beginning and
+// end, clipping the ones that cross either boundary.
+func (c Collection) Slice(beginning, end float64) format.CaptureCollection {
+	slicedCaptures := make([]Capture, 0)
+	for _, capture := range c.Overlapping(beginning, end) {
+		slicedCaptures = append(slicedCaptures, NewCapture(
+			math.Max(capture.Start(), beginning),
+			math.Min(capture.End(), end),
+			capture.Label(),
+			capture.Metadata(),
+		))
+	}
+	return NewCollection(c.Name(), slicedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) MapTime(mapping func(time float64) float64) format.CaptureCollection {
+	mappedCaptures := make([]Capture, len(c.captures))
+	for i, capture := range c.captures {
+		capture.start = mapping(capture.start)
+		capture.end = mapping(capture.end)
+		mappedCaptures[i] = capture
+	}
+	return NewCollection(c.Name(), mappedCaptures).WithMetadata(c.metadata)
+}
+
+func (c Collection) Append(other format.CaptureCollection) (format.CaptureCollection, error) {
+	appended, ok := other.(Collection)
+	if !ok {
+		return nil, fmt.Errorf("can not append %s collection to %s collection", other.Signature(), c.Signature())
+	}
+
+	captures := make([]Capture, 0, len(c.captures)+len(appended.captures))
+	captures = append(captures, c.captures...)
+	captures = append(captures, appended.captures...)
+	return NewCollection(c.Name(), captures).WithMetadata(c.metadata), nil
+}
+
+func (c Collection) Start() float64 {
+	return c.captures[0].Start()
+}
+
+// End is the latest time any span in the collection ended.
+func (c Collection) End() float64 {
+	end := math.Inf(-1)
+	for _, capture := range c.captures {
+		end = math.Max(end, capture.End())
+	}
+	return end
+}
+
+func (c Collection) Length() int {
+	return len(c.captures)
+}
+
+func (c Collection) CaptureAt(index int) format.Capture {
+	return c.captures[index]
+}
+
+// ActiveAt returns all spans that were active at the time provided.
+func (c Collection) ActiveAt(time float64) []Capture {
+	active := make([]Capture, 0)
+	for _, capture := range c.captures {
+		if capture.Contains(time) {
+			active = append(active, capture)
+		}
+	}
+	return active
+}
+
+// Overlapping returns all spans that were active at some point between
+// beginning and end.
+func (c Collection) Overlapping(beginning, end float64) []Capture {
+	window := NewCapture(beginning, end, "", metadata.EmptyBlock())
+	overlapping := make([]Capture, 0)
+	for _, capture := range c.captures {
+		// Zero length spans can't overlap anything, but still happened
+		// within the window if it contains their start
+		if capture.Overlaps(window) || (capture.Duration() == 0 && window.Contains(capture.Start())) {
+			overlapping = append(overlapping, capture)
+		}
+	}
+	return overlapping
+}
+
+// WithLabel returns all spans with the label provided.
+func (c Collection) WithLabel(label string) []Capture {
+	labeled := make([]Capture, 0)
+	for _, capture := range c.captures {
+		if capture.Label() == label {
+			labeled = append(labeled, capture)
+		}
+	}
+	return labeled
+}
+
+// OverlappingPairs returns the indexes of every pair of spans within the
+// collection that were active at the same time.
+func (c Collection) OverlappingPairs() [][2]int {
+	pairs := make([][2]int, 0)
+	for i := range c.captures {
+		for j := i + 1; j < len(c.captures); j++ {
+			if c.captures[i].Overlaps(c.captures[j]) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,111 @@
+package span_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func abilities() span.Collection {
+	block := metadata.NewBlock(map[string]metadata.Property{
+		"caster": metadata.NewStringProperty("player"),
+	})
+	return span.NewCollection("Abilities", []span.Capture{
+		span.NewCapture(1, 4, "Shield", block),
+		span.NewCapture(3, 5, "Sprint", block),
+		span.NewCapture(6, 6, "Blink", block),
+		span.NewCapture(8, 12, "Shield", block),
+	})
+}
+
+func Test_Capture(t *testing.T) {
+	capture := span.NewCapture(1, 4, "Shield", metadata.EmptyBlock())
+
+	assert.Equal(t, 1.0, capture.Time())
+	assert.Equal(t, 1.0, capture.Start())
+	assert.Equal(t, 4.0, capture.End())
+	assert.Equal(t, 3.0, capture.Duration())
+	assert.Equal(t, "Shield", capture.Label())
+	assert.Equal(t, "[1.00 - 4.00] Shield", capture.String())
+	assert.True(t, capture.Contains(1))
+	assert.True(t, capture.Contains(3.9))
+	assert.False(t, capture.Contains(4))
+	assert.True(t, capture.Overlaps(span.NewCapture(3, 5, "", metadata.EmptyBlock())))
+	assert.False(t, capture.Overlaps(span.NewCapture(4, 5, "", metadata.EmptyBlock())))
+}
+
+func Test_Collection(t *testing.T) {
+	collection := abilities()
+
+	assert.Equal(t, "Abilities", collection.Name())
+	assert.Equal(t, "recolude.span", collection.Signature())
+	assert.Equal(t, 4, collection.Length())
+	assert.Equal(t, 1.0, collection.Start())
+	assert.Equal(t, 12.0, collection.End())
+}
+
+func Test_Collection_Queries(t *testing.T) {
+	collection := abilities()
+
+	active := collection.ActiveAt(3.5)
+	if assert.Len(t, active, 2) {
+		assert.Equal(t, "Shield", active[0].Label())
+		assert.Equal(t, "Sprint", active[1].Label())
+	}
+
+	overlapping := collection.Overlapping(4.5, 9)
+	if assert.Len(t, overlapping, 3) {
+		assert.Equal(t, "Sprint", overlapping[0].Label())
+		assert.Equal(t, "Blink", overlapping[1].Label())
+		assert.Equal(t, "Shield", overlapping[2].Label())
+	}
+
+	assert.Len(t, collection.WithLabel("Shield"), 2)
+	assert.Len(t, collection.WithLabel("Dash"), 0)
+
+	assert.Equal(t, [][2]int{{0, 1}}, collection.OverlappingPairs())
+}
+
+func Test_Collection_SliceClipsSpans(t *testing.T) {
+	// ACT ====================================================================
+	sliced := abilities().Slice(2, 10).(span.Collection)
+
+	// ASSERT =================================================================
+	if assert.Equal(t, 4, sliced.Length()) {
+		assert.Equal(t, 2.0, sliced.CaptureAt(0).(span.Capture).Start())
+		assert.Equal(t, 4.0, sliced.CaptureAt(0).(span.Capture).End())
+		assert.Equal(t, 3.0, sliced.CaptureAt(1).(span.Capture).Start())
+		assert.Equal(t, 5.0, sliced.CaptureAt(1).(span.Capture).End())
+		assert.Equal(t, 6.0, sliced.CaptureAt(2).(span.Capture).Start())
+		assert.Equal(t, 8.0, sliced.CaptureAt(3).(span.Capture).Start())
+		assert.Equal(t, 10.0, sliced.CaptureAt(3).(span.Capture).End())
+		assert.Equal(t, "Shield", sliced.CaptureAt(3).(span.Capture).Label())
+	}
+}
+
+func Test_Collection_MapTimeMovesBothEnds(t *testing.T) {
+	// ACT ====================================================================
+	mapped := abilities().MapTime(func(time float64) float64 { return time*2 + 1 }).(span.Collection)
+
+	// ASSERT =================================================================
+	if assert.Equal(t, 4, mapped.Length()) {
+		assert.Equal(t, 3.0, mapped.CaptureAt(0).(span.Capture).Start())
+		assert.Equal(t, 9.0, mapped.CaptureAt(0).(span.Capture).End())
+		assert.Equal(t, "Shield", mapped.CaptureAt(0).(span.Capture).Label())
+		assert.Equal(t, 25.0, mapped.CaptureAt(3).(span.Capture).End())
+	}
+	assert.Equal(t, "player", mapped.CaptureAt(0).(span.Capture).Metadata().Mapping()["caster"].String())
+	assert.Equal(t, 1.0, abilities().CaptureAt(0).Time())
+}
+
+func Test_Collection_FormatSliceUnbounded(t *testing.T) {
+	rec := format.NewRecording("", "", []format.CaptureCollection{abilities()}, nil, metadata.EmptyBlock(), nil, nil)
+
+	sliced := format.Slice(rec, format.BeginningOfSlice(math.Inf(-1)), format.EndOfSlice(math.Inf(1)))
+
+	assert.Equal(t, abilities(), sliced.CaptureCollections()[0])
+}
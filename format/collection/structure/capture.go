@@ -0,0 +1,45 @@
+package structure
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capture holds a value for every field of its collection's schema, ordered
+// the same as the schema's fields.
+type Capture struct {
+	time   float64
+	values []interface{}
+}
+
+// NewCapture copies the values provided, so changing the slice afterwards
+// leaves the capture as it was.
+func NewCapture(time float64, values []interface{}) Capture {
+	return Capture{
+		time:   time,
+		values: append([]interface{}(nil), values...),
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+// Values returns a copy of the value of every field, so a capture can't be
+// changed once its schema has checked it.
+func (c Capture) Values() []interface{} {
+	return append([]interface{}(nil), c.values...)
+}
+
+// Value returns the value of the field at the index provided.
+func (c Capture) Value(index int) interface{} {
+	return c.values[index]
+}
+
+func (c Capture) String() string {
+	values := make([]string, len(c.values))
+	for i, v := range c.values {
+		values[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("[%.2f] - %s", c.time, strings.Join(values, ", "))
+}
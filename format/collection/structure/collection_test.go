@@ -0,0 +1,50 @@
+package structure_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Collection(t *testing.T) {
+	// ARRANGE ================================================================
+	schema, _ := structure.NewSchema([]structure.Field{
+		{Name: "health", Type: structure.Float},
+		{Name: "zone", Type: structure.String},
+	})
+	collection := structure.NewCollection("Player", schema, []structure.Capture{
+		structure.NewCapture(1, []interface{}{100.0, "spawn"}),
+		structure.NewCapture(2, []interface{}{80.0, "field"}),
+		structure.NewCapture(3, []interface{}{60.0, "field"}),
+	})
+
+	// ACT ====================================================================
+	sliced := collection.Slice(2, 3).(structure.Collection)
+
+	// ASSERT =================================================================
+	assert.Equal(t, "Player", collection.Name())
+	assert.Equal(t, "recolude.struct", collection.Signature())
+	assert.Equal(t, []interface{}{"spawn", "field", "field"}, collection.Column(1))
+	assert.Equal(t, "[2.00] - 80, field", collection.CaptureAt(1).String())
+	assert.Equal(t, 1.0, collection.Start())
+	assert.Equal(t, 3.0, collection.End())
+
+	assert.Equal(t, schema, sliced.Schema())
+	if assert.Equal(t, 1, sliced.Length()) {
+		assert.Equal(t, 80.0, sliced.CaptureAt(0).(structure.Capture).Value(0))
+	}
+}
+
+func Test_Capture_ValuesCanNotBeChanged(t *testing.T) {
+	// ARRANGE ================================================================
+	values := []interface{}{100.0, "spawn"}
+	capture := structure.NewCapture(1, values)
+
+	// ACT ====================================================================
+	values[0] = "not a number"
+	capture.Values()[1] = 12.0
+
+	// ASSERT =================================================================
+	assert.Equal(t, []interface{}{100.0, "spawn"}, capture.Values())
+}
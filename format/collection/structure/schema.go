@@ -0,0 +1,146 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EliCDavis/vector"
+)
+
+// FieldType is the kind of value a field of a schema holds.
+type FieldType int
+
+const (
+	// Float fields hold float64 values
+	Float FieldType = iota
+
+	// Int fields hold int64 values
+	Int
+
+	// Bool fields hold bool values
+	Bool
+
+	// Vector3 fields hold vector.Vector3 values
+	Vector3
+
+	// String fields hold string values
+	String
+
+	// Enum fields hold the int index of one of the field's options
+	Enum
+)
+
+var fieldTypeNames = []string{"float", "int", "bool", "vector3", "string", "enum"}
+
+func (t FieldType) String() string {
+	if t < Float || t > Enum {
+		return fmt.Sprintf("FieldType(%d)", int(t))
+	}
+	return fieldTypeNames[t]
+}
+
+// ParseFieldType converts the name of a field type, as returned by String,
+// back into a FieldType.
+func ParseFieldType(name string) (FieldType, error) {
+	for i, typeName := range fieldTypeNames {
+		if typeName == name {
+			return FieldType(i), nil
+		}
+	}
+	return Float, fmt.Errorf("unknown struct field type: '%s'", name)
+}
+
+// Field is a single named and typed value within every capture of a struct
+// collection.
+type Field struct {
+	Name string
+	Type FieldType
+
+	// Options are the members an enum field can take on. Ignored by all other
+	// field types.
+	Options []string
+}
+
+// Schema declares the fields every capture of a struct collection holds, in
+// the order their values appear within a capture.
+type Schema struct {
+	fields []Field
+}
+
+func NewSchema(fields []Field) (Schema, error) {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field.Name == "" {
+			return Schema{}, errors.New("struct field name must not be empty")
+		}
+
+		if seen[field.Name] {
+			return Schema{}, fmt.Errorf("struct field %s declared more than once", field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Type < Float || field.Type > Enum {
+			return Schema{}, fmt.Errorf("struct field %s has unknown type %d", field.Name, int(field.Type))
+		}
+
+		if field.Type == Enum && len(field.Options) == 0 {
+			return Schema{}, fmt.Errorf("enum field %s must have options", field.Name)
+		}
+	}
+	return Schema{fields: fields}, nil
+}
+
+func (s Schema) Fields() []Field {
+	return s.fields
+}
+
+// FieldIndex returns the index of the field with the name provided, or -1 if
+// the schema has no such field.
+func (s Schema) FieldIndex(name string) int {
+	for i, field := range s.fields {
+		if field.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Check ensures the values provided line up with the fields of the schema,
+// both in count and in type.
+func (s Schema) Check(values []interface{}) error {
+	if len(values) != len(s.fields) {
+		return fmt.Errorf("struct capture has %d values but schema has %d fields", len(values), len(s.fields))
+	}
+
+	for i, field := range s.fields {
+		ok := false
+		switch field.Type {
+		case Float:
+			_, ok = values[i].(float64)
+
+		case Int:
+			_, ok = values[i].(int64)
+
+		case Bool:
+			_, ok = values[i].(bool)
+
+		case Vector3:
+			_, ok = values[i].(vector.Vector3)
+
+		case String:
+			_, ok = values[i].(string)
+
+		case Enum:
+			var option int
+			option, ok = values[i].(int)
+			if ok && (option < 0 || option >= len(field.Options)) {
+				return fmt.Errorf("enum field %s value %d out of range of %d options", field.Name, option, len(field.Options))
+			}
+		}
+
+		if !ok {
+			return fmt.Errorf("struct field %s expects a %s value but was given %T", field.Name, field.Type, values[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package structure_test
+
+import (
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewSchema(t *testing.T) {
+	tests := map[string]struct {
+		fields []structure.Field
+		err    string
+	}{
+		"empty": {fields: nil},
+		"valid": {fields: []structure.Field{
+			{Name: "health", Type: structure.Float},
+			{Name: "weapon", Type: structure.Enum, Options: []string{"sword", "bow"}},
+		}},
+		"missing name": {
+			fields: []structure.Field{{Type: structure.Float}},
+			err:    "struct field name must not be empty",
+		},
+		"duplicate": {
+			fields: []structure.Field{{Name: "a", Type: structure.Float}, {Name: "a", Type: structure.Int}},
+			err:    "struct field a declared more than once",
+		},
+		"unknown type": {
+			fields: []structure.Field{{Name: "a", Type: structure.FieldType(42)}},
+			err:    "struct field a has unknown type 42",
+		},
+		"enum without options": {
+			fields: []structure.Field{{Name: "a", Type: structure.Enum}},
+			err:    "enum field a must have options",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, err := structure.NewSchema(tc.fields)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.fields, schema.Fields())
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func Test_ParseFieldType(t *testing.T) {
+	for _, fieldType := range []structure.FieldType{structure.Float, structure.Int, structure.Bool, structure.Vector3, structure.String, structure.Enum} {
+		parsed, err := structure.ParseFieldType(fieldType.String())
+		assert.NoError(t, err)
+		assert.Equal(t, fieldType, parsed)
+	}
+
+	_, err := structure.ParseFieldType("quaternion")
+	assert.EqualError(t, err, "unknown struct field type: 'quaternion'")
+}
+
+func Test_Schema_Check(t *testing.T) {
+	// ARRANGE ================================================================
+	schema, err := structure.NewSchema([]structure.Field{
+		{Name: "health", Type: structure.Float},
+		{Name: "ammo", Type: structure.Int},
+		{Name: "grounded", Type: structure.Bool},
+		{Name: "velocity", Type: structure.Vector3},
+		{Name: "zone", Type: structure.String},
+		{Name: "weapon", Type: structure.Enum, Options: []string{"sword", "bow"}},
+	})
+	assert.NoError(t, err)
+
+	// ACT/ASSERT =============================================================
+	assert.NoError(t, schema.Check([]interface{}{1.0, int64(2), true, vector.Vector3Zero(), "a", 1}))
+	assert.EqualError(t, schema.Check([]interface{}{1.0}), "struct capture has 1 values but schema has 6 fields")
+	assert.EqualError(
+		t,
+		schema.Check([]interface{}{1.0, 2, true, vector.Vector3Zero(), "a", 1}),
+		"struct field ammo expects a int value but was given int",
+	)
+	assert.EqualError(
+		t,
+		schema.Check([]interface{}{1.0, int64(2), true, vector.Vector3Zero(), "a", 2}),
+		"enum field weapon value 2 out of range of 2 options",
+	)
+	assert.Equal(t, 3, schema.FieldIndex("velocity"))
+	assert.Equal(t, -1, schema.FieldIndex("mana"))
+}
@@ -0,0 +1,31 @@
+package vector2
+
+import (
+	"fmt"
+
+	"github.com/EliCDavis/vector"
+)
+
+type Capture struct {
+	time  float64
+	value vector.Vector2
+}
+
+func NewCapture(time, x, y float64) Capture {
+	return Capture{
+		time:  time,
+		value: vector.NewVector2(x, y),
+	}
+}
+
+func (c Capture) Time() float64 {
+	return c.time
+}
+
+func (c Capture) String() string {
+	return fmt.Sprintf("[%.2f] - %.2f, %.2f", c.time, c.value.X(), c.value.Y())
+}
+
+func (c Capture) Value() vector.Vector2 {
+	return c.value
+}
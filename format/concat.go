@@ -0,0 +1,163 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/recolude/rap/format/metadata"
+)
+
+type ConcatOption func(options *concatOptions)
+
+type concatOptions struct {
+	rebase bool
+	gap    float64
+}
+
+// RebaseInConcat shifts the captures of every recording after the first so
+// it begins right where the recording before it ended. Useful when each
+// recording's clock started over from zero, which Concat otherwise refuses
+// to join.
+func RebaseInConcat(rebase bool) ConcatOption {
+	return func(options *concatOptions) {
+		options.rebase = rebase
+	}
+}
+
+// GapInConcat sets the seconds left between one recording ending and the
+// next beginning when rebasing.
+func GapInConcat(seconds float64) ConcatOption {
+	return func(options *concatOptions) {
+		options.gap = seconds
+	}
+}
+
+// matchingChild finds the child a subject should be joined with, preferring
+// a shared ID and falling back to a shared name.
+func matchingChild(children []Recording, joined []bool, subject Recording) int {
+	if subject.ID() != "" {
+		for i, child := range children {
+			if !joined[i] && child.ID() == subject.ID() {
+				return i
+			}
+		}
+	}
+
+	for i, child := range children {
+		if !joined[i] && child.Name() == subject.Name() {
+			return i
+		}
+	}
+	return -1
+}
+
+func concatPair(first, second Recording, path string) (Recording, error) {
+	block, err := mergeMetadata([]metadata.Block{first.Metadata(), second.Metadata()}, PreferFirstMetadata, "")
+	if err != nil {
+		return nil, err
+	}
+
+	collections := append(make([]CaptureCollection, 0, len(first.CaptureCollections())), first.CaptureCollections()...)
+	joinedCollections := make([]bool, len(collections))
+	for _, collection := range second.CaptureCollections() {
+		index := -1
+		for i, existing := range collections {
+			if i < len(joinedCollections) && !joinedCollections[i] && existing.Name() == collection.Name() && existing.Signature() == collection.Signature() {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			collections = append(collections, collection)
+			continue
+		}
+
+		existing := collections[index]
+		if existing.Length() > 0 && collection.Length() > 0 {
+			last, next := existing.CaptureAt(existing.Length()-1).Time(), collection.CaptureAt(0).Time()
+			if next < last {
+				return nil, fmt.Errorf("%s#%s: captures starting at %v would come before captures ending at %v, rebase the recordings to join them", path, collection.Name(), next, last)
+			}
+		}
+
+		appender, ok := existing.(Appender)
+		if !ok {
+			return nil, fmt.Errorf("%s#%s: %s collections can not be appended to", path, collection.Name(), collection.Signature())
+		}
+
+		collections[index], err = appender.Append(collection)
+		if err != nil {
+			return nil, fmt.Errorf("%s#%s: %w", path, collection.Name(), err)
+		}
+		joinedCollections[index] = true
+	}
+
+	children := append(make([]Recording, 0, len(first.Recordings())), first.Recordings()...)
+	joinedChildren := make([]bool, len(children))
+	for _, child := range second.Recordings() {
+		index := matchingChild(children[:len(joinedChildren)], joinedChildren, child)
+		if index == -1 {
+			children = append(children, child)
+			continue
+		}
+
+		children[index], err = concatPair(children[index], child, path+"/"+children[index].Name())
+		if err != nil {
+			return nil, err
+		}
+		joinedChildren[index] = true
+	}
+
+	return NewRecording(
+		first.ID(),
+		first.Name(),
+		collections,
+		children,
+		block,
+		append(append(make([]Binary, 0), first.Binaries()...), second.Binaries()...),
+		append(append(make([]BinaryReference, 0), first.BinaryReferences()...), second.BinaryReferences()...),
+	), nil
+}
+
+// Concat appends recordings end to end, such as a session that was split
+// across several files by crashes or level loads. Recordings are joined
+// with the first one's ID and name, and their subjects are matched up by ID,
+// or by name when IDs don't match. Collections of matched recordings sharing
+// a name and signature are joined into one, with enum members reconciled
+// across collections. Anything without a match is carried over as is.
+// Joining collections whose captures would go back in time is an error.
+func Concat(recs []Recording, options ...ConcatOption) (Recording, error) {
+	if len(recs) == 0 {
+		return nil, errors.New("concat requires at least one recording")
+	}
+
+	finalOpts := &concatOptions{}
+	for _, opt := range options {
+		opt(finalOpts)
+	}
+
+	joined := recs[0]
+	previousEnd := RecordingEnd(joined)
+	for _, rec := range recs[1:] {
+		start := RecordingStart(rec)
+		if finalOpts.rebase && !math.IsInf(start, 0) && !math.IsInf(previousEnd, 0) {
+			offset := previousEnd + finalOpts.gap - start
+			var err error
+			rec, err = Retime(rec, Shift(offset))
+			if err != nil {
+				return nil, err
+			}
+		}
+		previousEnd = math.Max(previousEnd, RecordingEnd(rec))
+
+		var err error
+		joined, err = concatPair(joined, rec, joined.Name())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return joined, nil
+}
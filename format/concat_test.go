@@ -0,0 +1,160 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func levelRecording(name string, start float64, members []string, states []int, subjects ...format.Recording) format.Recording {
+	stateCaptures := make([]enum.Capture, len(states))
+	for i, state := range states {
+		stateCaptures[i] = enum.NewCapture(start+float64(i), state)
+	}
+
+	return format.NewBuilder().
+		SetID(name).
+		SetName("session").
+		AddCollection(
+			enum.NewCollection("State", members, stateCaptures),
+			event.NewCollection("Events", []event.Capture{
+				event.NewCapture(start, name, metadata.EmptyBlock()),
+			}),
+		).
+		AddChild(subjects...).
+		Build()
+}
+
+func subjectAt(id, name string, times ...float64) format.Recording {
+	captures := make([]position.Capture, len(times))
+	for i, time := range times {
+		captures[i] = position.NewCapture(time, 0, 0, 0)
+	}
+	return format.NewBuilder().
+		SetID(id).
+		SetName(name).
+		AddCollection(position.NewCollection("Position", captures)).
+		Build()
+}
+
+func eventNames(collection format.CaptureCollection) []string {
+	names := make([]string, collection.Length())
+	for i, capture := range collection.Captures() {
+		names[i] = capture.(event.Capture).Name()
+	}
+	return names
+}
+
+func Test_Concat(t *testing.T) {
+	// ARRANGE ================================================================
+	first := levelRecording(
+		"level-1", 0,
+		[]string{"loading", "playing"}, []int{0, 1},
+		subjectAt("p1", "player", 0, 1),
+		subjectAt("e1", "enemy", 1),
+	)
+	second := levelRecording(
+		"level-2", 10,
+		[]string{"playing", "paused", "loading"}, []int{2, 0, 1},
+		subjectAt("p1-new", "player", 10),
+		subjectAt("e1", "renamed enemy", 11),
+		subjectAt("e2", "boss", 12),
+	)
+
+	// ACT ====================================================================
+	joined, err := format.Concat([]format.Recording{first, second})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "level-1", joined.ID())
+	if assert.Len(t, joined.CaptureCollections(), 2) {
+		states := joined.CaptureCollections()[0].(enum.Collection)
+		assert.Equal(t, []string{"loading", "playing", "paused"}, states.EnumMembers())
+
+		values := make([]int, states.Length())
+		for i, capture := range states.Captures() {
+			values[i] = capture.(enum.Capture).Value()
+		}
+		assert.Equal(t, []int{0, 1, 0, 1, 2}, values)
+
+		assert.Equal(t, []string{"level-1", "level-2"}, eventNames(joined.CaptureCollections()[1]))
+	}
+
+	if assert.Len(t, joined.Recordings(), 3) {
+		assert.Equal(t, "p1", joined.Recordings()[0].ID())
+		assert.Equal(t, 3, joined.Recordings()[0].CaptureCollections()[0].Length())
+		assert.Equal(t, "enemy", joined.Recordings()[1].Name())
+		assert.Equal(t, 2, joined.Recordings()[1].CaptureCollections()[0].Length())
+		assert.Equal(t, "boss", joined.Recordings()[2].Name())
+	}
+}
+
+func Test_Concat_Rebase(t *testing.T) {
+	// ARRANGE ================================================================
+	first := levelRecording("level-1", 0, []string{"playing"}, []int{0, 0, 0})
+	second := levelRecording("level-2", 0, []string{"playing"}, []int{0, 0})
+	third := levelRecording("level-3", 0, []string{"playing"}, []int{0})
+
+	// ACT ====================================================================
+	joined, err := format.Concat(
+		[]format.Recording{first, second, third},
+		format.RebaseInConcat(true),
+		format.GapInConcat(0.5),
+	)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	times := make([]float64, 0)
+	for _, capture := range joined.CaptureCollections()[0].Captures() {
+		times = append(times, capture.Time())
+	}
+	assert.Equal(t, []float64{0, 1, 2, 2.5, 3.5, 4}, times)
+	assert.Equal(t, 4.0, format.RecordingEnd(joined))
+}
+
+func Test_Concat_Overlapping(t *testing.T) {
+	// ARRANGE ================================================================
+	first := format.NewBuilder().SetName("session").AddChild(subjectAt("p1", "player", 0, 5)).Build()
+	second := format.NewBuilder().SetName("session").AddChild(subjectAt("p1", "player", 0, 5)).Build()
+
+	// ACT ====================================================================
+	_, err := format.Concat([]format.Recording{first, second})
+	rebased, rebasedErr := format.Concat([]format.Recording{first, second}, format.RebaseInConcat(true))
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "session/player#Position: captures starting at 0 would come before captures ending at 5, rebase the recordings to join them")
+	assert.NoError(t, rebasedErr)
+	if assert.NotNil(t, rebased) && assert.Len(t, rebased.Recordings(), 1) {
+		times := make([]float64, 0)
+		for _, capture := range rebased.Recordings()[0].CaptureCollections()[0].Captures() {
+			times = append(times, capture.Time())
+		}
+		assert.Equal(t, []float64{0, 5, 5, 10}, times)
+	}
+}
+
+func Test_Concat_MismatchedCollections(t *testing.T) {
+	// ARRANGE ================================================================
+	first := format.NewBuilder().
+		SetName("session").
+		AddCollection(floatvec.NewCollection("Hands", []string{"left", "right"}, nil)).
+		Build()
+	second := format.NewBuilder().
+		SetName("session").
+		AddCollection(floatvec.NewCollection("Hands", []string{"left"}, nil)).
+		Build()
+
+	// ACT ====================================================================
+	_, err := format.Concat([]format.Recording{first, second})
+	_, emptyErr := format.Concat(nil)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "session#Hands: can not append float vector collections with different channels")
+	assert.EqualError(t, emptyErr, "concat requires at least one recording")
+}
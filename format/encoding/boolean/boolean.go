@@ -0,0 +1,87 @@
+package boolean
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
+)
+
+// Encoder stores boolean collections as nothing more than their initial
+// state. Every following capture is a transition, so its value is implied by
+// the one before it and only its time needs to be written.
+type Encoder struct{}
+
+func NewEncoder() Encoder {
+	return Encoder{}
+}
+
+func (Encoder) encode(collection format.CaptureCollection) ([]byte, error) {
+	if collection.Length() == 0 {
+		return []byte{}, nil
+	}
+
+	var previous bool
+	for i := 0; i < collection.Length(); i++ {
+		capture, ok := collection.CaptureAt(i).(boolean.Capture)
+		if !ok {
+			return nil, errors.New("capture is not of type bool")
+		}
+
+		if i > 0 && capture.Value() == previous {
+			return nil, fmt.Errorf("bool capture %d in collection %s does not change value", i, collection.Name())
+		}
+		previous = capture.Value()
+	}
+
+	if collection.CaptureAt(0).(boolean.Capture).Value() {
+		return []byte{1}, nil
+	}
+	return []byte{0}, nil
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	allStreamData := make([][]byte, len(collections))
+
+	for i, collection := range collections {
+		s, err := e.encode(collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		allStreamData[i] = s
+	}
+
+	return nil, allStreamData, nil
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	captures := make([]boolean.Capture, len(times))
+	if len(times) == 0 {
+		return boolean.NewCollection(name, captures), nil
+	}
+
+	if len(streamData) == 0 {
+		return nil, errors.New("bool collection missing initial state")
+	}
+
+	value := streamData[0] == 1
+	for i, t := range times {
+		captures[i] = boolean.NewCapture(t, value)
+		value = !value
+	}
+
+	return boolean.NewCollection(name, captures), nil
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.bool"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.bool"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
@@ -0,0 +1,105 @@
+package boolean_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	boolCollection "github.com/recolude/rap/format/collection/boolean"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/encoding/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+type alternatingCollection struct {
+	boolCollection.Collection
+	captures []boolCollection.Capture
+}
+
+func (c alternatingCollection) Length() int {
+	return len(c.captures)
+}
+
+func (c alternatingCollection) CaptureAt(i int) format.Capture {
+	return c.captures[i]
+}
+
+func Test_Bool(t *testing.T) {
+	tests := map[string]struct {
+		captures []boolCollection.Capture
+		times    []float64
+	}{
+		"nil captures": {captures: nil},
+		"0-captures":   {captures: []boolCollection.Capture{}},
+		"1-capture-true": {
+			captures: []boolCollection.Capture{boolCollection.NewCapture(1, true)},
+			times:    []float64{1},
+		},
+		"1-capture-false": {
+			captures: []boolCollection.Capture{boolCollection.NewCapture(1, false)},
+			times:    []float64{1},
+		},
+		"transitions": {
+			captures: []boolCollection.Capture{
+				boolCollection.NewCapture(1, false),
+				boolCollection.NewCapture(2, true),
+				boolCollection.NewCapture(2.5, false),
+				boolCollection.NewCapture(7, true),
+			},
+			times: []float64{1, 2, 2.5, 7},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			collectionIn := boolCollection.NewCollection("visible", tc.captures)
+			encoder := boolean.NewEncoder()
+			assert.Equal(t, "recolude.bool", encoder.Signature())
+			assert.Equal(t, uint(0), encoder.Version())
+			assert.True(t, encoder.Accepts(collectionIn))
+
+			// ACT ============================================================
+			header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+			collectionOut, decodeErr := encoder.Decode("visible", header, collectionData[0], tc.times)
+
+			// ASSERT =========================================================
+			assert.NoError(t, encodeErr)
+			assert.NoError(t, decodeErr)
+			assert.LessOrEqual(t, len(collectionData[0]), 1)
+			if assert.NotNil(t, collectionOut) && assert.Len(t, collectionOut.Captures(), len(tc.captures)) {
+				assert.Equal(t, "visible", collectionOut.Name())
+				for i, c := range collectionOut.Captures() {
+					assert.Equal(t, tc.captures[i], c)
+				}
+			}
+		})
+	}
+}
+
+func Test_Bool_RejectsRepeatedValues(t *testing.T) {
+	collection := alternatingCollection{
+		Collection: boolCollection.NewCollection("visible", nil),
+		captures: []boolCollection.Capture{
+			boolCollection.NewCapture(1, true),
+			boolCollection.NewCapture(2, true),
+		},
+	}
+
+	_, _, err := boolean.NewEncoder().Encode([]format.CaptureCollection{collection})
+
+	assert.EqualError(t, err, "bool capture 1 in collection visible does not change value")
+}
+
+func Test_Bool_RejectsOtherCaptures(t *testing.T) {
+	encoder := boolean.NewEncoder()
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "capture is not of type bool")
+}
+
+func Test_Bool_MissingInitialState(t *testing.T) {
+	_, err := boolean.NewEncoder().Decode("visible", nil, []byte{}, []float64{1})
+	assert.EqualError(t, err, "bool collection missing initial state")
+}
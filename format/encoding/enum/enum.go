@@ -4,16 +4,40 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/collection/enum"
 	rapbinary "github.com/recolude/rap/internal/io/binary"
 )
 
-type Encoder struct{}
+type StorageTechnique int
 
-func NewEncoder() Encoder {
-	return Encoder{}
+const (
+	// Raw writes a single uvarint per capture
+	Raw StorageTechnique = iota
+
+	// RunLength writes a value followed by the number of consecutive captures
+	// that share it. Ideal for enums that rarely change.
+	RunLength
+
+	// BitPacked stores each capture in the fewest bits able to represent all
+	// enum members of the collection
+	BitPacked
+)
+
+// techniqueMarker is appended to the header to let the decoder know the
+// stream data contains a storage technique byte. Version 0 of this encoder
+// did not write one.
+const techniqueMarker byte = 1
+
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
 }
 
 func (p Encoder) Accepts(stream format.CaptureCollection) bool {
@@ -25,7 +49,49 @@ func (p Encoder) Signature() string {
 }
 
 func (p Encoder) Version() uint {
-	return 0
+	return 1
+}
+
+func bitsPerCapture(numMembers int) int {
+	if numMembers < 2 {
+		return 0
+	}
+	return rapbinary.BitsRequired(uint64(numMembers - 1))
+}
+
+func encodeRaw(out io.Writer, captures []enum.Capture) {
+	valueBuf := make([]byte, binary.MaxVarintLen64)
+	for _, c := range captures {
+		read := binary.PutUvarint(valueBuf, uint64(c.Value()))
+		out.Write(valueBuf[:read])
+	}
+}
+
+func encodeRunLength(out io.Writer, captures []enum.Capture) {
+	valueBuf := make([]byte, binary.MaxVarintLen64)
+	for i := 0; i < len(captures); {
+		runLength := 1
+		for i+runLength < len(captures) && captures[i+runLength].Value() == captures[i].Value() {
+			runLength++
+		}
+
+		read := binary.PutUvarint(valueBuf, uint64(captures[i].Value()))
+		out.Write(valueBuf[:read])
+
+		read = binary.PutUvarint(valueBuf, uint64(runLength))
+		out.Write(valueBuf[:read])
+
+		i += runLength
+	}
+}
+
+func encodeBitPacked(out io.Writer, captures []enum.Capture, numMembers int) {
+	bits := bitsPerCapture(numMembers)
+	writer := rapbinary.NewBitWriter()
+	for _, c := range captures {
+		writer.Write(uint64(c.Value()), bits)
+	}
+	out.Write(writer.Bytes())
 }
 
 func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, error) {
@@ -50,15 +116,36 @@ func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, e
 		// Write Enum Members indexes
 		streamDataBuffers[bufferIndex].Write(rapbinary.UvarintArrayToBytes(indexMapping))
 
-		for _, c := range stream.Captures() {
+		captures := make([]enum.Capture, len(stream.Captures()))
+		for i, c := range stream.Captures() {
 			enumCapture, ok := c.(enum.Capture)
 			if !ok {
 				return nil, nil, errors.New("capture is not of type enum")
 			}
+			if enumCapture.Value() < 0 || enumCapture.Value() >= len(indexMapping) {
+				return nil, nil, fmt.Errorf("enum value %d out of range of %d members in stream %s", enumCapture.Value(), len(indexMapping), stream.Name())
+			}
+			captures[i] = enumCapture
+		}
+
+		// Write technique
+		streamDataBuffers[bufferIndex].WriteByte(byte(p.technique))
+
+		switch p.technique {
+		case Raw:
+			encodeRaw(&streamDataBuffers[bufferIndex], captures)
+			break
 
-			valueBuf := make([]byte, binary.MaxVarintLen64)
-			read := binary.PutUvarint(valueBuf, uint64(enumCapture.Value()))
-			streamDataBuffers[bufferIndex].Write(valueBuf[:read])
+		case RunLength:
+			encodeRunLength(&streamDataBuffers[bufferIndex], captures)
+			break
+
+		case BitPacked:
+			encodeBitPacked(&streamDataBuffers[bufferIndex], captures, len(indexMapping))
+			break
+
+		default:
+			return nil, nil, fmt.Errorf("unknown enum encoding technique: %d", int(p.technique))
 		}
 	}
 
@@ -69,6 +156,7 @@ func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, e
 		headerMembers[val] = key
 	}
 	headerBuffer.Write(rapbinary.StringArrayToBytes(headerMembers))
+	headerBuffer.WriteByte(techniqueMarker)
 
 	streamData := make([][]byte, len(streams))
 	for i, buffer := range streamDataBuffers {
@@ -78,8 +166,73 @@ func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, e
 	return headerBuffer.Bytes(), streamData, nil
 }
 
+func decodeRaw(in io.ByteReader, numCaptures int) ([]uint64, error) {
+	values := make([]uint64, numCaptures)
+	for i := 0; i < numCaptures; i++ {
+		value, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func decodeRunLength(in io.ByteReader, numCaptures int) ([]uint64, error) {
+	values := make([]uint64, 0, numCaptures)
+	for len(values) < numCaptures {
+		value, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+
+		runLength, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+
+		if runLength == 0 || runLength > uint64(numCaptures-len(values)) {
+			return nil, fmt.Errorf("invalid enum run length: %d", runLength)
+		}
+
+		for i := uint64(0); i < runLength; i++ {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func decodeBitPacked(in io.Reader, numCaptures int, numMembers int) ([]uint64, error) {
+	bits := bitsPerCapture(numMembers)
+	reader := rapbinary.NewBitReader(in)
+	values := make([]uint64, numCaptures)
+	for i := 0; i < numCaptures; i++ {
+		value, err := reader.Read(bits)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func readHeader(header []byte) (members []string, hasTechnique bool, err error) {
+	headerReader := bytes.NewReader(header)
+	members, _, err = rapbinary.ReadStringArray(headerReader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if headerReader.Len() == 0 {
+		return members, false, nil
+	}
+
+	marker, err := headerReader.ReadByte()
+	return members, marker == techniqueMarker, err
+}
+
 func (p Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
-	allEnumMembers, _, err := rapbinary.ReadStringArray(bytes.NewReader(header))
+	allEnumMembers, hasTechnique, err := readHeader(header)
 	if err != nil {
 		return nil, err
 	}
@@ -90,14 +243,52 @@ func (p Encoder) Decode(name string, header []byte, streamData []byte, times []f
 
 	enumMembers := make([]string, len(enumMemberIndexes))
 	for i, indeces := range enumMemberIndexes {
+		if int(indeces) >= len(allEnumMembers) {
+			return nil, fmt.Errorf("enum member index %d out of range of header", indeces)
+		}
 		enumMembers[i] = allEnumMembers[indeces]
 	}
 
+	// Version 0 of the encoder only ever wrote raw uvarints
+	technique := Raw
+	if hasTechnique {
+		techniqueByte, _ := reader.ReadByte()
+		technique = StorageTechnique(techniqueByte)
+	}
+
+	var values []uint64
+	switch technique {
+	case Raw:
+		values, err = decodeRaw(reader, len(times))
+		break
+
+	case RunLength:
+		values, err = decodeRunLength(reader, len(times))
+		break
+
+	case BitPacked:
+		values, err = decodeBitPacked(reader, len(times), len(enumMembers))
+		break
+
+	default:
+		return nil, fmt.Errorf("unknown enum encoding technique: %d", int(technique))
+	}
+
+	if reader.Error() != nil {
+		return nil, reader.Error()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
 	captures := make([]enum.Capture, len(times))
-	for i := 0; i < len(times); i++ {
-		value, _ := binary.ReadUvarint(reader)
+	for i, value := range values {
+		if value >= uint64(len(enumMembers)) {
+			return nil, fmt.Errorf("enum value %d out of range of %d members in stream %s", value, len(enumMembers), name)
+		}
 		captures[i] = enum.NewCapture(times[i], int(value))
 	}
 
-	return enum.NewCollection(name, enumMembers, captures), reader.Error()
+	return enum.NewCollection(name, enumMembers, captures), nil
 }
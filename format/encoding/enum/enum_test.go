@@ -1,11 +1,13 @@
 package enum_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/recolude/rap/format"
 	enumCollection "github.com/recolude/rap/format/collection/enum"
 	"github.com/recolude/rap/format/encoding/enum"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,49 +37,71 @@ func Test_Singleenum(t *testing.T) {
 			},
 			times: []float64{1.2, 1.3},
 		},
+		"repeating-enums": {
+			streamName:  "lifecycle",
+			enumMembers: []string{"a", "b", "c", "d", "e"},
+			captures: []enumCollection.Capture{
+				enumCollection.NewCapture(1, 4),
+				enumCollection.NewCapture(2, 4),
+				enumCollection.NewCapture(3, 4),
+				enumCollection.NewCapture(4, 0),
+				enumCollection.NewCapture(5, 2),
+				enumCollection.NewCapture(6, 2),
+				enumCollection.NewCapture(7, 3),
+			},
+			times: []float64{1, 2, 3, 4, 5, 6, 7},
+		},
 	}
 
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			collectionIn := enumCollection.NewCollection(tc.streamName, tc.enumMembers, tc.captures)
-			encoder := enum.NewEncoder()
-			assert.Equal(t, "recolude.enum", encoder.Signature())
-			assert.Equal(t, uint(0), encoder.Version())
-			assert.True(t, encoder.Accepts(collectionIn))
-
-			// ACT ====================================================================
-			header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
-			collectionOut, decodeErr := encoder.Decode(tc.streamName, header, collectionData[0], tc.times)
-
-			// ASSERT =================================================================
-			assert.NoError(t, encodeErr)
-			assert.NoError(t, decodeErr)
-			assert.NotNil(t, collectionOut)
-			assert.Len(t, collectionData, 1)
-			assert.Equal(t, tc.streamName, collectionOut.Name())
-			if assert.NotNil(t, collectionOut) {
-				assert.Equal(t, collectionIn.Name(), collectionOut.Name())
-				if assert.Len(t, collectionOut.Captures(), len(collectionIn.Captures())) {
-
-					enmstr := collectionOut.(enumCollection.Collection)
-					if assert.Len(t, enmstr.EnumMembers(), len(tc.enumMembers)) {
-						for i, mem := range tc.enumMembers {
-							assert.Equal(t, mem, enmstr.EnumMembers()[i])
-						}
-					}
+	techniques := map[string]enum.StorageTechnique{
+		"Raw":       enum.Raw,
+		"RunLength": enum.RunLength,
+		"BitPacked": enum.BitPacked,
+	}
 
-					for i, c := range collectionOut.Captures() {
-						enumCapture, ok := c.(enumCollection.Capture)
-						if assert.True(t, ok) == false {
-							break
+	for techniqueName, technique := range techniques {
+		for name, tc := range tests {
+			t.Run(fmt.Sprintf("%s/%s", techniqueName, name), func(t *testing.T) {
+				collectionIn := enumCollection.NewCollection(tc.streamName, tc.enumMembers, tc.captures)
+				encoder := enum.NewEncoder(technique)
+				assert.Equal(t, "recolude.enum", encoder.Signature())
+				assert.Equal(t, uint(1), encoder.Version())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ====================================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode(tc.streamName, header, collectionData[0], tc.times)
+
+				// ASSERT =================================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				assert.NotNil(t, collectionOut)
+				assert.Len(t, collectionData, 1)
+				assert.Equal(t, tc.streamName, collectionOut.Name())
+				if assert.NotNil(t, collectionOut) {
+					assert.Equal(t, collectionIn.Name(), collectionOut.Name())
+					if assert.Len(t, collectionOut.Captures(), len(collectionIn.Captures())) {
+
+						enmstr := collectionOut.(enumCollection.Collection)
+						if assert.Len(t, enmstr.EnumMembers(), len(tc.enumMembers)) {
+							for i, mem := range tc.enumMembers {
+								assert.Equal(t, mem, enmstr.EnumMembers()[i])
+							}
 						}
 
-						assert.Equal(t, tc.captures[i].Time(), enumCapture.Time(), "times are not equal: %.2f != %.2f", tc.captures[i].Time(), enumCapture.Time())
-						assert.Equal(t, tc.captures[i].Value(), enumCapture.Value())
+						for i, c := range collectionOut.Captures() {
+							enumCapture, ok := c.(enumCollection.Capture)
+							if assert.True(t, ok) == false {
+								break
+							}
+
+							assert.Equal(t, tc.captures[i].Time(), enumCapture.Time(), "times are not equal: %.2f != %.2f", tc.captures[i].Time(), enumCapture.Time())
+							assert.Equal(t, tc.captures[i].Value(), enumCapture.Value())
+						}
 					}
 				}
-			}
-		})
+			})
+		}
 	}
 }
 
@@ -97,7 +121,7 @@ func Test_MultipleStreamsAtOnce(t *testing.T) {
 		enumCollection.NewCollection("first", []string{"ccc", "dddd"}, []enumCollection.Capture{enumCollection.NewCapture(4, 0)}),
 		enumCollection.NewCollection("first", []string{"a"}, []enumCollection.Capture{enumCollection.NewCapture(5, 0)}),
 	}
-	encoder := enum.NewEncoder()
+	encoder := enum.NewEncoder(enum.BitPacked)
 
 	// ACT ====================================================================
 	header, collectionData, encodeErr := encoder.Encode(collectionsIn)
@@ -138,3 +162,68 @@ func Test_MultipleStreamsAtOnce(t *testing.T) {
 		}
 	}
 }
+
+func Test_RunLengthShrinksRepeatedValues(t *testing.T) {
+	captures := make([]enumCollection.Capture, 1000)
+	times := make([]float64, len(captures))
+	for i := range captures {
+		captures[i] = enumCollection.NewCapture(float64(i), (i/250)%3)
+		times[i] = float64(i)
+	}
+	collectionIn := enumCollection.NewCollection("state", []string{"idle", "walk", "run"}, captures)
+
+	_, rawData, rawErr := enum.NewEncoder(enum.Raw).Encode([]format.CaptureCollection{collectionIn})
+	_, bitData, bitErr := enum.NewEncoder(enum.BitPacked).Encode([]format.CaptureCollection{collectionIn})
+	header, rleData, rleErr := enum.NewEncoder(enum.RunLength).Encode([]format.CaptureCollection{collectionIn})
+
+	assert.NoError(t, rawErr)
+	assert.NoError(t, bitErr)
+	assert.NoError(t, rleErr)
+	assert.Less(t, len(bitData[0]), len(rawData[0]))
+	assert.Less(t, len(rleData[0]), len(bitData[0]))
+
+	collectionOut, err := enum.NewEncoder(enum.Raw).Decode("state", header, rleData[0], times)
+	if assert.NoError(t, err) {
+		for i, c := range collectionOut.Captures() {
+			assert.Equal(t, captures[i].Value(), c.(enumCollection.Capture).Value())
+		}
+	}
+}
+
+func Test_DecodeVersion0(t *testing.T) {
+	// Version 0 header: just the string array of members
+	header := rapbinary.StringArrayToBytes([]string{"a", "b", "c"})
+
+	// Version 0 stream: member indexes followed by a uvarint per capture
+	streamData := append(rapbinary.UvarintArrayToBytes([]uint{2, 0}), 1, 0, 1)
+
+	collectionOut, err := enum.NewEncoder(enum.RunLength).Decode("old", header, streamData, []float64{1, 2, 3})
+
+	if assert.NoError(t, err) {
+		enmstr := collectionOut.(enumCollection.Collection)
+		assert.Equal(t, []string{"c", "a"}, enmstr.EnumMembers())
+		assert.Equal(t, 1, enmstr.CaptureAt(0).(enumCollection.Capture).Value())
+		assert.Equal(t, 0, enmstr.CaptureAt(1).(enumCollection.Capture).Value())
+		assert.Equal(t, 1, enmstr.CaptureAt(2).(enumCollection.Capture).Value())
+	}
+}
+
+func Test_DecodeOutOfRangeValue(t *testing.T) {
+	header := rapbinary.StringArrayToBytes([]string{"a", "b"})
+	streamData := append(rapbinary.UvarintArrayToBytes([]uint{0, 1}), 5)
+
+	collectionOut, err := enum.NewEncoder(enum.Raw).Decode("bad", header, streamData, []float64{1})
+
+	assert.EqualError(t, err, "enum value 5 out of range of 2 members in stream bad")
+	assert.Nil(t, collectionOut)
+}
+
+func Test_EncodeOutOfRangeValue(t *testing.T) {
+	collectionIn := enumCollection.NewCollection("bad", []string{"a"}, []enumCollection.Capture{
+		enumCollection.NewCapture(1, 1),
+	})
+
+	_, _, err := enum.NewEncoder(enum.BitPacked).Encode([]format.CaptureCollection{collectionIn})
+
+	assert.EqualError(t, err, "enum value 1 out of range of 1 members in stream bad")
+}
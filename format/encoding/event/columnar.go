@@ -0,0 +1,278 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/metadata"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+// rowCode marks a column whose values are written property by property. It
+// holds properties that claim a specialized code without being the metadata
+// package's own type, so their values can't be pulled out for the column.
+const rowCode byte = 255
+
+type columnID struct {
+	keyIndex int
+	code     byte
+}
+
+// column holds every value of a single key and property type within a
+// stream. A key whose values change type between captures ends up spread
+// across multiple columns.
+type column struct {
+	id      columnID
+	present []bool
+	values  []metadata.Property
+}
+
+func buildColumns(captures []event.Capture, eventKeysSet map[string]int) []*column {
+	columnLookup := make(map[columnID]*column)
+	columns := make([]*column, 0)
+
+	for captureIndex, capture := range captures {
+		for _, key := range capture.Metadata().Keys() {
+			prop := capture.Metadata().Mapping()[key]
+			id := columnID{keyIndex: eventKeysSet[key], code: columnCode(prop)}
+			col, ok := columnLookup[id]
+			if !ok {
+				col = &column{id: id, present: make([]bool, len(captures))}
+				columnLookup[id] = col
+				columns = append(columns, col)
+			}
+			col.present[captureIndex] = true
+			col.values = append(col.values, prop)
+		}
+	}
+
+	return columns
+}
+
+// columnCode picks the column a property is stored in. Properties the
+// specialized columns know how to unpack keep their own code, along with any
+// code no column specializes in.
+func columnCode(prop metadata.Property) byte {
+	switch prop.(type) {
+	case metadata.StringProperty, metadata.Int32Property, metadata.Float32Property,
+		metadata.BoolProperty, metadata.ByteProperty, metadata.TimeProperty:
+		return prop.Code()
+	}
+
+	switch prop.Code() {
+	case metadata.StringCode, metadata.Int32Code, metadata.Float32Code,
+		metadata.TrueCode, metadata.FalseCode, metadata.ByteCode, metadata.TimeCode:
+		return rowCode
+	}
+	return prop.Code()
+}
+
+func writeVarint(out io.Writer, value int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	read := binary.PutVarint(buf, value)
+	out.Write(buf[:read])
+}
+
+func writeUvarint(out io.Writer, value uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	read := binary.PutUvarint(buf, value)
+	out.Write(buf[:read])
+}
+
+func writeColumnValues(out io.Writer, col *column, dictionary map[string]int) {
+	switch col.id.code {
+	case metadata.StringCode:
+		for _, prop := range col.values {
+			str := prop.String()
+			if _, ok := dictionary[str]; !ok {
+				dictionary[str] = len(dictionary)
+			}
+			writeUvarint(out, uint64(dictionary[str]))
+		}
+
+	case metadata.Int32Code:
+		previous := int64(0)
+		for _, prop := range col.values {
+			current := int64(prop.(metadata.Int32Property).Value())
+			writeVarint(out, current-previous)
+			previous = current
+		}
+
+	case metadata.Float32Code:
+		// Delta the raw bits so the value comes back exactly as it went in
+		previous := int64(0)
+		for _, prop := range col.values {
+			current := int64(int32(math.Float32bits(prop.(metadata.Float32Property).Value())))
+			writeVarint(out, current-previous)
+			previous = current
+		}
+
+	case metadata.TimeCode:
+		previous := int64(0)
+		for _, prop := range col.values {
+			current := prop.(metadata.TimeProperty).Value().UnixMicro()
+			writeVarint(out, current-previous)
+			previous = current
+		}
+
+	case metadata.TrueCode, metadata.FalseCode:
+		// Value is implied by the column code
+
+	case metadata.ByteCode:
+		for _, prop := range col.values {
+			out.Write([]byte{prop.(metadata.ByteProperty).Value()})
+		}
+
+	default:
+		for _, prop := range col.values {
+			metadata.WriteProprty(out, prop)
+		}
+	}
+}
+
+func encodeColumnar(out io.Writer, captures []event.Capture, eventNamesSet, eventKeysSet map[string]int, dictionary map[string]int) {
+	// Names column
+	for _, capture := range captures {
+		writeUvarint(out, uint64(eventNamesSet[capture.Name()]))
+	}
+
+	columns := buildColumns(captures, eventKeysSet)
+	writeUvarint(out, uint64(len(columns)))
+
+	for _, col := range columns {
+		writeUvarint(out, uint64(col.id.keyIndex))
+		out.Write([]byte{col.id.code})
+
+		presence := rapbinary.NewBitWriter()
+		for _, present := range col.present {
+			if present {
+				presence.Write(1, 1)
+			} else {
+				presence.Write(0, 1)
+			}
+		}
+		out.Write(presence.Bytes())
+
+		writeColumnValues(out, col, dictionary)
+	}
+}
+
+func readColumnValue(in *bufio.Reader, code byte, previous *int64, dictionary []string) (metadata.Property, error) {
+	switch code {
+	case metadata.StringCode:
+		index, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+		if index >= uint64(len(dictionary)) {
+			return nil, fmt.Errorf("event string dictionary index %d out of range", index)
+		}
+		return metadata.NewStringProperty(dictionary[index]), nil
+
+	case metadata.Int32Code, metadata.Float32Code, metadata.TimeCode:
+		delta, err := binary.ReadVarint(in)
+		if err != nil {
+			return nil, err
+		}
+		*previous += delta
+
+		switch code {
+		case metadata.Int32Code:
+			return metadata.NewIntProperty(int(int32(*previous))), nil
+		case metadata.Float32Code:
+			return metadata.NewFloat32Property(math.Float32frombits(uint32(int32(*previous)))), nil
+		}
+		return metadata.NewTimeProperty(time.UnixMicro(*previous)), nil
+
+	case metadata.TrueCode:
+		return metadata.NewBoolProperty(true), nil
+
+	case metadata.FalseCode:
+		return metadata.NewBoolProperty(false), nil
+
+	case metadata.ByteCode:
+		b, err := in.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		return metadata.NewByteProperty(b), nil
+	}
+
+	return metadata.ReadProperty(in)
+}
+
+func decodeColumnar(in *bufio.Reader, header encoderHeader, times []float64) ([]event.Capture, error) {
+	names := make([]string, len(times))
+	for i := range times {
+		nameIndex, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+		if nameIndex >= uint64(len(header.names)) {
+			return nil, fmt.Errorf("event name index %d out of range of header", nameIndex)
+		}
+		names[i] = header.names[nameIndex]
+	}
+
+	blocks := make([]map[string]metadata.Property, len(times))
+	for i := range blocks {
+		blocks[i] = make(map[string]metadata.Property)
+	}
+
+	numColumns, err := binary.ReadUvarint(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for columnIndex := 0; columnIndex < int(numColumns); columnIndex++ {
+		keyIndex, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, err
+		}
+		if keyIndex >= uint64(len(header.metadataKeys)) {
+			return nil, fmt.Errorf("event metadata key index %d out of range of header", keyIndex)
+		}
+		key := header.metadataKeys[keyIndex]
+
+		code, err := in.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		presenceBytes := make([]byte, (len(times)+7)/8)
+		_, err = io.ReadFull(in, presenceBytes)
+		if err != nil {
+			return nil, err
+		}
+		presence := rapbinary.NewBitReader(bytes.NewReader(presenceBytes))
+
+		previous := int64(0)
+		for captureIndex := range times {
+			present, err := presence.Read(1)
+			if err != nil {
+				return nil, err
+			}
+			if present == 0 {
+				continue
+			}
+
+			prop, err := readColumnValue(in, code, &previous, header.dictionary)
+			if err != nil {
+				return nil, err
+			}
+			blocks[captureIndex][key] = prop
+		}
+	}
+
+	captures := make([]event.Capture, len(times))
+	for i, t := range times {
+		captures[i] = event.NewCapture(t, names[i], metadata.NewBlock(blocks[i]))
+	}
+	return captures, nil
+}
@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/collection/event"
@@ -12,10 +14,29 @@ import (
 	rapbinary "github.com/recolude/rap/internal/io/binary"
 )
 
-type Encoder struct{}
+type StorageTechnique int
 
-func NewEncoder() Encoder {
-	return Encoder{}
+const (
+	// Row writes each capture's metadata block one after another
+	Row StorageTechnique = iota
+
+	// Columnar pivots metadata into typed columns per key, dictionary
+	// encoding strings across the whole file and delta encoding numbers.
+	// Best suited for events that share the same keys on every capture.
+	Columnar
+)
+
+// techniqueMarker is appended to the header to let the decoder know the
+// stream data contains a storage technique byte. Version 0 of this encoder
+// did not write one.
+const techniqueMarker byte = 1
+
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
 }
 
 func (p Encoder) Accepts(stream format.CaptureCollection) bool {
@@ -27,16 +48,40 @@ func (p Encoder) Signature() string {
 }
 
 func (p Encoder) Version() uint {
-	return 0
+	return 1
+}
+
+func encodeRow(out io.Writer, captures []event.Capture, eventNamesSet, eventKeysSet map[string]int) {
+	nameIndex := make([]byte, binary.MaxVarintLen64)
+	for _, eventCapture := range captures {
+		read := binary.PutUvarint(nameIndex, uint64(eventNamesSet[eventCapture.Name()]))
+		out.Write(nameIndex[:read])
+
+		allKeyIndxes := make([]uint, len(eventCapture.Metadata().Mapping()))
+		allValueDataBuffer := bytes.Buffer{}
+		keyCount := 0
+		for _, key := range eventCapture.Metadata().Keys() {
+			val := eventCapture.Metadata().Mapping()[key]
+			allKeyIndxes[keyCount] = uint(eventKeysSet[key])
+			allValueDataBuffer.WriteByte(val.Code())
+			allValueDataBuffer.Write(val.Data())
+			keyCount++
+		}
+
+		out.Write(rapbinary.UvarintArrayToBytes(allKeyIndxes))
+		out.Write(allValueDataBuffer.Bytes())
+	}
 }
 
 func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, error) {
 	eventNamesSet := make(map[string]int)
 	eventKeysSet := make(map[string]int)
+	dictionary := make(map[string]int)
 
-	streamDataBuffers := make([]bytes.Buffer, len(streams))
-	for bufferIndex, stream := range streams {
-		for _, c := range stream.Captures() {
+	allCaptures := make([][]event.Capture, len(streams))
+	for streamIndex, stream := range streams {
+		allCaptures[streamIndex] = make([]event.Capture, len(stream.Captures()))
+		for captureIndex, c := range stream.Captures() {
 			eventCapture, ok := c.(event.Capture)
 			if !ok {
 				return nil, nil, errors.New("capture is not of type event")
@@ -46,25 +91,31 @@ func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, e
 				eventNamesSet[eventCapture.Name()] = len(eventNamesSet)
 			}
 
-			nameIndex := make([]byte, binary.MaxVarintLen64)
-			read := binary.PutUvarint(nameIndex, uint64(eventNamesSet[eventCapture.Name()]))
-			streamDataBuffers[bufferIndex].Write(nameIndex[:read])
-
-			allKeyIndxes := make([]uint, len(eventCapture.Metadata().Mapping()))
-			allValueDataBuffer := bytes.Buffer{}
-			keyCount := 0
-			for key, val := range eventCapture.Metadata().Mapping() {
+			for _, key := range eventCapture.Metadata().Keys() {
 				if _, ok := eventKeysSet[key]; !ok {
 					eventKeysSet[key] = len(eventKeysSet)
 				}
-				allKeyIndxes[keyCount] = uint(eventKeysSet[key])
-				allValueDataBuffer.WriteByte(val.Code())
-				allValueDataBuffer.Write(val.Data())
-				keyCount++
 			}
 
-			streamDataBuffers[bufferIndex].Write(rapbinary.UvarintArrayToBytes(allKeyIndxes))
-			streamDataBuffers[bufferIndex].Write(allValueDataBuffer.Bytes())
+			allCaptures[streamIndex][captureIndex] = eventCapture
+		}
+	}
+
+	streamDataBuffers := make([]bytes.Buffer, len(streams))
+	for bufferIndex, captures := range allCaptures {
+		streamDataBuffers[bufferIndex].WriteByte(byte(p.technique))
+
+		switch p.technique {
+		case Row:
+			encodeRow(&streamDataBuffers[bufferIndex], captures, eventNamesSet, eventKeysSet)
+			break
+
+		case Columnar:
+			encodeColumnar(&streamDataBuffers[bufferIndex], captures, eventNamesSet, eventKeysSet, dictionary)
+			break
+
+		default:
+			return nil, nil, fmt.Errorf("unknown event encoding technique: %d", int(p.technique))
 		}
 	}
 
@@ -87,24 +138,60 @@ func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, e
 	}
 	header.Write(rapbinary.StringArrayToBytes(allKeys))
 
+	header.WriteByte(techniqueMarker)
+
+	allDictionaryEntries := make([]string, len(dictionary))
+	for key, index := range dictionary {
+		allDictionaryEntries[index] = key
+	}
+	header.Write(rapbinary.StringArrayToBytes(allDictionaryEntries))
+
 	return header.Bytes(), streamData, nil
 }
 
-func readHeader(header []byte) (names []string, metadataKeys []string, err error) {
-	headerReader := bytes.NewBuffer(header)
-	names, _, err = rapbinary.ReadStringArray(headerReader)
-	metadataKeys, _, err = rapbinary.ReadStringArray(headerReader)
-	return
+type encoderHeader struct {
+	names        []string
+	metadataKeys []string
+	hasTechnique bool
+	dictionary   []string
 }
 
-func (p Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
-	buf := bufio.NewReader(bytes.NewReader(streamData))
+func readHeader(header []byte) (encoderHeader, error) {
+	headerReader := bytes.NewReader(header)
+	names, _, err := rapbinary.ReadStringArray(headerReader)
+	if err != nil {
+		return encoderHeader{}, err
+	}
 
-	eventNames, metadataKeys, err := readHeader(header)
+	metadataKeys, _, err := rapbinary.ReadStringArray(headerReader)
 	if err != nil {
-		return nil, err
+		return encoderHeader{}, err
+	}
+
+	// Version 0 headers end after the metadata keys
+	if headerReader.Len() == 0 {
+		return encoderHeader{names: names, metadataKeys: metadataKeys}, nil
 	}
 
+	marker, err := headerReader.ReadByte()
+	if err != nil {
+		return encoderHeader{}, err
+	}
+
+	dictionary, _, err := rapbinary.ReadStringArray(headerReader)
+	if err != nil {
+		return encoderHeader{}, err
+	}
+
+	return encoderHeader{
+		names:        names,
+		metadataKeys: metadataKeys,
+		hasTechnique: marker == techniqueMarker,
+		dictionary:   dictionary,
+	}, nil
+}
+
+func decodeRow(buf *bufio.Reader, header encoderHeader, times []float64) ([]event.Capture, error) {
 	captures := make([]event.Capture, len(times))
 	for i := 0; i < len(times); i++ {
 		eventNameIndex, err := binary.ReadUvarint(buf)
@@ -112,6 +199,10 @@ func (p Encoder) Decode(name string, header []byte, streamData []byte, times []f
 			return nil, err
 		}
 
+		if eventNameIndex >= uint64(len(header.names)) {
+			return nil, fmt.Errorf("event name index %d out of range of header", eventNameIndex)
+		}
+
 		metadataIndeces, _, err := rapbinary.ReadUvarIntArray(buf)
 		if err != nil {
 			return nil, err
@@ -123,9 +214,50 @@ func (p Encoder) Decode(name string, header []byte, streamData []byte, times []f
 			if err != nil {
 				return nil, err
 			}
-			block[metadataKeys[metadataIndeces[metadataIndex]]] = prop
+			if metadataIndeces[metadataIndex] >= uint(len(header.metadataKeys)) {
+				return nil, fmt.Errorf("event metadata key index %d out of range of header", metadataIndeces[metadataIndex])
+			}
+			block[header.metadataKeys[metadataIndeces[metadataIndex]]] = prop
 		}
-		captures[i] = event.NewCapture(times[i], eventNames[int(eventNameIndex)], metadata.NewBlock(block))
+		captures[i] = event.NewCapture(times[i], header.names[int(eventNameIndex)], metadata.NewBlock(block))
+	}
+	return captures, nil
+}
+
+func (p Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	buf := bufio.NewReader(bytes.NewReader(streamData))
+
+	parsedHeader, err := readHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// Version 0 of the encoder only ever wrote rows
+	technique := Row
+	if parsedHeader.hasTechnique {
+		techniqueByte, err := buf.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		technique = StorageTechnique(techniqueByte)
+	}
+
+	var captures []event.Capture
+	switch technique {
+	case Row:
+		captures, err = decodeRow(buf, parsedHeader, times)
+		break
+
+	case Columnar:
+		captures, err = decodeColumnar(buf, parsedHeader, times)
+		break
+
+	default:
+		return nil, fmt.Errorf("unknown event encoding technique: %d", int(technique))
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	return event.NewCollection(name, captures), nil
@@ -1,12 +1,15 @@
 package event_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/recolude/rap/format"
 	eventStream "github.com/recolude/rap/format/collection/event"
 	"github.com/recolude/rap/format/encoding/event"
 	"github.com/recolude/rap/format/metadata"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,46 +57,193 @@ func Test_SingleEvent(t *testing.T) {
 			},
 			times: []float64{1.2, 1.6},
 		},
+		"mixed-events": {
+			streamName: "combat",
+			captures: []eventStream.Capture{
+				eventStream.NewCapture(
+					1,
+					"Damage",
+					metadata.NewBlock(map[string]metadata.Property{
+						"damage":   metadata.NewFloat32Property(12.5),
+						"weapon":   metadata.NewStringProperty("sword"),
+						"critical": metadata.NewBoolProperty(true),
+						"when":     metadata.NewTimeProperty(time.UnixMicro(1625000000000000)),
+					}),
+				),
+				eventStream.NewCapture(
+					2,
+					"Heal",
+					metadata.NewBlock(map[string]metadata.Property{}),
+				),
+				eventStream.NewCapture(
+					3,
+					"Damage",
+					metadata.NewBlock(map[string]metadata.Property{
+						"damage":   metadata.NewIntProperty(-3),
+						"weapon":   metadata.NewStringProperty("sword"),
+						"critical": metadata.NewBoolProperty(false),
+						"when":     metadata.NewTimeProperty(time.UnixMicro(1625000000001000)),
+						"flags":    metadata.NewByteProperty(0x0f),
+						"target":   metadata.NewVector3Property(1, 2, 3),
+						"extra": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+							"nested": metadata.NewStringProperty("value"),
+						})),
+					}),
+				),
+			},
+			times: []float64{1, 2, 3},
+		},
 	}
 
-	encoder := event.NewEncoder()
-	assert.Equal(t, "recolude.event", encoder.Signature())
-	assert.Equal(t, uint(0), encoder.Version())
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			streamIn := eventStream.NewCollection(tc.streamName, tc.captures)
-			assert.True(t, encoder.Accepts(streamIn))
-
-			// ACT ====================================================================
-			header, streamsData, encodeErr := encoder.Encode([]format.CaptureCollection{streamIn})
-			streamOut, decodeErr := encoder.Decode(tc.streamName, header, streamsData[0], tc.times)
-
-			// ASSERT =================================================================
-			assert.NoError(t, encodeErr)
-			assert.NoError(t, decodeErr)
-			assert.NotNil(t, streamOut)
-			assert.Len(t, streamsData, 1)
-			assert.Equal(t, tc.streamName, streamOut.Name())
-			if assert.NotNil(t, streamOut) {
-				assert.Equal(t, streamIn.Name(), streamOut.Name())
-				if assert.Len(t, streamOut.Captures(), len(streamIn.Captures())) {
-					for i, c := range streamOut.Captures() {
-						eventCapture, ok := c.(eventStream.Capture)
-						if assert.True(t, ok) == false {
-							break
-						}
+	techniques := map[string]event.StorageTechnique{
+		"Row":      event.Row,
+		"Columnar": event.Columnar,
+	}
+
+	for techniqueName, technique := range techniques {
+		encoder := event.NewEncoder(technique)
+		assert.Equal(t, "recolude.event", encoder.Signature())
+		assert.Equal(t, uint(1), encoder.Version())
+
+		for name, tc := range tests {
+			t.Run(fmt.Sprintf("%s/%s", techniqueName, name), func(t *testing.T) {
+				streamIn := eventStream.NewCollection(tc.streamName, tc.captures)
+				assert.True(t, encoder.Accepts(streamIn))
 
-						assert.Equal(t, tc.captures[i].Time(), eventCapture.Time(), "times are not equal: %.2f != %.2f", tc.captures[i].Time(), eventCapture.Time())
-						assert.Equal(t, tc.captures[i].Name(), eventCapture.Name())
+				// ACT ====================================================================
+				header, streamsData, encodeErr := encoder.Encode([]format.CaptureCollection{streamIn})
+				streamOut, decodeErr := encoder.Decode(tc.streamName, header, streamsData[0], tc.times)
 
-						assert.Len(t, eventCapture.Metadata().Mapping(), len(tc.captures[i].Metadata().Mapping()))
-						for key, val := range tc.captures[i].Metadata().Mapping() {
-							assert.Equal(t, val, eventCapture.Metadata().Mapping()[key])
+				// ASSERT =================================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				assert.NotNil(t, streamOut)
+				assert.Len(t, streamsData, 1)
+				assert.Equal(t, tc.streamName, streamOut.Name())
+				if assert.NotNil(t, streamOut) {
+					assert.Equal(t, streamIn.Name(), streamOut.Name())
+					if assert.Len(t, streamOut.Captures(), len(streamIn.Captures())) {
+						for i, c := range streamOut.Captures() {
+							eventCapture, ok := c.(eventStream.Capture)
+							if assert.True(t, ok) == false {
+								break
+							}
+
+							assert.Equal(t, tc.captures[i].Time(), eventCapture.Time(), "times are not equal: %.2f != %.2f", tc.captures[i].Time(), eventCapture.Time())
+							assert.Equal(t, tc.captures[i].Name(), eventCapture.Name())
+
+							assert.Len(t, eventCapture.Metadata().Mapping(), len(tc.captures[i].Metadata().Mapping()))
+							for key, val := range tc.captures[i].Metadata().Mapping() {
+								assert.Equal(t, val, eventCapture.Metadata().Mapping()[key])
+							}
 						}
 					}
 				}
+			})
+		}
+	}
+}
+
+func Test_ColumnarSharesDictionaryAcrossStreams(t *testing.T) {
+	captures := make([]eventStream.Capture, 500)
+	times := make([]float64, len(captures))
+	for i := range captures {
+		captures[i] = eventStream.NewCapture(
+			float64(i),
+			"Damage",
+			metadata.NewBlock(map[string]metadata.Property{
+				"damage": metadata.NewIntProperty(100 + i%7),
+				"weapon": metadata.NewStringProperty([]string{"sword", "bow", "staff"}[i%3]),
+				"target": metadata.NewStringProperty("enemy"),
+			}),
+		)
+		times[i] = float64(i)
+	}
+	streamsIn := []format.CaptureCollection{
+		eventStream.NewCollection("first", captures),
+		eventStream.NewCollection("second", captures),
+	}
+
+	_, rowData, rowErr := event.NewEncoder(event.Row).Encode(streamsIn)
+	header, columnarData, columnarErr := event.NewEncoder(event.Columnar).Encode(streamsIn)
+
+	assert.NoError(t, rowErr)
+	assert.NoError(t, columnarErr)
+	assert.Less(t, len(columnarData[0])*3, len(rowData[0]))
+
+	for i, data := range columnarData {
+		streamOut, err := event.NewEncoder(event.Row).Decode(streamsIn[i].Name(), header, data, times)
+		if assert.NoError(t, err) {
+			for captureIndex, c := range streamOut.Captures() {
+				assert.Equal(t, captures[captureIndex].Metadata().Mapping(), c.(eventStream.Capture).Metadata().Mapping())
 			}
-		})
+		}
+	}
+}
+
+func Test_DecodeVersion0(t *testing.T) {
+	// Version 0 header: event names followed by metadata keys
+	header := append(
+		rapbinary.StringArrayToBytes([]string{"Damage"}),
+		rapbinary.StringArrayToBytes([]string{"weapon"})...,
+	)
+
+	// Version 0 stream: name index, key indexes, then values
+	streamData := []byte{0}
+	streamData = append(streamData, rapbinary.UvarintArrayToBytes([]uint{0})...)
+	streamData = append(streamData, 0)
+	streamData = append(streamData, rapbinary.StringToBytes("sword")...)
+
+	streamOut, err := event.NewEncoder(event.Columnar).Decode("old", header, streamData, []float64{1})
+
+	if assert.NoError(t, err) && assert.Equal(t, 1, streamOut.Length()) {
+		capture := streamOut.CaptureAt(0).(eventStream.Capture)
+		assert.Equal(t, "Damage", capture.Name())
+		assert.Equal(t, metadata.NewStringProperty("sword"), capture.Metadata().Mapping()["weapon"])
+	}
+}
+
+// foreignIntProperty carries the int32 code without being the metadata
+// package's own Int32Property.
+type foreignIntProperty struct {
+	value int32
+}
+
+func (fip foreignIntProperty) Code() byte {
+	return metadata.Int32Code
+}
+
+func (fip foreignIntProperty) String() string {
+	return fmt.Sprintf("%d", fip.value)
+}
+
+func (fip foreignIntProperty) Data() []byte {
+	return metadata.NewIntProperty(int(fip.value)).Data()
+}
+
+func Test_ColumnarForeignProperty(t *testing.T) {
+	// ARRANGE ================================================================
+	streamsIn := []format.CaptureCollection{
+		eventStream.NewCollection("events", []eventStream.Capture{
+			eventStream.NewCapture(1, "Damage", metadata.NewBlock(map[string]metadata.Property{
+				"damage": foreignIntProperty{value: 7},
+			})),
+			eventStream.NewCapture(2, "Damage", metadata.NewBlock(map[string]metadata.Property{
+				"damage": metadata.NewIntProperty(9),
+			})),
+		}),
+	}
+
+	// ACT ====================================================================
+	header, data, encodeErr := event.NewEncoder(event.Columnar).Encode(streamsIn)
+
+	// ASSERT =================================================================
+	if !assert.NoError(t, encodeErr) {
+		return
+	}
+	streamOut, err := event.NewEncoder(event.Columnar).Decode("events", header, data[0], []float64{1, 2})
+	if assert.NoError(t, err) && assert.Equal(t, 2, streamOut.Length()) {
+		assert.Equal(t, metadata.NewIntProperty(7), streamOut.CaptureAt(0).(eventStream.Capture).Metadata().Mapping()["damage"])
+		assert.Equal(t, metadata.NewIntProperty(9), streamOut.CaptureAt(1).(eventStream.Capture).Metadata().Mapping()["damage"])
 	}
 }
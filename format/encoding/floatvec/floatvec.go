@@ -0,0 +1,297 @@
+package floatvec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/floatvec"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw64 encodes every channel with maximum precision
+	Raw64 StorageTechnique = iota
+
+	// Raw32 truncates every channel to 32bit
+	Raw32
+
+	// BST16 encodes each value within 16 bits, using the min and max of its
+	// channel
+	BST16
+
+	// DeltaBST16 encodes the change between consecutive values of a channel
+	// within 16 bits, using the min and max change of that channel. Best
+	// suited for channels that move smoothly across a large range.
+	DeltaBST16
+)
+
+// Encoder writes floatvec collections. Channel names are declared once per
+// collection, and values are grouped by channel so each channel can be
+// quantized by its own range while all channels share the same time track.
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.floatvec"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.floatvec"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
+
+func channelBounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return min, max
+}
+
+func encodeBST16(out io.Writer, values []float64) {
+	min, max := channelBounds(values)
+	binary.Write(out, binary.LittleEndian, float32(min))
+	binary.Write(out, binary.LittleEndian, float32(max))
+
+	buffer := make([]byte, 2)
+	for _, v := range values {
+		rapbinary.UnsignedFloatBSTToBytes(v, float64(float32(min)), float64(float32(max)-float32(min)), buffer)
+		out.Write(buffer)
+	}
+}
+
+func decodeBST16(in io.Reader, count int) []float64 {
+	var min, max float32
+	binary.Read(in, binary.LittleEndian, &min)
+	binary.Read(in, binary.LittleEndian, &max)
+
+	values := make([]float64, count)
+	buffer := make([]byte, 2)
+	for i := range values {
+		in.Read(buffer)
+		values[i] = rapbinary.BytesToUnisngedFloatBST(float64(min), float64(max-min), buffer)
+	}
+	return values
+}
+
+func encodeDeltaBST16(out io.Writer, values []float64) {
+	if len(values) == 0 {
+		return
+	}
+
+	deltas := make([]float64, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		deltas[i-1] = values[i] - values[i-1]
+	}
+	min, max := channelBounds(deltas)
+	min = float64(float32(min))
+	max = float64(float32(max))
+
+	binary.Write(out, binary.LittleEndian, float32(values[0]))
+	binary.Write(out, binary.LittleEndian, float32(min))
+	binary.Write(out, binary.LittleEndian, float32(max))
+
+	// Track the value the decoder will see to keep error from accumulating
+	quantized := float64(float32(values[0]))
+	buffer := make([]byte, 2)
+	for i := 1; i < len(values); i++ {
+		rapbinary.UnsignedFloatBSTToBytes(values[i]-quantized, min, max-min, buffer)
+		out.Write(buffer)
+		quantized += rapbinary.BytesToUnisngedFloatBST(min, max-min, buffer)
+	}
+}
+
+func decodeDeltaBST16(in io.Reader, count int) []float64 {
+	values := make([]float64, count)
+	if count == 0 {
+		return values
+	}
+
+	var first, min, max float32
+	binary.Read(in, binary.LittleEndian, &first)
+	binary.Read(in, binary.LittleEndian, &min)
+	binary.Read(in, binary.LittleEndian, &max)
+
+	values[0] = float64(first)
+	buffer := make([]byte, 2)
+	for i := 1; i < count; i++ {
+		in.Read(buffer)
+		values[i] = values[i-1] + rapbinary.BytesToUnisngedFloatBST(float64(min), float64(max-min), buffer)
+	}
+	return values
+}
+
+func (e Encoder) encodeChannel(out io.Writer, values []float64) error {
+	switch e.technique {
+	case Raw64:
+		for _, v := range values {
+			binary.Write(out, binary.LittleEndian, v)
+		}
+
+	case Raw32:
+		for _, v := range values {
+			binary.Write(out, binary.LittleEndian, float32(v))
+		}
+
+	case BST16:
+		encodeBST16(out, values)
+
+	case DeltaBST16:
+		encodeDeltaBST16(out, values)
+
+	default:
+		return fmt.Errorf("unknown floatvec encoding technique: %d", int(e.technique))
+	}
+	return nil
+}
+
+func (e Encoder) encode(c format.CaptureCollection) ([]byte, error) {
+	collection, ok := c.(floatvec.Collection)
+	if !ok {
+		return nil, errors.New("collection is not of type floatvec")
+	}
+
+	channels := make([][]float64, len(collection.Channels()))
+	for i := range channels {
+		channels[i] = make([]float64, collection.Length())
+	}
+
+	for captureIndex := 0; captureIndex < collection.Length(); captureIndex++ {
+		capture, ok := collection.CaptureAt(captureIndex).(floatvec.Capture)
+		if !ok {
+			return nil, errors.New("capture is not of type floatvec")
+		}
+
+		if len(capture.Values()) != len(channels) {
+			return nil, fmt.Errorf(
+				"floatvec capture %d in collection %s has %d values but collection has %d channels",
+				captureIndex,
+				collection.Name(),
+				len(capture.Values()),
+				len(channels),
+			)
+		}
+
+		for channelIndex, v := range capture.Values() {
+			channels[channelIndex][captureIndex] = v
+		}
+	}
+
+	if e.technique < Raw64 || e.technique > DeltaBST16 {
+		return nil, fmt.Errorf("unknown floatvec encoding technique: %d", int(e.technique))
+	}
+
+	out := bytes.Buffer{}
+	out.WriteByte(byte(e.technique))
+	out.Write(rapbinary.StringArrayToBytes(collection.Channels()))
+	for _, values := range channels {
+		if err := e.encodeChannel(&out, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	allStreamData := make([][]byte, len(collections))
+
+	for i, collection := range collections {
+		s, err := e.encode(collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		allStreamData[i] = s
+	}
+
+	return nil, allStreamData, nil
+}
+
+func decodeChannel(in io.Reader, technique StorageTechnique, count int) ([]float64, error) {
+	switch technique {
+	case Raw64:
+		values := make([]float64, count)
+		for i := range values {
+			binary.Read(in, binary.LittleEndian, &values[i])
+		}
+		return values, nil
+
+	case Raw32:
+		values := make([]float64, count)
+		var v float32
+		for i := range values {
+			binary.Read(in, binary.LittleEndian, &v)
+			values[i] = float64(v)
+		}
+		return values, nil
+
+	case BST16:
+		return decodeBST16(in, count), nil
+
+	case DeltaBST16:
+		return decodeDeltaBST16(in, count), nil
+	}
+
+	return nil, fmt.Errorf("unknown floatvec encoding technique: %d", int(technique))
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	reader := bytes.NewReader(streamData)
+	techniqueByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if StorageTechnique(techniqueByte) > DeltaBST16 {
+		return nil, fmt.Errorf("unknown floatvec encoding technique: %d", int(techniqueByte))
+	}
+
+	channels, _, err := rapbinary.ReadStringArray(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	errReader := rapbinary.NewErrReader(reader)
+	channelValues := make([][]float64, len(channels))
+	for i := range channelValues {
+		channelValues[i], err = decodeChannel(errReader, StorageTechnique(techniqueByte), len(times))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if errReader.Error() != nil {
+		return nil, errReader.Error()
+	}
+
+	captures := make([]floatvec.Capture, len(times))
+	for captureIndex, time := range times {
+		values := make([]float64, len(channels))
+		for channelIndex := range channels {
+			values[channelIndex] = channelValues[channelIndex][captureIndex]
+		}
+		captures[captureIndex] = floatvec.NewCapture(time, values)
+	}
+
+	return floatvec.NewCollection(name, channels, captures), nil
+}
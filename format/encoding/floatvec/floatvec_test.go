@@ -0,0 +1,112 @@
+package floatvec_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	floatvecCollection "github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/encoding/floatvec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FloatVec(t *testing.T) {
+	channels := []string{"jawOpen", "blinkLeft", "spectrum", "constant"}
+	frames := make([]floatvecCollection.Capture, 1000)
+	frameTimes := make([]float64, len(frames))
+	spectrum := 0.0
+	for i := range frames {
+		frameTimes[i] = float64(i) / 60.0
+		spectrum += rand.Float64()*200 - 100
+		frames[i] = floatvecCollection.NewCapture(frameTimes[i], []float64{
+			rand.Float64(),
+			math.Round(rand.Float64()),
+			spectrum,
+			42,
+		})
+	}
+
+	tests := map[string]struct {
+		captures []floatvecCollection.Capture
+		times    []float64
+	}{
+		"nil frames":  {captures: nil},
+		"0-frames":    {captures: []floatvecCollection.Capture{}},
+		"1-frame":     {captures: frames[:1], times: frameTimes[:1]},
+		"2-frames":    {captures: frames[:2], times: frameTimes[:2]},
+		"1000-frames": {captures: frames, times: frameTimes},
+	}
+
+	techniques := []struct {
+		displayName string
+		technique   floatvec.StorageTechnique
+		tolerance   []float64
+	}{
+		{displayName: "Raw64", technique: floatvec.Raw64, tolerance: []float64{0, 0, 0, 0}},
+		{displayName: "Raw32", technique: floatvec.Raw32, tolerance: []float64{0.0000001, 0, 0.01, 0}},
+		{displayName: "BST16", technique: floatvec.BST16, tolerance: []float64{0.0001, 0.0001, 0.5, 0.0001}},
+		{displayName: "DeltaBST16", technique: floatvec.DeltaBST16, tolerance: []float64{0.0001, 0.0001, 0.01, 0.0001}},
+	}
+
+	for name, tc := range tests {
+		for _, technique := range techniques {
+			t.Run(fmt.Sprintf("%s/%s", name, technique.displayName), func(t *testing.T) {
+				collectionIn := floatvecCollection.NewCollection("Face", channels, tc.captures)
+				encoder := floatvec.NewEncoder(technique.technique)
+				assert.Equal(t, "recolude.floatvec", encoder.Signature())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ========================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode("Face", header, collectionData[0], tc.times)
+
+				// ASSERT =====================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				if assert.NotNil(t, collectionOut) == false || assert.Equal(t, len(tc.captures), collectionOut.Length()) == false {
+					return
+				}
+
+				out := collectionOut.(floatvecCollection.Collection)
+				assert.Equal(t, channels, out.Channels())
+				for i, c := range out.Captures() {
+					capture := c.(floatvecCollection.Capture)
+					assert.Equal(t, tc.times[i], capture.Time())
+					for channel, v := range capture.Values() {
+						if !assert.InDelta(t, tc.captures[i].Values()[channel], v, technique.tolerance[channel], "channel %s capture %d", channels[channel], i) {
+							return
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_FloatVec_RejectsMismatchedValues(t *testing.T) {
+	collection := floatvecCollection.NewCollection("Axes", []string{"x", "y"}, []floatvecCollection.Capture{
+		floatvecCollection.NewCapture(1, []float64{1}),
+	})
+
+	_, _, err := floatvec.NewEncoder(floatvec.BST16).Encode([]format.CaptureCollection{collection})
+
+	assert.EqualError(t, err, "floatvec capture 0 in collection Axes has 1 values but collection has 2 channels")
+}
+
+func Test_FloatVec_RejectsOtherCollections(t *testing.T) {
+	encoder := floatvec.NewEncoder(floatvec.BST16)
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "collection is not of type floatvec")
+}
+
+func Test_FloatVec_UnknownTechnique(t *testing.T) {
+	_, err := floatvec.NewEncoder(floatvec.BST16).Decode("Axes", nil, []byte{9, 0}, nil)
+	assert.EqualError(t, err, "unknown floatvec encoding technique: 9")
+}
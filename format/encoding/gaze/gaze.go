@@ -0,0 +1,215 @@
+package gaze
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/gaze"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw32 stores every value at 32bit precision, costing 320 bits per
+	// capture
+	Raw32 StorageTechnique = iota
+
+	// Compact stores the origin at 32bit precision, the direction in 32 bits
+	// using octahedral encoding, each eye's openness and the confidence in 8
+	// bits, and the pupil diameter in 16 bits at micrometer resolution,
+	// costing 168 bits per capture
+	Compact
+)
+
+// pupilResolution is how many millimeters each step of a compact pupil
+// diameter represents
+const pupilResolution = 0.001
+
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.gaze"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.gaze"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
+
+func writeFloat32s(out io.Writer, values ...float64) {
+	for _, v := range values {
+		binary.Write(out, binary.LittleEndian, float32(v))
+	}
+}
+
+func readFloat32(in io.Reader) float64 {
+	var v float32
+	binary.Read(in, binary.LittleEndian, &v)
+	return float64(v)
+}
+
+// quantizeUnit maps a value from 0 to 1 onto the steps provided, clamping
+// anything out of range.
+func quantizeUnit(value float64, steps float64) uint64 {
+	return uint64(math.Round(math.Max(0, math.Min(1, value)) * steps))
+}
+
+func encodeRaw32(out io.Writer, captures []gaze.Capture) {
+	for _, c := range captures {
+		writeFloat32s(
+			out,
+			c.Origin().X(), c.Origin().Y(), c.Origin().Z(),
+			c.Direction().X(), c.Direction().Y(), c.Direction().Z(),
+			c.LeftOpenness(), c.RightOpenness(),
+			c.PupilDiameter(),
+			c.Confidence(),
+		)
+	}
+}
+
+func decodeRaw32(in io.Reader, times []float64) ([]gaze.Capture, error) {
+	errReader := rapbinary.NewErrReader(in)
+	captures := make([]gaze.Capture, len(times))
+	for i := range times {
+		origin := vector.NewVector3(readFloat32(errReader), readFloat32(errReader), readFloat32(errReader))
+		direction := vector.NewVector3(readFloat32(errReader), readFloat32(errReader), readFloat32(errReader))
+		left := readFloat32(errReader)
+		right := readFloat32(errReader)
+		pupil := readFloat32(errReader)
+		confidence := readFloat32(errReader)
+		captures[i] = gaze.NewCapture(times[i], origin, direction, left, right, pupil, confidence)
+	}
+	return captures, errReader.Error()
+}
+
+func encodeCompact(out io.Writer, captures []gaze.Capture) {
+	buf := make([]byte, 2)
+	for _, c := range captures {
+		writeFloat32s(out, c.Origin().X(), c.Origin().Y(), c.Origin().Z())
+
+		x, y := OctahedralEncode(c.Direction())
+		binary.LittleEndian.PutUint16(buf, uint16(quantizeUnit((x+1)/2, math.MaxUint16)))
+		out.Write(buf)
+		binary.LittleEndian.PutUint16(buf, uint16(quantizeUnit((y+1)/2, math.MaxUint16)))
+		out.Write(buf)
+
+		out.Write([]byte{
+			byte(quantizeUnit(c.LeftOpenness(), math.MaxUint8)),
+			byte(quantizeUnit(c.RightOpenness(), math.MaxUint8)),
+			byte(quantizeUnit(c.Confidence(), math.MaxUint8)),
+		})
+
+		pupil := math.Round(c.PupilDiameter() / pupilResolution)
+		binary.LittleEndian.PutUint16(buf, uint16(math.Max(0, math.Min(math.MaxUint16, pupil))))
+		out.Write(buf)
+	}
+}
+
+func decodeCompact(in io.Reader, times []float64) ([]gaze.Capture, error) {
+	errReader := rapbinary.NewErrReader(in)
+	captures := make([]gaze.Capture, len(times))
+	buf := make([]byte, 9)
+	for i := range times {
+		origin := vector.NewVector3(readFloat32(errReader), readFloat32(errReader), readFloat32(errReader))
+		if _, err := errReader.Read(buf); err != nil {
+			return nil, err
+		}
+
+		x := float64(binary.LittleEndian.Uint16(buf[0:]))/math.MaxUint16*2 - 1
+		y := float64(binary.LittleEndian.Uint16(buf[2:]))/math.MaxUint16*2 - 1
+
+		captures[i] = gaze.NewCapture(
+			times[i],
+			origin,
+			OctahedralDecode(x, y),
+			float64(buf[4])/math.MaxUint8,
+			float64(buf[5])/math.MaxUint8,
+			float64(binary.LittleEndian.Uint16(buf[7:]))*pupilResolution,
+			float64(buf[6])/math.MaxUint8,
+		)
+	}
+	return captures, errReader.Error()
+}
+
+func (e Encoder) encode(collection format.CaptureCollection) ([]byte, error) {
+	captures := make([]gaze.Capture, collection.Length())
+	for i := range captures {
+		capture, ok := collection.CaptureAt(i).(gaze.Capture)
+		if !ok {
+			return nil, errors.New("capture is not of type gaze")
+		}
+		captures[i] = capture
+	}
+
+	out := bytes.Buffer{}
+	out.WriteByte(byte(e.technique))
+
+	switch e.technique {
+	case Raw32:
+		encodeRaw32(&out, captures)
+
+	case Compact:
+		encodeCompact(&out, captures)
+
+	default:
+		return nil, fmt.Errorf("unknown gaze encoding technique: %d", int(e.technique))
+	}
+
+	return out.Bytes(), nil
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	allStreamData := make([][]byte, len(collections))
+
+	for i, collection := range collections {
+		s, err := e.encode(collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		allStreamData[i] = s
+	}
+
+	return nil, allStreamData, nil
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	reader := bytes.NewReader(streamData)
+	techniqueByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var captures []gaze.Capture
+	switch StorageTechnique(techniqueByte) {
+	case Raw32:
+		captures, err = decodeRaw32(reader, times)
+
+	case Compact:
+		captures, err = decodeCompact(reader, times)
+
+	default:
+		return nil, fmt.Errorf("unknown gaze encoding technique: %d", int(techniqueByte))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return gaze.NewCollection(name, captures), nil
+}
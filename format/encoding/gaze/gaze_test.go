@@ -0,0 +1,149 @@
+package gaze_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	gazeCollection "github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/encoding/gaze"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomDirection() vector.Vector3 {
+	for {
+		v := vector.NewVector3(rand.Float64()*2-1, rand.Float64()*2-1, rand.Float64()*2-1)
+		if v.Length() > 0.01 {
+			return v.Normalized()
+		}
+	}
+}
+
+func angleBetween(a, b vector.Vector3) float64 {
+	return math.Acos(math.Max(-1, math.Min(1, a.Dot(b)))) * 180.0 / math.Pi
+}
+
+func Test_Octahedral(t *testing.T) {
+	directions := []vector.Vector3{
+		vector.NewVector3(0, 0, 1),
+		vector.NewVector3(0, 0, -1),
+		vector.NewVector3(1, 0, 0),
+		vector.NewVector3(0, -1, 0),
+		vector.NewVector3(-1, -1, -1).Normalized(),
+	}
+	for i := 0; i < 100; i++ {
+		directions = append(directions, randomDirection())
+	}
+
+	for _, direction := range directions {
+		x, y := gaze.OctahedralEncode(direction)
+		assert.LessOrEqual(t, math.Abs(x), 1.0)
+		assert.LessOrEqual(t, math.Abs(y), 1.0)
+		assert.Less(t, angleBetween(direction, gaze.OctahedralDecode(x, y)), 0.0001)
+	}
+}
+
+func Test_Gaze(t *testing.T) {
+	samples := make([]gazeCollection.Capture, 500)
+	sampleTimes := make([]float64, len(samples))
+	for i := range samples {
+		sampleTimes[i] = float64(i) / 120.0
+		samples[i] = gazeCollection.NewCapture(
+			sampleTimes[i],
+			vector.NewVector3(rand.Float64(), 1.6+rand.Float64()*0.1, rand.Float64()),
+			randomDirection(),
+			rand.Float64(),
+			rand.Float64(),
+			2+rand.Float64()*6,
+			rand.Float64(),
+		)
+	}
+
+	tests := map[string]struct {
+		captures []gazeCollection.Capture
+		times    []float64
+	}{
+		"nil samples": {captures: nil},
+		"0-samples":   {captures: []gazeCollection.Capture{}},
+		"1-sample":    {captures: samples[:1], times: sampleTimes[:1]},
+		"500-samples": {captures: samples, times: sampleTimes},
+	}
+
+	techniques := []struct {
+		displayName         string
+		technique           gaze.StorageTechnique
+		angleTolerance      float64
+		opennessTolerance   float64
+		pupilTolerance      float64
+		originTolerance     float64
+		confidenceTolerance float64
+	}{
+		{displayName: "Raw32", technique: gaze.Raw32, angleTolerance: 0.001, opennessTolerance: 0.0000001, pupilTolerance: 0.000001, originTolerance: 0.000001, confidenceTolerance: 0.0000001},
+		{displayName: "Compact", technique: gaze.Compact, angleTolerance: 0.01, opennessTolerance: 0.002, pupilTolerance: 0.0005, originTolerance: 0.000001, confidenceTolerance: 0.002},
+	}
+
+	for name, tc := range tests {
+		for _, technique := range techniques {
+			t.Run(fmt.Sprintf("%s/%s", name, technique.displayName), func(t *testing.T) {
+				collectionIn := gazeCollection.NewCollection("Eyes", tc.captures)
+				encoder := gaze.NewEncoder(technique.technique)
+				assert.Equal(t, "recolude.gaze", encoder.Signature())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ========================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode("Eyes", header, collectionData[0], tc.times)
+
+				// ASSERT =====================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				if assert.NotNil(t, collectionOut) == false || assert.Equal(t, len(tc.captures), collectionOut.Length()) == false {
+					return
+				}
+
+				for i, c := range collectionOut.Captures() {
+					in := tc.captures[i]
+					out := c.(gazeCollection.Capture)
+					if !assert.Less(t, angleBetween(in.Direction(), out.Direction()), technique.angleTolerance) ||
+						!assert.InDelta(t, in.Origin().Y(), out.Origin().Y(), technique.originTolerance) ||
+						!assert.InDelta(t, in.LeftOpenness(), out.LeftOpenness(), technique.opennessTolerance) ||
+						!assert.InDelta(t, in.RightOpenness(), out.RightOpenness(), technique.opennessTolerance) ||
+						!assert.InDelta(t, in.PupilDiameter(), out.PupilDiameter(), technique.pupilTolerance) ||
+						!assert.InDelta(t, in.Confidence(), out.Confidence(), technique.confidenceTolerance) {
+						return
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_Gaze_CompactSize(t *testing.T) {
+	captures := []gazeCollection.Capture{
+		gazeCollection.NewCapture(0, vector.Vector3Zero(), vector.NewVector3(0, 0, 1), 1, 1, 3, 1),
+		gazeCollection.NewCapture(1, vector.Vector3Zero(), vector.NewVector3(0, 1, 0), 1, 1, 3, 1),
+	}
+	collections := []format.CaptureCollection{gazeCollection.NewCollection("Eyes", captures)}
+
+	_, raw, rawErr := gaze.NewEncoder(gaze.Raw32).Encode(collections)
+	_, compact, compactErr := gaze.NewEncoder(gaze.Compact).Encode(collections)
+
+	assert.NoError(t, rawErr)
+	assert.NoError(t, compactErr)
+	assert.Len(t, raw[0], 1+2*40)
+	assert.Len(t, compact[0], 1+2*21)
+}
+
+func Test_Gaze_RejectsOtherCaptures(t *testing.T) {
+	encoder := gaze.NewEncoder(gaze.Compact)
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "capture is not of type gaze")
+}
@@ -0,0 +1,41 @@
+package gaze
+
+import (
+	"math"
+
+	"github.com/EliCDavis/vector"
+)
+
+func signNotZero(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// OctahedralEncode maps a normalized direction onto a 2D square ranging from
+// -1 to 1 on both axes, by projecting it onto an octahedron and unfolding the
+// bottom half over the top.
+func OctahedralEncode(direction vector.Vector3) (float64, float64) {
+	l1 := math.Abs(direction.X()) + math.Abs(direction.Y()) + math.Abs(direction.Z())
+	if l1 == 0 {
+		return 0, 0
+	}
+
+	x := direction.X() / l1
+	y := direction.Y() / l1
+	if direction.Z() < 0 {
+		return (1 - math.Abs(y)) * signNotZero(x), (1 - math.Abs(x)) * signNotZero(y)
+	}
+	return x, y
+}
+
+// OctahedralDecode is the inverse of OctahedralEncode, returning a normalized
+// direction.
+func OctahedralDecode(x, y float64) vector.Vector3 {
+	z := 1 - math.Abs(x) - math.Abs(y)
+	if z < 0 {
+		x, y = (1-math.Abs(y))*signNotZero(x), (1-math.Abs(x))*signNotZero(y)
+	}
+	return vector.NewVector3(x, y, z).Normalized()
+}
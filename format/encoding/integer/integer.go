@@ -0,0 +1,249 @@
+package integer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/integer"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw writes every value as a full 64 bit integer
+	Raw StorageTechnique = iota
+
+	// ZigZagDelta writes the difference between consecutive values as a
+	// zig-zag encoded varint. Ideal for counters and IDs that steadily climb.
+	ZigZagDelta
+
+	// FrameOfReference writes the smallest value of the collection followed by
+	// every value's offset from it, bit-packed to the width of the largest
+	// offset. Ideal for values that stay within a narrow range.
+	FrameOfReference
+)
+
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (p Encoder) Accepts(stream format.CaptureCollection) bool {
+	return stream.Signature() == "recolude.int"
+}
+
+func (p Encoder) Signature() string {
+	return "recolude.int"
+}
+
+func (p Encoder) Version() uint {
+	return 0
+}
+
+func toValues(captures []format.Capture) ([]int64, error) {
+	values := make([]int64, len(captures))
+	for i, c := range captures {
+		intCapture, ok := c.(integer.Capture)
+		if !ok {
+			return nil, errors.New("capture is not of type int")
+		}
+		values[i] = intCapture.Value()
+	}
+	return values, nil
+}
+
+func encodeRaw(out io.Writer, values []int64) error {
+	for _, v := range values {
+		err := binary.Write(out, binary.LittleEndian, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeZigZagDelta(out io.Writer, values []int64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	previous := int64(0)
+	for _, v := range values {
+		read := binary.PutVarint(buf, v-previous)
+		_, err := out.Write(buf[:read])
+		if err != nil {
+			return err
+		}
+		previous = v
+	}
+	return nil
+}
+
+func encodeFrameOfReference(out io.Writer, values []int64) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	min := values[0]
+	max := values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	buf := make([]byte, binary.MaxVarintLen64)
+	read := binary.PutVarint(buf, min)
+	out.Write(buf[:read])
+
+	// Unsigned subtraction keeps the full range when max - min overflows
+	bits := rapbinary.BitsRequired(uint64(max) - uint64(min))
+	out.Write([]byte{byte(bits)})
+
+	writer := rapbinary.NewBitWriter()
+	for _, v := range values {
+		writer.Write(uint64(v)-uint64(min), bits)
+	}
+	_, err := out.Write(writer.Bytes())
+	return err
+}
+
+func (p Encoder) Encode(streams []format.CaptureCollection) ([]byte, [][]byte, error) {
+	streamDataBuffers := make([]bytes.Buffer, len(streams))
+	for bufferIndex, stream := range streams {
+		values, err := toValues(stream.Captures())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Write technique
+		streamDataBuffers[bufferIndex].WriteByte(byte(p.technique))
+
+		switch p.technique {
+		case Raw:
+			err = encodeRaw(&streamDataBuffers[bufferIndex], values)
+			break
+
+		case ZigZagDelta:
+			err = encodeZigZagDelta(&streamDataBuffers[bufferIndex], values)
+			break
+
+		case FrameOfReference:
+			err = encodeFrameOfReference(&streamDataBuffers[bufferIndex], values)
+			break
+
+		default:
+			return nil, nil, fmt.Errorf("unknown int encoding technique: %d", int(p.technique))
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	streamData := make([][]byte, len(streams))
+	for i, buffer := range streamDataBuffers {
+		streamData[i] = buffer.Bytes()
+	}
+
+	return nil, streamData, nil
+}
+
+func decodeRaw(in io.Reader, times []float64) ([]integer.Capture, error) {
+	captures := make([]integer.Capture, len(times))
+	var value int64
+	for i, time := range times {
+		err := binary.Read(in, binary.LittleEndian, &value)
+		if err != nil {
+			return nil, err
+		}
+		captures[i] = integer.NewCapture(time, value)
+	}
+	return captures, nil
+}
+
+func decodeZigZagDelta(in io.ByteReader, times []float64) ([]integer.Capture, error) {
+	captures := make([]integer.Capture, len(times))
+	value := int64(0)
+	for i, time := range times {
+		delta, err := binary.ReadVarint(in)
+		if err != nil {
+			return nil, err
+		}
+		value += delta
+		captures[i] = integer.NewCapture(time, value)
+	}
+	return captures, nil
+}
+
+func decodeFrameOfReference(in *bytes.Reader, times []float64) ([]integer.Capture, error) {
+	if len(times) == 0 {
+		return make([]integer.Capture, 0), nil
+	}
+
+	min, err := binary.ReadVarint(in)
+	if err != nil {
+		return nil, err
+	}
+
+	bits, err := in.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if bits > 64 {
+		return nil, fmt.Errorf("invalid frame of reference bit width: %d", bits)
+	}
+
+	reader := rapbinary.NewBitReader(in)
+	captures := make([]integer.Capture, len(times))
+	for i, time := range times {
+		offset, err := reader.Read(int(bits))
+		if err != nil {
+			return nil, err
+		}
+		captures[i] = integer.NewCapture(time, int64(uint64(min)+offset))
+	}
+	return captures, nil
+}
+
+func (p Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	reader := bytes.NewReader(streamData)
+
+	// Read Storage Technique
+	typeByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var captures []integer.Capture
+	switch StorageTechnique(typeByte) {
+	case Raw:
+		captures, err = decodeRaw(reader, times)
+		break
+
+	case ZigZagDelta:
+		captures, err = decodeZigZagDelta(reader, times)
+		break
+
+	case FrameOfReference:
+		captures, err = decodeFrameOfReference(reader, times)
+		break
+
+	default:
+		return nil, fmt.Errorf("unknown int encoding technique: %d", int(typeByte))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return integer.NewCollection(name, captures), nil
+}
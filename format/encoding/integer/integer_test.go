@@ -0,0 +1,121 @@
+package integer_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	intCollection "github.com/recolude/rap/format/collection/integer"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/encoding/integer"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Integer(t *testing.T) {
+	tickCaptures := make([]intCollection.Capture, 2000)
+	tickTimes := make([]float64, len(tickCaptures))
+	tick := int64(1 << 60)
+	for i := range tickCaptures {
+		tickTimes[i] = float64(i) / 60.0
+		tickCaptures[i] = intCollection.NewCapture(tickTimes[i], tick)
+		tick += rand.Int63n(3)
+	}
+
+	tests := map[string]struct {
+		streamName string
+		captures   []intCollection.Capture
+		times      []float64
+	}{
+		"nil ints": {streamName: "", captures: nil},
+		"0-ints":   {streamName: "empty stream", captures: []intCollection.Capture{}},
+		"1-int": {
+			streamName: "score",
+			captures: []intCollection.Capture{
+				intCollection.NewCapture(1.2, 42),
+			},
+			times: []float64{1.2},
+		},
+		"3-ints": {
+			streamName: "ammo",
+			captures: []intCollection.Capture{
+				intCollection.NewCapture(1, 30),
+				intCollection.NewCapture(2, 29),
+				intCollection.NewCapture(3, -4),
+			},
+			times: []float64{1, 2, 3},
+		},
+		"extremes": {
+			streamName: "extremes",
+			captures: []intCollection.Capture{
+				intCollection.NewCapture(1, math.MaxInt64),
+				intCollection.NewCapture(2, math.MinInt64),
+				intCollection.NewCapture(3, 0),
+				intCollection.NewCapture(4, (1<<53)+1),
+			},
+			times: []float64{1, 2, 3, 4},
+		},
+		"2000-network-ticks": {streamName: "ticks", captures: tickCaptures, times: tickTimes},
+	}
+
+	techniques := map[string]integer.StorageTechnique{
+		"Raw":              integer.Raw,
+		"ZigZagDelta":      integer.ZigZagDelta,
+		"FrameOfReference": integer.FrameOfReference,
+	}
+
+	for techniqueName, technique := range techniques {
+		for name, tc := range tests {
+			t.Run(fmt.Sprintf("%s/%s", techniqueName, name), func(t *testing.T) {
+				collectionIn := intCollection.NewCollection(tc.streamName, tc.captures)
+				encoder := integer.NewEncoder(technique)
+				assert.Equal(t, "recolude.int", encoder.Signature())
+				assert.Equal(t, uint(0), encoder.Version())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ====================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode(tc.streamName, header, collectionData[0], tc.times)
+
+				// ASSERT =================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				if assert.NotNil(t, collectionOut) && assert.Len(t, collectionOut.Captures(), len(tc.captures)) {
+					assert.Equal(t, tc.streamName, collectionOut.Name())
+					for i, c := range collectionOut.Captures() {
+						assert.Equal(t, tc.captures[i], c)
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_CompactTechniquesBeatRaw(t *testing.T) {
+	captures := make([]intCollection.Capture, 1000)
+	for i := range captures {
+		captures[i] = intCollection.NewCapture(float64(i), int64(1000000+i%10))
+	}
+	collectionIn := []format.CaptureCollection{intCollection.NewCollection("frames", captures)}
+
+	_, raw, rawErr := integer.NewEncoder(integer.Raw).Encode(collectionIn)
+	_, delta, deltaErr := integer.NewEncoder(integer.ZigZagDelta).Encode(collectionIn)
+	_, frame, frameErr := integer.NewEncoder(integer.FrameOfReference).Encode(collectionIn)
+
+	assert.NoError(t, rawErr)
+	assert.NoError(t, deltaErr)
+	assert.NoError(t, frameErr)
+	assert.Less(t, len(delta[0]), len(raw[0]))
+	assert.Less(t, len(frame[0]), len(delta[0]))
+}
+
+func Test_RejectsOtherCaptures(t *testing.T) {
+	encoder := integer.NewEncoder(integer.Raw)
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "capture is not of type int")
+}
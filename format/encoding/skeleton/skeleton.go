@@ -0,0 +1,361 @@
+package skeleton
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/skeleton"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw32 stores the root position and every joint rotation at 32bit
+	// precision
+	Raw32 StorageTechnique = iota
+
+	// Quantized16 stores the root position at 32bit precision, and every
+	// joint rotation in 16 bits per axis. All joints share the same
+	// quantization range.
+	Quantized16
+
+	// Quantized8 stores the root position at 32bit precision, and every
+	// joint rotation in 8 bits per axis. All joints share the same
+	// quantization range.
+	Quantized8
+)
+
+// Encoder writes skeleton collections. Skeleton definitions are stored in
+// the header, and collections that share the same skeleton reference the
+// same definition.
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.skeleton"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.skeleton"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
+
+func writeVector3(out io.Writer, v vector.Vector3) {
+	binary.Write(out, binary.LittleEndian, float32(v.X()))
+	binary.Write(out, binary.LittleEndian, float32(v.Y()))
+	binary.Write(out, binary.LittleEndian, float32(v.Z()))
+}
+
+func readVector3(in io.Reader) vector.Vector3 {
+	var x, y, z float32
+	binary.Read(in, binary.LittleEndian, &x)
+	binary.Read(in, binary.LittleEndian, &y)
+	binary.Read(in, binary.LittleEndian, &z)
+	return vector.NewVector3(float64(x), float64(y), float64(z))
+}
+
+func skeletonToBytes(s skeleton.Skeleton) []byte {
+	out := bytes.Buffer{}
+
+	names := make([]string, s.JointCount())
+	parents := make([]uint, s.JointCount())
+	for i, joint := range s.Joints() {
+		names[i] = joint.Name
+
+		// Shifted by one so roots can be stored unsigned
+		parents[i] = uint(joint.Parent + 1)
+	}
+
+	out.Write(rapbinary.StringArrayToBytes(names))
+	out.Write(rapbinary.UvarintArrayToBytes(parents))
+	for _, joint := range s.Joints() {
+		writeVector3(&out, joint.Offset)
+	}
+
+	return out.Bytes()
+}
+
+func readSkeleton(in *bytes.Reader) (skeleton.Skeleton, error) {
+	names, _, err := rapbinary.ReadStringArray(in)
+	if err != nil {
+		return skeleton.Skeleton{}, err
+	}
+
+	parents, _, err := rapbinary.ReadUvarIntArray(in)
+	if err != nil {
+		return skeleton.Skeleton{}, err
+	}
+
+	if len(names) != len(parents) {
+		return skeleton.Skeleton{}, fmt.Errorf("skeleton has %d joint names but %d parents", len(names), len(parents))
+	}
+
+	errReader := rapbinary.NewErrReader(in)
+	joints := make([]skeleton.Joint, len(names))
+	for i := range joints {
+		joints[i] = skeleton.Joint{
+			Name:   names[i],
+			Parent: int(parents[i]) - 1,
+			Offset: readVector3(errReader),
+		}
+	}
+	if errReader.Error() != nil {
+		return skeleton.Skeleton{}, errReader.Error()
+	}
+
+	return skeleton.NewSkeleton(joints)
+}
+
+// rotationBounds finds the smallest and largest rotation component across
+// every joint of every capture, so all joints can share one quantization
+// range.
+func rotationBounds(captures []skeleton.Capture) (float64, float64) {
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, capture := range captures {
+		for _, rot := range capture.Rotations() {
+			min = math.Min(min, math.Min(rot.X(), math.Min(rot.Y(), rot.Z())))
+			max = math.Max(max, math.Max(rot.X(), math.Max(rot.Y(), rot.Z())))
+		}
+	}
+	if math.IsInf(min, 1) {
+		return 0, 0
+	}
+	return min, max
+}
+
+func quantize(value, min, max float64, steps uint64) uint64 {
+	if max <= min {
+		return 0
+	}
+	return uint64(math.Round((value - min) / (max - min) * float64(steps)))
+}
+
+func dequantize(value uint64, min, max float64, steps uint64) float64 {
+	if max <= min {
+		return min
+	}
+	return min + (float64(value)/float64(steps))*(max-min)
+}
+
+func (e Encoder) encodeCaptures(out *bytes.Buffer, captures []skeleton.Capture) error {
+	switch e.technique {
+	case Raw32:
+		for _, capture := range captures {
+			writeVector3(out, capture.Root())
+			for _, rot := range capture.Rotations() {
+				writeVector3(out, rot)
+			}
+		}
+		return nil
+
+	case Quantized16, Quantized8:
+		bits := 16
+		if e.technique == Quantized8 {
+			bits = 8
+		}
+		steps := uint64(1)<<bits - 1
+
+		min, max := rotationBounds(captures)
+		binary.Write(out, binary.LittleEndian, float32(min))
+		binary.Write(out, binary.LittleEndian, float32(max))
+
+		// Decode with the same bounds the reader will see
+		min = float64(float32(min))
+		max = float64(float32(max))
+
+		for _, capture := range captures {
+			writeVector3(out, capture.Root())
+		}
+
+		rotations := rapbinary.NewBitWriter()
+		for _, capture := range captures {
+			for _, rot := range capture.Rotations() {
+				rotations.Write(quantize(rot.X(), min, max, steps), bits)
+				rotations.Write(quantize(rot.Y(), min, max, steps), bits)
+				rotations.Write(quantize(rot.Z(), min, max, steps), bits)
+			}
+		}
+		out.Write(rotations.Bytes())
+		return nil
+	}
+
+	return fmt.Errorf("unknown skeleton encoding technique: %d", int(e.technique))
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	skeletonIndexes := make(map[string]int)
+	allSkeletons := make([][]byte, 0)
+
+	streamData := make([][]byte, len(collections))
+	for collectionIndex, c := range collections {
+		collection, ok := c.(skeleton.Collection)
+		if !ok {
+			return nil, nil, errors.New("collection is not of type skeleton")
+		}
+
+		definition := skeletonToBytes(collection.Skeleton())
+		skeletonIndex, ok := skeletonIndexes[string(definition)]
+		if !ok {
+			skeletonIndex = len(allSkeletons)
+			skeletonIndexes[string(definition)] = skeletonIndex
+			allSkeletons = append(allSkeletons, definition)
+		}
+
+		captures := make([]skeleton.Capture, collection.Length())
+		for i := range captures {
+			capture, ok := collection.CaptureAt(i).(skeleton.Capture)
+			if !ok {
+				return nil, nil, errors.New("capture is not of type skeleton")
+			}
+			if len(capture.Rotations()) != collection.Skeleton().JointCount() {
+				return nil, nil, fmt.Errorf(
+					"skeleton capture %d in collection %s has %d rotations but skeleton has %d joints",
+					i,
+					collection.Name(),
+					len(capture.Rotations()),
+					collection.Skeleton().JointCount(),
+				)
+			}
+			captures[i] = capture
+		}
+
+		out := bytes.Buffer{}
+		indexBuffer := make([]byte, binary.MaxVarintLen64)
+		out.Write(indexBuffer[:binary.PutUvarint(indexBuffer, uint64(skeletonIndex))])
+		out.WriteByte(byte(e.technique))
+		if err := e.encodeCaptures(&out, captures); err != nil {
+			return nil, nil, err
+		}
+		streamData[collectionIndex] = out.Bytes()
+	}
+
+	header := bytes.Buffer{}
+	countBuffer := make([]byte, binary.MaxVarintLen64)
+	header.Write(countBuffer[:binary.PutUvarint(countBuffer, uint64(len(allSkeletons)))])
+	for _, definition := range allSkeletons {
+		header.Write(definition)
+	}
+
+	return header.Bytes(), streamData, nil
+}
+
+func decodeRaw32(in io.Reader, jointCount int, times []float64) ([]skeleton.Capture, error) {
+	errReader := rapbinary.NewErrReader(in)
+	captures := make([]skeleton.Capture, len(times))
+	for i := range times {
+		root := readVector3(errReader)
+		rotations := make([]vector.Vector3, jointCount)
+		for j := range rotations {
+			rotations[j] = readVector3(errReader)
+		}
+		captures[i] = skeleton.NewCapture(times[i], root, rotations)
+	}
+	return captures, errReader.Error()
+}
+
+func decodeQuantized(in io.Reader, bits int, jointCount int, times []float64) ([]skeleton.Capture, error) {
+	errReader := rapbinary.NewErrReader(in)
+	steps := uint64(1)<<bits - 1
+
+	var min, max float32
+	binary.Read(errReader, binary.LittleEndian, &min)
+	binary.Read(errReader, binary.LittleEndian, &max)
+
+	roots := make([]vector.Vector3, len(times))
+	for i := range roots {
+		roots[i] = readVector3(errReader)
+	}
+
+	if errReader.Error() != nil {
+		return nil, errReader.Error()
+	}
+
+	bitReader := rapbinary.NewBitReader(errReader)
+	component := func() float64 {
+		value, _ := bitReader.Read(bits)
+		return dequantize(value, float64(min), float64(max), steps)
+	}
+
+	captures := make([]skeleton.Capture, len(times))
+	for i := range times {
+		rotations := make([]vector.Vector3, jointCount)
+		for j := range rotations {
+			x := component()
+			y := component()
+			z := component()
+			rotations[j] = vector.NewVector3(x, y, z)
+		}
+		captures[i] = skeleton.NewCapture(times[i], roots[i], rotations)
+	}
+
+	return captures, errReader.Error()
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	headerReader := bytes.NewReader(header)
+	skeletonCount, err := binary.ReadUvarint(headerReader)
+	if err != nil {
+		return nil, err
+	}
+
+	skeletons := make([]skeleton.Skeleton, skeletonCount)
+	for i := range skeletons {
+		skeletons[i], err = readSkeleton(headerReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reader := bytes.NewReader(streamData)
+	skeletonIndex, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if skeletonIndex >= skeletonCount {
+		return nil, fmt.Errorf("skeleton index %d out of range of header", skeletonIndex)
+	}
+	definition := skeletons[skeletonIndex]
+
+	techniqueByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var captures []skeleton.Capture
+	switch StorageTechnique(techniqueByte) {
+	case Raw32:
+		captures, err = decodeRaw32(reader, definition.JointCount(), times)
+
+	case Quantized16:
+		captures, err = decodeQuantized(reader, 16, definition.JointCount(), times)
+
+	case Quantized8:
+		captures, err = decodeQuantized(reader, 8, definition.JointCount(), times)
+
+	default:
+		return nil, fmt.Errorf("unknown skeleton encoding technique: %d", int(techniqueByte))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return skeleton.NewCollection(name, definition, captures), nil
+}
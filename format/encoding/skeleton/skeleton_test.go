@@ -0,0 +1,162 @@
+package skeleton_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	skeletonCollection "github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/stretchr/testify/assert"
+)
+
+func body(t *testing.T, jointCount int) skeletonCollection.Skeleton {
+	joints := make([]skeletonCollection.Joint, jointCount)
+	for i := range joints {
+		joints[i] = skeletonCollection.Joint{
+			Name:   fmt.Sprintf("Joint %d", i),
+			Parent: i - 1,
+			Offset: vector.NewVector3(0, 0.25, 0),
+		}
+	}
+	s, err := skeletonCollection.NewSkeleton(joints)
+	assert.NoError(t, err)
+	return s
+}
+
+func randomPoses(jointCount, poseCount int) ([]skeletonCollection.Capture, []float64) {
+	captures := make([]skeletonCollection.Capture, poseCount)
+	times := make([]float64, poseCount)
+	for i := range captures {
+		rotations := make([]vector.Vector3, jointCount)
+		for j := range rotations {
+			rotations[j] = vector.NewVector3(rand.Float64()*360-180, rand.Float64()*360-180, rand.Float64()*360-180)
+		}
+		times[i] = float64(i) / 90.0
+		captures[i] = skeletonCollection.NewCapture(
+			times[i],
+			vector.NewVector3(rand.Float64()*10, rand.Float64(), rand.Float64()*10),
+			rotations,
+		)
+	}
+	return captures, times
+}
+
+func Test_Skeleton(t *testing.T) {
+	definition := body(t, 24)
+	poses, poseTimes := randomPoses(24, 200)
+
+	tests := map[string]struct {
+		captures []skeletonCollection.Capture
+		times    []float64
+	}{
+		"nil poses": {captures: nil},
+		"0-poses":   {captures: []skeletonCollection.Capture{}},
+		"1-pose":    {captures: poses[:1], times: poseTimes[:1]},
+		"200-poses": {captures: poses, times: poseTimes},
+	}
+
+	techniques := []struct {
+		displayName       string
+		technique         skeleton.StorageTechnique
+		rotationTolerance float64
+	}{
+		{displayName: "Raw32", technique: skeleton.Raw32, rotationTolerance: 0.0001},
+		{displayName: "Quantized16", technique: skeleton.Quantized16, rotationTolerance: 0.003},
+		{displayName: "Quantized8", technique: skeleton.Quantized8, rotationTolerance: 0.71},
+	}
+
+	for name, tc := range tests {
+		for _, technique := range techniques {
+			t.Run(fmt.Sprintf("%s/%s", name, technique.displayName), func(t *testing.T) {
+				collectionIn := skeletonCollection.NewCollection("Avatar", definition, tc.captures)
+				encoder := skeleton.NewEncoder(technique.technique)
+				assert.Equal(t, "recolude.skeleton", encoder.Signature())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ========================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode("Avatar", header, collectionData[0], tc.times)
+
+				// ASSERT =====================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				if assert.NotNil(t, collectionOut) == false {
+					return
+				}
+
+				out := collectionOut.(skeletonCollection.Collection)
+				assert.Equal(t, "Avatar", out.Name())
+				assert.Equal(t, definition.Joints()[3].Name, out.Skeleton().Joints()[3].Name)
+				assert.Equal(t, definition.Joints()[3].Parent, out.Skeleton().Joints()[3].Parent)
+				if assert.Equal(t, len(tc.captures), out.Length()) == false {
+					return
+				}
+
+				for i, c := range out.Captures() {
+					pose := c.(skeletonCollection.Capture)
+					assert.InDelta(t, tc.captures[i].Root().X(), pose.Root().X(), 0.0001)
+					assert.InDelta(t, tc.captures[i].Root().Z(), pose.Root().Z(), 0.0001)
+					for j, rot := range pose.Rotations() {
+						expected := tc.captures[i].Rotations()[j]
+						if !assert.InDelta(t, expected.X(), rot.X(), technique.rotationTolerance) ||
+							!assert.InDelta(t, expected.Y(), rot.Y(), technique.rotationTolerance) ||
+							!assert.InDelta(t, expected.Z(), rot.Z(), technique.rotationTolerance) {
+							return
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_Skeleton_SharesDefinitions(t *testing.T) {
+	definition := body(t, 3)
+	other := body(t, 4)
+	poses, _ := randomPoses(3, 1)
+	otherPoses, _ := randomPoses(4, 1)
+
+	encoder := skeleton.NewEncoder(skeleton.Raw32)
+	header, collectionData, err := encoder.Encode([]format.CaptureCollection{
+		skeletonCollection.NewCollection("a", definition, poses),
+		skeletonCollection.NewCollection("b", definition, poses),
+		skeletonCollection.NewCollection("c", other, otherPoses),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, byte(2), header[0])
+	assert.Equal(t, byte(0), collectionData[0][0])
+	assert.Equal(t, byte(0), collectionData[1][0])
+	assert.Equal(t, byte(1), collectionData[2][0])
+
+	out, err := encoder.Decode("c", header, collectionData[2], []float64{0})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, out.(skeletonCollection.Collection).Skeleton().JointCount())
+}
+
+func Test_Skeleton_QuantizedSmallerThanRaw(t *testing.T) {
+	definition := body(t, 24)
+	poses, _ := randomPoses(24, 50)
+	collections := []format.CaptureCollection{skeletonCollection.NewCollection("a", definition, poses)}
+
+	_, raw, rawErr := skeleton.NewEncoder(skeleton.Raw32).Encode(collections)
+	_, quantized, quantizedErr := skeleton.NewEncoder(skeleton.Quantized16).Encode(collections)
+
+	assert.NoError(t, rawErr)
+	assert.NoError(t, quantizedErr)
+	assert.Less(t, len(quantized[0]), len(raw[0])*6/10)
+}
+
+func Test_Skeleton_RejectsMismatchedRotations(t *testing.T) {
+	definition := body(t, 3)
+	poses, _ := randomPoses(2, 1)
+
+	_, _, err := skeleton.NewEncoder(skeleton.Raw32).Encode([]format.CaptureCollection{
+		skeletonCollection.NewCollection("a", definition, poses),
+	})
+
+	assert.EqualError(t, err, "skeleton capture 0 in collection a has 2 rotations but skeleton has 3 joints")
+}
@@ -0,0 +1,169 @@
+package span
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/metadata"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+// Encoder writes span collections. Labels and metadata keys are stored once
+// in the header and shared by all span collections within the file, while
+// each capture only stores indexes into them alongside its duration.
+type Encoder struct{}
+
+func NewEncoder() Encoder {
+	return Encoder{}
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.span"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.span"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
+
+func encodeSpans(out io.Writer, captures []span.Capture, labels, keys map[string]int) {
+	varintBuffer := make([]byte, binary.MaxVarintLen64)
+	durationBuffer := make([]byte, 8)
+	for _, capture := range captures {
+		read := binary.PutUvarint(varintBuffer, uint64(labels[capture.Label()]))
+		out.Write(varintBuffer[:read])
+
+		binary.LittleEndian.PutUint64(durationBuffer, math.Float64bits(capture.Duration()))
+		out.Write(durationBuffer)
+
+		keyIndexes := make([]uint, 0, len(capture.Metadata().Mapping()))
+		values := bytes.Buffer{}
+		for _, key := range capture.Metadata().Keys() {
+			val := capture.Metadata().Mapping()[key]
+			keyIndexes = append(keyIndexes, uint(keys[key]))
+			values.WriteByte(val.Code())
+			values.Write(val.Data())
+		}
+
+		out.Write(rapbinary.UvarintArrayToBytes(keyIndexes))
+		out.Write(values.Bytes())
+	}
+}
+
+func (Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	labels := make(map[string]int)
+	keys := make(map[string]int)
+
+	allCaptures := make([][]span.Capture, len(collections))
+	for collectionIndex, collection := range collections {
+		allCaptures[collectionIndex] = make([]span.Capture, collection.Length())
+		for captureIndex := range allCaptures[collectionIndex] {
+			capture, ok := collection.CaptureAt(captureIndex).(span.Capture)
+			if !ok {
+				return nil, nil, errors.New("capture is not of type span")
+			}
+
+			if capture.End() < capture.Start() {
+				return nil, nil, fmt.Errorf("span %s in collection %s ends before it starts", capture.Label(), collection.Name())
+			}
+
+			if _, ok := labels[capture.Label()]; !ok {
+				labels[capture.Label()] = len(labels)
+			}
+
+			for _, key := range capture.Metadata().Keys() {
+				if _, ok := keys[key]; !ok {
+					keys[key] = len(keys)
+				}
+			}
+
+			allCaptures[collectionIndex][captureIndex] = capture
+		}
+	}
+
+	streamData := make([][]byte, len(collections))
+	for i, captures := range allCaptures {
+		buffer := bytes.Buffer{}
+		encodeSpans(&buffer, captures, labels, keys)
+		streamData[i] = buffer.Bytes()
+	}
+
+	allLabels := make([]string, len(labels))
+	for label, index := range labels {
+		allLabels[index] = label
+	}
+
+	allKeys := make([]string, len(keys))
+	for key, index := range keys {
+		allKeys[index] = key
+	}
+
+	header := bytes.Buffer{}
+	header.Write(rapbinary.StringArrayToBytes(allLabels))
+	header.Write(rapbinary.StringArrayToBytes(allKeys))
+
+	return header.Bytes(), streamData, nil
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	headerReader := bytes.NewReader(header)
+	labels, _, err := rapbinary.ReadStringArray(headerReader)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, _, err := rapbinary.ReadStringArray(headerReader)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufio.NewReader(bytes.NewReader(streamData))
+	durationBuffer := make([]byte, 8)
+	captures := make([]span.Capture, len(times))
+	for i := range times {
+		labelIndex, err := binary.ReadUvarint(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if labelIndex >= uint64(len(labels)) {
+			return nil, fmt.Errorf("span label index %d out of range of header", labelIndex)
+		}
+
+		if _, err := io.ReadFull(buf, durationBuffer); err != nil {
+			return nil, err
+		}
+		duration := math.Float64frombits(binary.LittleEndian.Uint64(durationBuffer))
+
+		keyIndexes, _, err := rapbinary.ReadUvarIntArray(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		block := make(map[string]metadata.Property)
+		for _, keyIndex := range keyIndexes {
+			prop, err := metadata.ReadProperty(buf)
+			if err != nil {
+				return nil, err
+			}
+			if keyIndex >= uint(len(keys)) {
+				return nil, fmt.Errorf("span metadata key index %d out of range of header", keyIndex)
+			}
+			block[keys[keyIndex]] = prop
+		}
+
+		captures[i] = span.NewCapture(times[i], times[i]+duration, labels[labelIndex], metadata.NewBlock(block))
+	}
+
+	return span.NewCollection(name, captures), nil
+}
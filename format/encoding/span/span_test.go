@@ -0,0 +1,121 @@
+package span_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	spanCollection "github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/encoding/span"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Span(t *testing.T) {
+	block := metadata.NewBlock(map[string]metadata.Property{
+		"speaker": metadata.NewStringProperty("Guard"),
+		"line":    metadata.NewIntProperty(12),
+	})
+
+	tests := map[string]struct {
+		captures []spanCollection.Capture
+		times    []float64
+	}{
+		"nil spans": {captures: nil},
+		"0-spans":   {captures: []spanCollection.Capture{}},
+		"1-span": {
+			captures: []spanCollection.Capture{spanCollection.NewCapture(1, 3.5, "Dialog", block)},
+			times:    []float64{1},
+		},
+		"3-spans": {
+			captures: []spanCollection.Capture{
+				spanCollection.NewCapture(1, 3.5, "Dialog", block),
+				spanCollection.NewCapture(2, 2, "Blink", metadata.EmptyBlock()),
+				spanCollection.NewCapture(2.5, 10, "Dialog", block),
+			},
+			times: []float64{1, 2, 2.5},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			collectionIn := spanCollection.NewCollection("Activities", tc.captures)
+			encoder := span.NewEncoder()
+			assert.Equal(t, "recolude.span", encoder.Signature())
+			assert.Equal(t, uint(0), encoder.Version())
+			assert.True(t, encoder.Accepts(collectionIn))
+
+			// ACT ============================================================
+			header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+			collectionOut, decodeErr := encoder.Decode("Activities", header, collectionData[0], tc.times)
+
+			// ASSERT =========================================================
+			assert.NoError(t, encodeErr)
+			assert.NoError(t, decodeErr)
+			if assert.NotNil(t, collectionOut) && assert.Len(t, collectionOut.Captures(), len(tc.captures)) {
+				assert.Equal(t, "Activities", collectionOut.Name())
+				for i, c := range collectionOut.Captures() {
+					assert.Equal(t, tc.captures[i], c)
+				}
+			}
+		})
+	}
+}
+
+func Test_Span_SharesLabelsAcrossCollections(t *testing.T) {
+	// ARRANGE ================================================================
+	first := spanCollection.NewCollection("a", []spanCollection.Capture{
+		spanCollection.NewCapture(1, 2, "Zone: Forest", metadata.EmptyBlock()),
+	})
+	second := spanCollection.NewCollection("b", []spanCollection.Capture{
+		spanCollection.NewCapture(3, 4, "Zone: Forest", metadata.EmptyBlock()),
+	})
+	encoder := span.NewEncoder()
+
+	// ACT ====================================================================
+	header, collectionData, err := encoder.Encode([]format.CaptureCollection{first, second})
+	firstOut, firstErr := encoder.Decode("a", header, collectionData[0], []float64{1})
+	secondOut, secondErr := encoder.Decode("b", header, collectionData[1], []float64{3})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, first, firstOut)
+	assert.Equal(t, second, secondOut)
+	assert.Equal(t, collectionData[0], collectionData[1])
+}
+
+func Test_Span_DurationSurvivesTimeChanges(t *testing.T) {
+	collectionIn := spanCollection.NewCollection("a", []spanCollection.Capture{
+		spanCollection.NewCapture(1, 2.5, "Sprint", metadata.EmptyBlock()),
+	})
+	encoder := span.NewEncoder()
+
+	header, collectionData, err := encoder.Encode([]format.CaptureCollection{collectionIn})
+	collectionOut, decodeErr := encoder.Decode("a", header, collectionData[0], []float64{1.01})
+
+	assert.NoError(t, err)
+	assert.NoError(t, decodeErr)
+	assert.InDelta(t, 1.5, collectionOut.CaptureAt(0).(spanCollection.Capture).Duration(), 0.0000001)
+}
+
+func Test_Span_RejectsBackwardsSpans(t *testing.T) {
+	collection := spanCollection.NewCollection("a", []spanCollection.Capture{
+		spanCollection.NewCapture(3, 2, "Sprint", metadata.EmptyBlock()),
+	})
+
+	_, _, err := span.NewEncoder().Encode([]format.CaptureCollection{collection})
+
+	assert.EqualError(t, err, "span Sprint in collection a ends before it starts")
+}
+
+func Test_Span_RejectsOtherCaptures(t *testing.T) {
+	encoder := span.NewEncoder()
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "capture is not of type span")
+}
@@ -0,0 +1,431 @@
+package structure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/structure"
+	rapbinary "github.com/recolude/rap/internal/io/binary"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw64 encodes float and vector3 fields with maximum precision
+	Raw64 StorageTechnique = iota
+
+	// Raw32 truncates float and vector3 fields to 32bit
+	Raw32
+
+	// BST16 encodes each float and each vector3 component within 16 bits,
+	// using the min and max of its column
+	BST16
+)
+
+// Encoder writes struct collections. Each stream declares its own schema,
+// followed by every field stored as its own column. The storage technique
+// only affects float and vector3 columns; ints are written as zigzag deltas,
+// bools and enums are bit packed, and strings are stored as indexes into a
+// dictionary of the column's unique values.
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.struct"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.struct"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
+
+func bitsPerValue(numMembers int) int {
+	if numMembers < 2 {
+		return 0
+	}
+	return rapbinary.BitsRequired(uint64(numMembers - 1))
+}
+
+func writeSchema(out io.Writer, schema structure.Schema) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	read := binary.PutUvarint(buf, uint64(len(schema.Fields())))
+	out.Write(buf[:read])
+
+	for _, field := range schema.Fields() {
+		out.Write(rapbinary.StringToBytes(field.Name))
+		out.Write([]byte{byte(field.Type)})
+		if field.Type == structure.Enum {
+			out.Write(rapbinary.StringArrayToBytes(field.Options))
+		}
+	}
+}
+
+func readSchema(in *rapbinary.ErrReader) (structure.Schema, error) {
+	numFields, _, err := rapbinary.ReadUvarint(in)
+	if err != nil {
+		return structure.Schema{}, err
+	}
+
+	fields := make([]structure.Field, 0)
+	for i := uint64(0); i < numFields; i++ {
+		name, _, err := rapbinary.ReadString(in)
+		if err != nil {
+			return structure.Schema{}, err
+		}
+
+		fieldType, err := in.ReadByte()
+		if err != nil {
+			return structure.Schema{}, err
+		}
+
+		field := structure.Field{Name: name, Type: structure.FieldType(fieldType)}
+		if field.Type == structure.Enum {
+			field.Options, _, err = rapbinary.ReadStringArray(in)
+			if err != nil {
+				return structure.Schema{}, err
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	return structure.NewSchema(fields)
+}
+
+func floatBounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return float64(float32(min)), float64(float32(max))
+}
+
+func (e Encoder) encodeFloats(out io.Writer, values []float64) {
+	switch e.technique {
+	case Raw64:
+		for _, v := range values {
+			binary.Write(out, binary.LittleEndian, v)
+		}
+
+	case Raw32:
+		for _, v := range values {
+			binary.Write(out, binary.LittleEndian, float32(v))
+		}
+
+	case BST16:
+		min, max := floatBounds(values)
+		binary.Write(out, binary.LittleEndian, float32(min))
+		binary.Write(out, binary.LittleEndian, float32(max))
+
+		buffer := make([]byte, 2)
+		for _, v := range values {
+			rapbinary.UnsignedFloatBSTToBytes(v, min, max-min, buffer)
+			out.Write(buffer)
+		}
+	}
+}
+
+func decodeFloats(in io.Reader, technique StorageTechnique, count int) []float64 {
+	values := make([]float64, count)
+	switch technique {
+	case Raw64:
+		for i := range values {
+			binary.Read(in, binary.LittleEndian, &values[i])
+		}
+
+	case Raw32:
+		var v float32
+		for i := range values {
+			binary.Read(in, binary.LittleEndian, &v)
+			values[i] = float64(v)
+		}
+
+	case BST16:
+		var min, max float32
+		binary.Read(in, binary.LittleEndian, &min)
+		binary.Read(in, binary.LittleEndian, &max)
+
+		buffer := make([]byte, 2)
+		for i := range values {
+			in.Read(buffer)
+			values[i] = rapbinary.BytesToUnisngedFloatBST(float64(min), float64(max-min), buffer)
+		}
+	}
+	return values
+}
+
+func encodeInts(out io.Writer, values []int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	previous := int64(0)
+	for _, v := range values {
+		read := binary.PutVarint(buf, v-previous)
+		out.Write(buf[:read])
+		previous = v
+	}
+}
+
+func decodeInts(in io.ByteReader, count int) ([]int64, error) {
+	values := make([]int64, count)
+	previous := int64(0)
+	for i := range values {
+		delta, err := binary.ReadVarint(in)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = previous + delta
+		previous = values[i]
+	}
+	return values, nil
+}
+
+func encodeIndexes(out io.Writer, indexes []int, numMembers int) {
+	bits := bitsPerValue(numMembers)
+	writer := rapbinary.NewBitWriter()
+	for _, index := range indexes {
+		writer.Write(uint64(index), bits)
+	}
+	out.Write(writer.Bytes())
+}
+
+func decodeIndexes(in io.Reader, count int, numMembers int) ([]int, error) {
+	bits := bitsPerValue(numMembers)
+	reader := rapbinary.NewBitReader(in)
+	indexes := make([]int, count)
+	for i := range indexes {
+		value, err := reader.Read(bits)
+		if err != nil {
+			return nil, err
+		}
+		if value >= uint64(numMembers) {
+			return nil, fmt.Errorf("struct column index %d out of range of %d members", value, numMembers)
+		}
+		indexes[i] = int(value)
+	}
+	return indexes, nil
+}
+
+func (e Encoder) encodeColumn(out io.Writer, field structure.Field, column []interface{}) {
+	switch field.Type {
+	case structure.Float:
+		values := make([]float64, len(column))
+		for i, v := range column {
+			values[i] = v.(float64)
+		}
+		e.encodeFloats(out, values)
+
+	case structure.Vector3:
+		x := make([]float64, len(column))
+		y := make([]float64, len(column))
+		z := make([]float64, len(column))
+		for i, v := range column {
+			x[i] = v.(vector.Vector3).X()
+			y[i] = v.(vector.Vector3).Y()
+			z[i] = v.(vector.Vector3).Z()
+		}
+		e.encodeFloats(out, x)
+		e.encodeFloats(out, y)
+		e.encodeFloats(out, z)
+
+	case structure.Int:
+		values := make([]int64, len(column))
+		for i, v := range column {
+			values[i] = v.(int64)
+		}
+		encodeInts(out, values)
+
+	case structure.Bool:
+		indexes := make([]int, len(column))
+		for i, v := range column {
+			if v.(bool) {
+				indexes[i] = 1
+			}
+		}
+		encodeIndexes(out, indexes, 2)
+
+	case structure.Enum:
+		indexes := make([]int, len(column))
+		for i, v := range column {
+			indexes[i] = v.(int)
+		}
+		encodeIndexes(out, indexes, len(field.Options))
+
+	case structure.String:
+		dictionary := make([]string, 0)
+		lookup := make(map[string]int)
+		indexes := make([]int, len(column))
+		for i, v := range column {
+			index, ok := lookup[v.(string)]
+			if !ok {
+				index = len(dictionary)
+				lookup[v.(string)] = index
+				dictionary = append(dictionary, v.(string))
+			}
+			indexes[i] = index
+		}
+		out.Write(rapbinary.StringArrayToBytes(dictionary))
+		encodeIndexes(out, indexes, len(dictionary))
+	}
+}
+
+func decodeColumn(in *rapbinary.ErrReader, technique StorageTechnique, field structure.Field, count int) ([]interface{}, error) {
+	column := make([]interface{}, count)
+	switch field.Type {
+	case structure.Float:
+		for i, v := range decodeFloats(in, technique, count) {
+			column[i] = v
+		}
+
+	case structure.Vector3:
+		x := decodeFloats(in, technique, count)
+		y := decodeFloats(in, technique, count)
+		z := decodeFloats(in, technique, count)
+		for i := range column {
+			column[i] = vector.NewVector3(x[i], y[i], z[i])
+		}
+
+	case structure.Int:
+		values, err := decodeInts(in, count)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			column[i] = v
+		}
+
+	case structure.Bool:
+		indexes, err := decodeIndexes(in, count, 2)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range indexes {
+			column[i] = v == 1
+		}
+
+	case structure.Enum:
+		indexes, err := decodeIndexes(in, count, len(field.Options))
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range indexes {
+			column[i] = v
+		}
+
+	case structure.String:
+		dictionary, _, err := rapbinary.ReadStringArray(in)
+		if err != nil {
+			return nil, err
+		}
+		indexes, err := decodeIndexes(in, count, len(dictionary))
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range indexes {
+			column[i] = dictionary[v]
+		}
+	}
+	return column, nil
+}
+
+func (e Encoder) encode(c format.CaptureCollection) ([]byte, error) {
+	collection, ok := c.(structure.Collection)
+	if !ok {
+		return nil, errors.New("collection is not of type struct")
+	}
+
+	for i := 0; i < collection.Length(); i++ {
+		capture, ok := collection.CaptureAt(i).(structure.Capture)
+		if !ok {
+			return nil, errors.New("capture is not of type struct")
+		}
+
+		if err := collection.Schema().Check(capture.Values()); err != nil {
+			return nil, fmt.Errorf("struct capture %d in collection %s: %w", i, collection.Name(), err)
+		}
+	}
+
+	out := bytes.Buffer{}
+	out.WriteByte(byte(e.technique))
+	writeSchema(&out, collection.Schema())
+	for i, field := range collection.Schema().Fields() {
+		e.encodeColumn(&out, field, collection.Column(i))
+	}
+
+	return out.Bytes(), nil
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	if e.technique < Raw64 || e.technique > BST16 {
+		return nil, nil, fmt.Errorf("unknown struct encoding technique: %d", int(e.technique))
+	}
+
+	allStreamData := make([][]byte, len(collections))
+	for i, collection := range collections {
+		s, err := e.encode(collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		allStreamData[i] = s
+	}
+
+	return nil, allStreamData, nil
+}
+
+func (Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	reader := rapbinary.NewErrReader(bytes.NewReader(streamData))
+
+	techniqueByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	technique := StorageTechnique(techniqueByte)
+	if technique > BST16 {
+		return nil, fmt.Errorf("unknown struct encoding technique: %d", int(techniqueByte))
+	}
+
+	schema, err := readSchema(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([][]interface{}, len(schema.Fields()))
+	for i, field := range schema.Fields() {
+		columns[i], err = decodeColumn(reader, technique, field, len(times))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if reader.Error() != nil {
+		return nil, reader.Error()
+	}
+
+	captures := make([]structure.Capture, len(times))
+	for captureIndex, time := range times {
+		values := make([]interface{}, len(columns))
+		for fieldIndex := range columns {
+			values[fieldIndex] = columns[fieldIndex][captureIndex]
+		}
+		captures[captureIndex] = structure.NewCapture(time, values)
+	}
+
+	return structure.NewCollection(name, schema, captures), nil
+}
@@ -0,0 +1,133 @@
+package structure_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	structureCollection "github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/encoding/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildSchema(t *testing.T) structureCollection.Schema {
+	schema, err := structureCollection.NewSchema([]structureCollection.Field{
+		{Name: "health", Type: structureCollection.Float},
+		{Name: "ammo", Type: structureCollection.Int},
+		{Name: "grounded", Type: structureCollection.Bool},
+		{Name: "velocity", Type: structureCollection.Vector3},
+		{Name: "zone", Type: structureCollection.String},
+		{Name: "weapon", Type: structureCollection.Enum, Options: []string{"sword", "bow", "staff"}},
+	})
+	assert.NoError(t, err)
+	return schema
+}
+
+func Test_Struct(t *testing.T) {
+	schema := buildSchema(t)
+	zones := []string{"spawn", "field", "castle"}
+
+	frames := make([]structureCollection.Capture, 1000)
+	frameTimes := make([]float64, len(frames))
+	for i := range frames {
+		frameTimes[i] = float64(i) / 30.0
+		frames[i] = structureCollection.NewCapture(frameTimes[i], []interface{}{
+			rand.Float64() * 100,
+			int64(rand.Intn(200) - 100),
+			rand.Intn(2) == 1,
+			vector.NewVector3(rand.Float64()*10, rand.Float64()*-10, rand.Float64()),
+			zones[rand.Intn(len(zones))],
+			rand.Intn(3),
+		})
+	}
+
+	tests := map[string]struct {
+		captures []structureCollection.Capture
+		times    []float64
+	}{
+		"nil frames":  {captures: nil},
+		"0-frames":    {captures: []structureCollection.Capture{}},
+		"1-frame":     {captures: frames[:1], times: frameTimes[:1]},
+		"2-frames":    {captures: frames[:2], times: frameTimes[:2]},
+		"1000-frames": {captures: frames, times: frameTimes},
+	}
+
+	techniques := []struct {
+		displayName string
+		technique   structure.StorageTechnique
+		tolerance   float64
+	}{
+		{displayName: "Raw64", technique: structure.Raw64, tolerance: 0},
+		{displayName: "Raw32", technique: structure.Raw32, tolerance: 0.00001},
+		{displayName: "BST16", technique: structure.BST16, tolerance: 0.002},
+	}
+
+	for name, tc := range tests {
+		for _, technique := range techniques {
+			t.Run(fmt.Sprintf("%s/%s", name, technique.displayName), func(t *testing.T) {
+				collectionIn := structureCollection.NewCollection("Player", schema, tc.captures)
+				encoder := structure.NewEncoder(technique.technique)
+				assert.Equal(t, "recolude.struct", encoder.Signature())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ========================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode("Player", header, collectionData[0], tc.times)
+
+				// ASSERT =====================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				if assert.NotNil(t, collectionOut) == false || assert.Equal(t, len(tc.captures), collectionOut.Length()) == false {
+					return
+				}
+
+				out := collectionOut.(structureCollection.Collection)
+				assert.Equal(t, schema, out.Schema())
+				for i, c := range out.Captures() {
+					capture := c.(structureCollection.Capture)
+					expected := tc.captures[i].Values()
+					assert.Equal(t, tc.times[i], capture.Time())
+					assert.InDelta(t, expected[0], capture.Value(0), technique.tolerance)
+					assert.Equal(t, expected[1], capture.Value(1))
+					assert.Equal(t, expected[2], capture.Value(2))
+					assert.InDelta(t, 0, expected[3].(vector.Vector3).Distance(capture.Value(3).(vector.Vector3)), technique.tolerance)
+					assert.Equal(t, expected[4], capture.Value(4))
+					if !assert.Equal(t, expected[5], capture.Value(5)) {
+						return
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_Struct_RejectsValuesNotMatchingSchema(t *testing.T) {
+	collection := structureCollection.NewCollection("Player", buildSchema(t), []structureCollection.Capture{
+		structureCollection.NewCapture(1, []interface{}{1.0}),
+	})
+
+	_, _, err := structure.NewEncoder(structure.BST16).Encode([]format.CaptureCollection{collection})
+
+	assert.EqualError(t, err, "struct capture 0 in collection Player: struct capture has 1 values but schema has 6 fields")
+}
+
+func Test_Struct_RejectsOtherCollections(t *testing.T) {
+	encoder := structure.NewEncoder(structure.BST16)
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "collection is not of type struct")
+}
+
+func Test_Struct_UnknownTechnique(t *testing.T) {
+	_, _, encodeErr := structure.NewEncoder(structure.StorageTechnique(9)).Encode(nil)
+	_, decodeErr := structure.NewEncoder(structure.BST16).Decode("Player", nil, []byte{9, 0}, nil)
+
+	assert.EqualError(t, encodeErr, "unknown struct encoding technique: 9")
+	assert.EqualError(t, decodeErr, "unknown struct encoding technique: 9")
+}
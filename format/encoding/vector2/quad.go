@@ -0,0 +1,216 @@
+package vector2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/collection/vector2"
+)
+
+type QuadCell int
+
+// ORDER MATTERS: topBit << 1 | rightBit
+const (
+	TopRight QuadCell = iota
+	TopLeft
+	BottomRight
+	BottomLeft
+)
+
+func Vec2ToQuadCells(v, min, max vector.Vector2, cells []QuadCell) {
+	center := min.Add(max).DivByConstant(2.0)
+	crossSection := max.Sub(min)
+	incrementX := crossSection.X() / 4.0
+	incrementY := crossSection.Y() / 4.0
+
+	for cellIndex := 0; cellIndex < len(cells); cellIndex++ {
+		topBit := 0
+		newY := center.Y() + incrementY
+		if v.Y() < center.Y() {
+			topBit = 1
+			newY = center.Y() - incrementY
+		}
+
+		rightBit := 0
+		newX := center.X() + incrementX
+		if v.X() < center.X() {
+			rightBit = 1
+			newX = center.X() - incrementX
+		}
+
+		cells[cellIndex] = QuadCell(topBit<<1 | rightBit)
+		center = vector.NewVector2(newX, newY)
+		incrementX /= 2.0
+		incrementY /= 2.0
+	}
+}
+
+func QuadCellsToVec2(min, max vector.Vector2, cells []QuadCell) vector.Vector2 {
+	center := min.Add(max).DivByConstant(2.0)
+	crossSection := max.Sub(min)
+	incrementX := crossSection.X() / 4.0
+	incrementY := crossSection.Y() / 4.0
+
+	for cellIndex := 0; cellIndex < len(cells); cellIndex++ {
+		newY := center.Y() - incrementY
+		if cells[cellIndex]&0b10 == 0 {
+			newY = center.Y() + incrementY
+		}
+
+		newX := center.X() - incrementX
+		if cells[cellIndex]&0b01 == 0 {
+			newX = center.X() + incrementX
+		}
+
+		center = vector.NewVector2(newX, newY)
+		incrementX /= 2.0
+		incrementY /= 2.0
+	}
+	return center
+}
+
+// quadCellsToBytes writes cells in groups of 8, where each group takes up two
+// bytes. The first byte contains the right bits of all cells in the group and
+// the second the top bits, which compresses better than packing cells next to
+// one another.
+func quadCellsToBytes(cells []QuadCell, buffer []byte) {
+	for group := 0; group < len(cells)/8; group++ {
+		right := byte(0)
+		top := byte(0)
+		for i := 0; i < 8; i++ {
+			cell := byte(cells[group*8+i])
+			right |= (cell & 0b1) << i
+			top |= ((cell & 0b10) >> 1) << i
+		}
+		buffer[group*2] = right
+		buffer[group*2+1] = top
+	}
+}
+
+func bytesToQuadCells(cells []QuadCell, buffer []byte) {
+	for group := 0; group < len(cells)/8; group++ {
+		right := buffer[group*2]
+		top := buffer[group*2+1]
+		for i := 0; i < 8; i++ {
+			cells[group*8+i] = QuadCell(((right >> i) & 0b1) | ((top>>i)&0b1)<<1)
+		}
+	}
+}
+
+func writeVec2(out *bytes.Buffer, v vector.Vector2) {
+	binary.Write(out, binary.LittleEndian, float32(v.X()))
+	binary.Write(out, binary.LittleEndian, float32(v.Y()))
+}
+
+func readVec2(in *bytes.Reader) (vector.Vector2, error) {
+	var x float32
+	var y float32
+	if err := binary.Read(in, binary.LittleEndian, &x); err != nil {
+		return vector.Vector2Zero(), err
+	}
+	if err := binary.Read(in, binary.LittleEndian, &y); err != nil {
+		return vector.Vector2Zero(), err
+	}
+	return vector.NewVector2(float64(x), float64(y)), nil
+}
+
+// encodeQuad stores the first value at 32 bit precision, followed by the
+// bounds of all deltas between consecutive values. Each delta is then stored
+// as a path through a quad tree of the depth provided.
+func encodeQuad(captures []vector2.Capture, depth int) ([]byte, error) {
+	collectionData := new(bytes.Buffer)
+
+	if len(captures) == 0 {
+		return collectionData.Bytes(), nil
+	}
+
+	if len(captures) == 1 {
+		writeVec2(collectionData, captures[0].Value())
+		return collectionData.Bytes(), nil
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := 1; i < len(captures); i++ {
+		distance := captures[i].Value().Sub(captures[i-1].Value())
+		minX = math.Min(minX, distance.X())
+		minY = math.Min(minY, distance.Y())
+		maxX = math.Max(maxX, distance.X())
+		maxY = math.Max(maxY, distance.Y())
+	}
+	min := vector.NewVector2(minX, minY)
+	max := vector.NewVector2(maxX, maxY)
+
+	writeVec2(collectionData, min)
+	writeVec2(collectionData, max)
+	writeVec2(collectionData, captures[0].Value())
+
+	quadBuffer := make([]QuadCell, depth)
+	quadByteBuffer := make([]byte, depth/4)
+
+	// Quantize starting value the same way the decoder will see it
+	quantizedValue := vector.NewVector2(
+		float64(float32(captures[0].Value().X())),
+		float64(float32(captures[0].Value().Y())),
+	)
+	for i := 1; i < len(captures); i++ {
+		dir := captures[i].Value().Sub(quantizedValue)
+		Vec2ToQuadCells(dir, min, max, quadBuffer)
+		quadCellsToBytes(quadBuffer, quadByteBuffer)
+		if _, err := collectionData.Write(quadByteBuffer); err != nil {
+			return nil, err
+		}
+
+		// Read back quantized value to fix drifting
+		quantizedValue = quantizedValue.Add(QuadCellsToVec2(min, max, quadBuffer))
+	}
+
+	return collectionData.Bytes(), nil
+}
+
+func decodeQuad(collectionData *bytes.Reader, times []float64, depth int) ([]vector2.Capture, error) {
+	if len(times) == 0 {
+		return make([]vector2.Capture, 0), nil
+	}
+
+	if len(times) == 1 {
+		v, err := readVec2(collectionData)
+		if err != nil {
+			return nil, err
+		}
+		return []vector2.Capture{vector2.NewCapture(times[0], v.X(), v.Y())}, nil
+	}
+
+	min, err := readVec2(collectionData)
+	if err != nil {
+		return nil, err
+	}
+
+	max, err := readVec2(collectionData)
+	if err != nil {
+		return nil, err
+	}
+
+	currentValue, err := readVec2(collectionData)
+	if err != nil {
+		return nil, err
+	}
+
+	captures := make([]vector2.Capture, len(times))
+	quadBuffer := make([]QuadCell, depth)
+	quadByteBuffer := make([]byte, depth/4)
+	for i := range times {
+		if i > 0 {
+			if _, err := collectionData.Read(quadByteBuffer); err != nil {
+				return nil, err
+			}
+			bytesToQuadCells(quadBuffer, quadByteBuffer)
+			currentValue = currentValue.Add(QuadCellsToVec2(min, max, quadBuffer))
+		}
+		captures[i] = vector2.NewCapture(times[i], currentValue.X(), currentValue.Y())
+	}
+
+	return captures, nil
+}
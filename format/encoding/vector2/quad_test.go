@@ -0,0 +1,50 @@
+package vector2_test
+
+import (
+	"testing"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/encoding/vector2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Vec2ToQuadCells(t *testing.T) {
+	start := vector.Vector2Zero()
+	end := vector.NewVector2(1, 1)
+
+	tests := map[string]struct {
+		x    float64
+		y    float64
+		cell vector2.QuadCell
+	}{
+		"top right":    {x: 0.75, y: 0.75, cell: vector2.TopRight},
+		"top left":     {x: 0.25, y: 0.75, cell: vector2.TopLeft},
+		"bottom right": {x: 0.75, y: 0.25, cell: vector2.BottomRight},
+		"bottom left":  {x: 0.25, y: 0.25, cell: vector2.BottomLeft},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cells := make([]vector2.QuadCell, 1)
+			vector2.Vec2ToQuadCells(vector.NewVector2(tc.x, tc.y), start, end, cells)
+
+			v := vector2.QuadCellsToVec2(start, end, cells)
+
+			assert.Equal(t, tc.cell, cells[0])
+			assert.InDelta(t, tc.x, v.X(), 0.001)
+			assert.InDelta(t, tc.y, v.Y(), 0.001)
+		})
+	}
+}
+
+func Test_Vec2ToQuadCells_Deep(t *testing.T) {
+	start := vector.NewVector2(-10, -3)
+	end := vector.NewVector2(10, 7)
+	cells := make([]vector2.QuadCell, 16)
+
+	vector2.Vec2ToQuadCells(vector.NewVector2(3.14159, -1.2345), start, end, cells)
+	v := vector2.QuadCellsToVec2(start, end, cells)
+
+	assert.InDelta(t, 3.14159, v.X(), 0.001)
+	assert.InDelta(t, -1.2345, v.Y(), 0.001)
+}
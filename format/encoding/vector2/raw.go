@@ -0,0 +1,65 @@
+package vector2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/recolude/rap/format/collection/vector2"
+)
+
+func encodeRaw64(captures []vector2.Capture) []byte {
+	streamData := new(bytes.Buffer)
+
+	buf := make([]byte, 8)
+	for _, capture := range captures {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(capture.Value().X()))
+		streamData.Write(buf)
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(capture.Value().Y()))
+		streamData.Write(buf)
+	}
+
+	return streamData.Bytes()
+}
+
+func decodeRaw64(streamData *bytes.Reader, times []float64) ([]vector2.Capture, error) {
+	captures := make([]vector2.Capture, len(times))
+	buf := make([]byte, 16)
+	for i := range times {
+		if _, err := streamData.Read(buf); err != nil {
+			return nil, err
+		}
+		x := math.Float64frombits(binary.LittleEndian.Uint64(buf))
+		y := math.Float64frombits(binary.LittleEndian.Uint64(buf[8:]))
+		captures[i] = vector2.NewCapture(times[i], x, y)
+	}
+	return captures, nil
+}
+
+func encodeRaw32(captures []vector2.Capture) []byte {
+	streamData := new(bytes.Buffer)
+
+	buf := make([]byte, 4)
+	for _, capture := range captures {
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(capture.Value().X())))
+		streamData.Write(buf)
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(capture.Value().Y())))
+		streamData.Write(buf)
+	}
+
+	return streamData.Bytes()
+}
+
+func decodeRaw32(streamData *bytes.Reader, times []float64) ([]vector2.Capture, error) {
+	captures := make([]vector2.Capture, len(times))
+	buf := make([]byte, 8)
+	for i := range times {
+		if _, err := streamData.Read(buf); err != nil {
+			return nil, err
+		}
+		x := math.Float32frombits(binary.LittleEndian.Uint32(buf))
+		y := math.Float32frombits(binary.LittleEndian.Uint32(buf[4:]))
+		captures[i] = vector2.NewCapture(times[i], float64(x), float64(y))
+	}
+	return captures, nil
+}
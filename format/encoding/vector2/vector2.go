@@ -0,0 +1,137 @@
+package vector2
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/vector2"
+)
+
+type StorageTechnique int
+
+const (
+	// Raw64 encodes all values at fullest precision, costing 128 bits per
+	// capture
+	Raw64 StorageTechnique = iota
+
+	// Raw32 encodes all values at 32bit precision, costing 64 bits per
+	// capture
+	Raw32
+
+	// Quad32 stores all values in a quad tree of depth 16, costing 32 bits
+	// per capture
+	Quad32
+
+	// Quad16 stores all values in a quad tree of depth 8, costing 16 bits per
+	// capture
+	Quad16
+)
+
+type Encoder struct {
+	technique StorageTechnique
+}
+
+func NewEncoder(technique StorageTechnique) Encoder {
+	return Encoder{technique: technique}
+}
+
+func (e Encoder) encode(collection format.CaptureCollection) ([]byte, error) {
+	streamData := new(bytes.Buffer)
+
+	castedCaptures := make([]vector2.Capture, collection.Length())
+	for i := range castedCaptures {
+		capture, ok := collection.CaptureAt(i).(vector2.Capture)
+		if !ok {
+			return nil, fmt.Errorf("capture is not of type vector2")
+		}
+		castedCaptures[i] = capture
+	}
+
+	streamData.WriteByte(byte(e.technique))
+
+	switch e.technique {
+	case Raw64:
+		streamData.Write(encodeRaw64(castedCaptures))
+
+	case Raw32:
+		streamData.Write(encodeRaw32(castedCaptures))
+
+	case Quad16:
+		d, err := encodeQuad(castedCaptures, 8)
+		if err != nil {
+			return nil, err
+		}
+		streamData.Write(d)
+
+	case Quad32:
+		d, err := encodeQuad(castedCaptures, 16)
+		if err != nil {
+			return nil, err
+		}
+		streamData.Write(d)
+
+	default:
+		return nil, fmt.Errorf("unknown vector2 encoding technique: %d", int(e.technique))
+	}
+
+	return streamData.Bytes(), nil
+}
+
+func (e Encoder) Encode(collections []format.CaptureCollection) ([]byte, [][]byte, error) {
+	allStreamData := make([][]byte, len(collections))
+
+	for i, collection := range collections {
+		s, err := e.encode(collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		allStreamData[i] = s
+	}
+
+	return nil, allStreamData, nil
+}
+
+func (e Encoder) Decode(name string, header []byte, streamData []byte, times []float64) (format.CaptureCollection, error) {
+	reader := bytes.NewReader(streamData)
+
+	typeByte, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var captures []vector2.Capture
+	switch StorageTechnique(typeByte) {
+	case Raw64:
+		captures, err = decodeRaw64(reader, times)
+
+	case Raw32:
+		captures, err = decodeRaw32(reader, times)
+
+	case Quad16:
+		captures, err = decodeQuad(reader, times, 8)
+
+	case Quad32:
+		captures, err = decodeQuad(reader, times, 16)
+
+	default:
+		return nil, fmt.Errorf("unknown vector2 encoding technique: %d", int(typeByte))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return vector2.NewCollection(name, captures), nil
+}
+
+func (Encoder) Accepts(collection format.CaptureCollection) bool {
+	return collection.Signature() == "recolude.vector2"
+}
+
+func (Encoder) Signature() string {
+	return "recolude.vector2"
+}
+
+func (Encoder) Version() uint {
+	return 0
+}
@@ -0,0 +1,135 @@
+package vector2_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	vector2Collection "github.com/recolude/rap/format/collection/vector2"
+	"github.com/recolude/rap/format/encoding/vector2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Vector2(t *testing.T) {
+	continuousCaptures := make([]vector2Collection.Capture, 1000)
+	continuousTimes := make([]float64, len(continuousCaptures))
+	curTime := -1000.0
+	curX, curY := 0.0, 0.0
+	for i := range continuousCaptures {
+		continuousCaptures[i] = vector2Collection.NewCapture(curTime, curX, curY)
+		continuousTimes[i] = curTime
+		curX += rand.Float64() * 10
+		curY -= rand.Float64() * 10
+		curTime += rand.Float64() * 10.0
+	}
+
+	tests := map[string]struct {
+		captures []vector2Collection.Capture
+		times    []float64
+	}{
+		"nil captures": {captures: nil},
+		"0-captures":   {captures: []vector2Collection.Capture{}},
+		"1-capture": {
+			captures: []vector2Collection.Capture{vector2Collection.NewCapture(1.2, 1, 2)},
+			times:    []float64{1.2},
+		},
+		"2-captures": {
+			captures: []vector2Collection.Capture{
+				vector2Collection.NewCapture(1.2, 1, 1),
+				vector2Collection.NewCapture(1.3, 4, 5),
+			},
+			times: []float64{1.2, 1.3},
+		},
+		"3-captures": {
+			captures: []vector2Collection.Capture{
+				vector2Collection.NewCapture(1.2, 1, 1),
+				vector2Collection.NewCapture(1.3, 4, 5),
+				vector2Collection.NewCapture(1.4, 4.1, 5.7),
+			},
+			times: []float64{1.2, 1.3, 1.4},
+		},
+		"1000-continuous-captures": {captures: continuousCaptures, times: continuousTimes},
+	}
+
+	techniques := []struct {
+		displayName string
+		technique   vector2.StorageTechnique
+		tolerance   float64
+	}{
+		{displayName: "Raw64", technique: vector2.Raw64, tolerance: 0},
+		{displayName: "Raw32", technique: vector2.Raw32, tolerance: 0.0003},
+		{displayName: "Quad16", technique: vector2.Quad16, tolerance: 0.04},
+		{displayName: "Quad32", technique: vector2.Quad32, tolerance: 0.0004},
+	}
+
+	for name, tc := range tests {
+		for _, technique := range techniques {
+			t.Run(fmt.Sprintf("%s/%s", name, technique.displayName), func(t *testing.T) {
+				collectionIn := vector2Collection.NewCollection(technique.displayName, tc.captures)
+				encoder := vector2.NewEncoder(technique.technique)
+				assert.Equal(t, "recolude.vector2", encoder.Signature())
+				assert.True(t, encoder.Accepts(collectionIn))
+
+				// ACT ============================================================
+				header, collectionData, encodeErr := encoder.Encode([]format.CaptureCollection{collectionIn})
+				collectionOut, decodeErr := encoder.Decode(technique.displayName, header, collectionData[0], tc.times)
+
+				// ASSERT =========================================================
+				assert.NoError(t, encodeErr)
+				assert.NoError(t, decodeErr)
+				assert.Len(t, header, 0)
+				if assert.NotNil(t, collectionOut) && assert.Len(t, collectionOut.Captures(), len(tc.captures)) {
+					assert.Equal(t, technique.displayName, collectionOut.Name())
+					for i, c := range collectionOut.Captures() {
+						capture, ok := c.(vector2Collection.Capture)
+						if assert.True(t, ok) == false {
+							break
+						}
+						assert.Equal(t, tc.captures[i].Time(), capture.Time())
+						if assert.InDelta(t, tc.captures[i].Value().X(), capture.Value().X(), technique.tolerance) == false {
+							break
+						}
+						if assert.InDelta(t, tc.captures[i].Value().Y(), capture.Value().Y(), technique.tolerance) == false {
+							break
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_QuadSmallerThanRaw(t *testing.T) {
+	captures := make([]vector2Collection.Capture, 100)
+	for i := range captures {
+		captures[i] = vector2Collection.NewCapture(float64(i), float64(i), float64(i)*2)
+	}
+	collectionIn := []format.CaptureCollection{vector2Collection.NewCollection("cursor", captures)}
+
+	_, raw, rawErr := vector2.NewEncoder(vector2.Raw32).Encode(collectionIn)
+	_, quad32, quad32Err := vector2.NewEncoder(vector2.Quad32).Encode(collectionIn)
+	_, quad16, quad16Err := vector2.NewEncoder(vector2.Quad16).Encode(collectionIn)
+
+	assert.NoError(t, rawErr)
+	assert.NoError(t, quad32Err)
+	assert.NoError(t, quad16Err)
+	assert.Less(t, len(quad32[0]), len(raw[0]))
+	assert.Less(t, len(quad16[0]), len(quad32[0]))
+}
+
+func Test_Vector2RejectsOtherCaptures(t *testing.T) {
+	encoder := vector2.NewEncoder(vector2.Raw32)
+	collection := position.NewCollection("pos", []position.Capture{position.NewCapture(1, 1, 1, 1)})
+
+	assert.False(t, encoder.Accepts(collection))
+
+	_, _, err := encoder.Encode([]format.CaptureCollection{collection})
+	assert.EqualError(t, err, "capture is not of type vector2")
+}
+
+func Test_Vector2UnknownTechnique(t *testing.T) {
+	_, err := vector2.NewEncoder(vector2.Raw32).Decode("", nil, []byte{200}, nil)
+	assert.EqualError(t, err, "unknown vector2 encoding technique: 200")
+}
@@ -0,0 +1,32 @@
+package io
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/recolude/rap/format"
+)
+
+// Fingerprint computes a content hash of the recording by writing it with the
+// lossless encoders in canonical mode. Identical recordings always share a
+// fingerprint, and any change to a time or value changes it, making it
+// suitable for deduplicating uploads and caching anything derived from a
+// recording.
+//
+// Compression is skipped while hashing so the fingerprint doesn't change with
+// the compression library's output.
+//
+// Fingerprint lives here rather than in package format because hashing
+// requires the writer and the default encoders, and both import format.
+func Fingerprint(recording format.Recording) (string, error) {
+	hash := sha256.New()
+
+	writer := NewLosslessWriter(hash).Canonical()
+	writer.compress = false
+
+	if _, err := writer.Write(recording); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
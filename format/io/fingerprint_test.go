@@ -0,0 +1,140 @@
+package io_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildMetadataHeavyBlock(prefix string, value int) metadata.Block {
+	mapping := make(map[string]metadata.Property)
+	for i := 0; i < 20; i++ {
+		mapping[fmt.Sprintf("%s key %d", prefix, i)] = metadata.NewIntProperty(value + i)
+	}
+	mapping[prefix+" nested"] = metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+		"a": metadata.NewStringProperty("a"),
+		"b": metadata.NewStringProperty("b"),
+		"c": metadata.NewStringProperty("c"),
+		"d": metadata.NewStringProperty("d"),
+	}))
+	return metadata.NewBlock(mapping)
+}
+
+func buildMetadataHeavyRecording(value int) format.Recording {
+	events := event.NewCollection("events", []event.Capture{
+		event.NewCapture(1, "hit", buildMetadataHeavyBlock("hit", value)),
+		event.NewCapture(2, "miss", buildMetadataHeavyBlock("miss", value)),
+	})
+
+	spans := span.NewCollection("spans", []span.Capture{
+		span.NewCapture(1, 2, "round", buildMetadataHeavyBlock("round", value)),
+	})
+
+	positions := position.NewCollection("positions", []position.Capture{
+		position.NewCapture(1, 1, 2, 3),
+		position.NewCapture(2, 4, 5, 6),
+	}).WithMetadata(buildMetadataHeavyBlock("units", value))
+
+	child := format.NewRecording(
+		"child",
+		"child",
+		nil,
+		nil,
+		buildMetadataHeavyBlock("child", value),
+		[]format.Binary{io.NewBinary("bin", []byte{1, 2, 3}, buildMetadataHeavyBlock("bin", value))},
+		[]format.BinaryReference{io.NewBinaryReference("ref", "uri", 3, buildMetadataHeavyBlock("ref", value))},
+	)
+
+	return format.NewRecording(
+		"parent",
+		"parent",
+		[]format.CaptureCollection{events, spans, positions},
+		[]format.Recording{child},
+		buildMetadataHeavyBlock("parent", value),
+		nil,
+		nil,
+	)
+}
+
+func Test_CanonicalWriterIsDeterministic(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := buildMetadataHeavyRecording(0)
+
+	first := bytes.Buffer{}
+	_, err := io.NewRecoludeWriter(&first).Canonical().Write(rec)
+	assert.NoError(t, err)
+
+	// ACT/ASSERT =============================================================
+	for i := 0; i < 20; i++ {
+		out := bytes.Buffer{}
+		_, err := io.NewRecoludeWriter(&out).Canonical().Write(buildMetadataHeavyRecording(0))
+		assert.NoError(t, err)
+		if !assert.Equal(t, first.Bytes(), out.Bytes()) {
+			return
+		}
+	}
+
+	recBack, _, err := io.Load(&first)
+	assert.NoError(t, err)
+	assertRecordingsMatch(t, rec, recBack, 0.01)
+}
+
+func Test_Fingerprint(t *testing.T) {
+	// ARRANGE ================================================================
+	original := buildMetadataHeavyRecording(0)
+	identical := buildMetadataHeavyRecording(0)
+	changed := buildMetadataHeavyRecording(1)
+
+	// ACT ====================================================================
+	originalFingerprint, originalErr := io.Fingerprint(original)
+	identicalFingerprint, identicalErr := io.Fingerprint(identical)
+	changedFingerprint, changedErr := io.Fingerprint(changed)
+
+	// ASSERT =================================================================
+	assert.NoError(t, originalErr)
+	assert.NoError(t, identicalErr)
+	assert.NoError(t, changedErr)
+	assert.Len(t, originalFingerprint, 64)
+	assert.Equal(t, originalFingerprint, identicalFingerprint)
+	assert.NotEqual(t, originalFingerprint, changedFingerprint)
+}
+
+func Test_Fingerprint_SubQuantumChanges(t *testing.T) {
+	// ARRANGE ================================================================
+	build := func(time, x float64) format.Recording {
+		return format.NewRecording(
+			"id",
+			"name",
+			[]format.CaptureCollection{
+				position.NewCollection("Position", []position.Capture{
+					position.NewCapture(0, 1, 2, 3),
+					position.NewCapture(time, x, 2, 3),
+				}),
+			},
+			nil,
+			metadata.EmptyBlock(),
+			nil,
+			nil,
+		)
+	}
+
+	// ACT ====================================================================
+	original, originalErr := io.Fingerprint(build(5, 1))
+	shifted, shiftedErr := io.Fingerprint(build(5.0000001, 1))
+	moved, movedErr := io.Fingerprint(build(5, 1.0000001))
+
+	// ASSERT =================================================================
+	assert.NoError(t, originalErr)
+	assert.NoError(t, shiftedErr)
+	assert.NoError(t, movedErr)
+	assert.NotEqual(t, original, shifted)
+	assert.NotEqual(t, original, moved)
+}
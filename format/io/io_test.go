@@ -115,6 +115,14 @@ func assertRecordingsMatch(t *testing.T, recExpected, recActual format.Recording
 	for streamIndex, correctStream := range recExpected.CaptureCollections() {
 		assert.Equal(t, correctStream.Name(), recActual.CaptureCollections()[streamIndex].Name())
 
+		if assert.Equal(t, len(correctStream.Metadata().Mapping()), len(recActual.CaptureCollections()[streamIndex].Metadata().Mapping()), "mismatch collection metadata lengths") == false {
+			return false
+		}
+
+		for key, element := range correctStream.Metadata().Mapping() {
+			assert.Equal(t, element, recActual.CaptureCollections()[streamIndex].Metadata().Mapping()[key])
+		}
+
 		if assert.Len(t, recActual.CaptureCollections()[streamIndex].Captures(), len(correctStream.Captures())) {
 			for i, correctCapture := range correctStream.Captures() {
 				assert.InDelta(t, correctCapture.Time(), recActual.CaptureCollections()[streamIndex].Captures()[i].Time(), timeDelta)
@@ -625,8 +633,8 @@ func Test_EncodersWithHeaders(t *testing.T) {
 	encoders := []encoding.Encoder{
 		positionEncoding.NewEncoder(positionEncoding.Raw64),
 		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
-		eventEncoding.NewEncoder(),
-		enumEncoding.NewEncoder(),
+		eventEncoding.NewEncoder(eventEncoding.Columnar),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
 	}
 
 	w := io.NewWriter(encoders, true, fileData, io.Raw64)
@@ -691,8 +699,8 @@ func Test_HandlesMultipleEncoders(t *testing.T) {
 	encoders := []encoding.Encoder{
 		positionEncoding.NewEncoder(positionEncoding.Raw64),
 		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
-		eventEncoding.NewEncoder(),
-		enumEncoding.NewEncoder(),
+		eventEncoding.NewEncoder(eventEncoding.Columnar),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
 	}
 
 	w := io.NewWriter(encoders, true, fileData, io.Raw64)
@@ -847,8 +855,8 @@ func Test_HandlesManyChildren(t *testing.T) {
 	encoders := []encoding.Encoder{
 		positionEncoding.NewEncoder(positionEncoding.Raw64),
 		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
-		eventEncoding.NewEncoder(),
-		enumEncoding.NewEncoder(),
+		eventEncoding.NewEncoder(eventEncoding.Columnar),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
 	}
 
 	w := io.NewWriter(encoders, true, fileData, io.Raw64)
@@ -956,8 +964,8 @@ func Test_Uprade(t *testing.T) {
 	encoders := []encoding.Encoder{
 		positionEncoding.NewEncoder(positionEncoding.Raw64),
 		eulerEncoding.NewEncoder(eulerEncoding.Raw64),
-		eventEncoding.NewEncoder(),
-		enumEncoding.NewEncoder(),
+		eventEncoding.NewEncoder(eventEncoding.Columnar),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
 	}
 	fileData := new(bytes.Buffer)
 
@@ -1139,3 +1147,148 @@ func Test_OptionallyCompressesRecording(t *testing.T) {
 	assertRecordingsMatch(t, recIn, recOut, 0)
 	assertRecordingsMatch(t, recIn, recOut2, 0)
 }
+
+func Test_CollectionMetadata(t *testing.T) {
+	// ARRANGE ================================================================
+	fileData := new(bytes.Buffer)
+
+	encoders := []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Raw64),
+		enumEncoding.NewEncoder(enumEncoding.RunLength),
+	}
+
+	w := io.NewWriter(encoders, true, fileData, io.Raw64)
+	r := io.NewReader(encoders, fileData)
+
+	recIn := format.NewRecording(
+		"",
+		"Test Collection Metadata",
+		[]format.CaptureCollection{
+			position.NewCollection("Position", []position.Capture{
+				position.NewCapture(1, 1, 2, 3),
+			}).WithMetadata(metadata.NewBlock(map[string]metadata.Property{
+				"units":       metadata.NewStringProperty("meters"),
+				"sample rate": metadata.NewIntProperty(90),
+			})),
+			enum.NewCollection("State", []string{"a", "b"}, []enum.Capture{
+				enum.NewCapture(1, 1),
+			}),
+		},
+		[]format.Recording{
+			format.NewRecording("", "Child", []format.CaptureCollection{
+				position.NewCollection("Child Position", []position.Capture{
+					position.NewCapture(1, 1, 2, 3),
+				}).WithMetadata(metadata.NewBlock(map[string]metadata.Property{
+					"units": metadata.NewStringProperty("centimeters"),
+				})),
+			}, nil, metadata.EmptyBlock(), nil, nil),
+		},
+		metadata.NewBlock(map[string]metadata.Property{
+			"units": metadata.NewStringProperty("feet"),
+		}),
+		nil,
+		nil,
+	)
+
+	// ACT ====================================================================
+	n, errWrite := w.Write(recIn)
+	recOut, nOut, errRead := r.Read()
+
+	// ASSERT =================================================================
+	assert.NoError(t, errWrite)
+	assert.NoError(t, errRead)
+	assert.Equal(t, n, nOut)
+	assertRecordingsMatch(t, recIn, recOut, 0)
+	if assert.NotNil(t, recOut) {
+		assert.Equal(t, metadata.NewStringProperty("meters"), recOut.CaptureCollections()[0].Metadata().Mapping()["units"])
+		assert.Len(t, recOut.CaptureCollections()[1].Metadata().Mapping(), 0)
+	}
+}
+
+func Test_ReadsVersion2CollectionsWithEmptyMetadata(t *testing.T) {
+	// ARRANGE ================================================================
+	fileData := new(bytes.Buffer)
+	encoders := []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Raw64),
+	}
+
+	_, err := io.NewWriter(encoders, false, fileData, io.Raw64).Write(format.NewRecording(
+		"",
+		"Old Recording",
+		[]format.CaptureCollection{
+			position.NewCollection("Version2Stream", []position.Capture{
+				position.NewCapture(1, 1, 2, 3),
+			}),
+		},
+		nil,
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	))
+	assert.NoError(t, err)
+
+	// Version 2 files are identical, minus the empty metadata block following
+	// each stream name.
+	fileBytes := fileData.Bytes()
+	streamName := []byte("Version2Stream")
+	nameEnd := bytes.Index(fileBytes, streamName) + len(streamName)
+	v2Bytes := append([]byte{2}, fileBytes[1:nameEnd]...)
+	v2Bytes = append(v2Bytes, fileBytes[nameEnd+1:]...)
+
+	// ACT ====================================================================
+	recOut, bytesRead, errRead := io.NewReader(encoders, bytes.NewReader(v2Bytes)).Read()
+
+	// ASSERT =================================================================
+	assert.NoError(t, errRead)
+	assert.Equal(t, len(v2Bytes), bytesRead)
+	if assert.NotNil(t, recOut) && assert.Len(t, recOut.CaptureCollections(), 1) {
+		assert.Equal(t, "Version2Stream", recOut.CaptureCollections()[0].Name())
+		assert.NotNil(t, recOut.CaptureCollections()[0].Metadata().Mapping())
+		assert.Len(t, recOut.CaptureCollections()[0].Metadata().Mapping(), 0)
+		assert.Len(t, recOut.CaptureCollections()[0].Captures(), 1)
+	}
+}
+
+func Test_ErrorsOnCorruptCollectionMetadata(t *testing.T) {
+	// ARRANGE ================================================================
+	fileData := new(bytes.Buffer)
+	encoders := []encoding.Encoder{
+		positionEncoding.NewEncoder(positionEncoding.Raw64),
+	}
+
+	_, err := io.NewWriter(encoders, false, fileData, io.Raw64).Write(format.NewRecording(
+		"",
+		"Corrupt Recording",
+		[]format.CaptureCollection{
+			position.NewCollection("CorruptStream", []position.Capture{
+				position.NewCapture(1, 1, 2, 3),
+			}).WithMetadata(metadata.NewBlock(map[string]metadata.Property{
+				"units": metadata.NewStringProperty("meters"),
+			})),
+		},
+		nil,
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	))
+	assert.NoError(t, err)
+
+	// The stream name is followed by the number of metadata keys, the index
+	// of the key, then the code of its property.
+	streamName := []byte("CorruptStream")
+	nameEnd := bytes.Index(fileData.Bytes(), streamName) + len(streamName)
+
+	badCode := append([]byte{}, fileData.Bytes()...)
+	badCode[nameEnd+2] = 250
+
+	badKey := append([]byte{}, fileData.Bytes()...)
+	badKey[nameEnd+1] = 100
+
+	// ACT ====================================================================
+	_, _, badCodeErr := io.NewReader(encoders, bytes.NewReader(badCode)).Read()
+	_, _, badKeyErr := io.NewReader(encoders, bytes.NewReader(badKey)).Read()
+
+	// ASSERT =================================================================
+	assert.EqualError(t, badCodeErr, "unrecognized property type code: 250")
+	assert.EqualError(t, badKeyErr, "metadata key index 100 out of range of header")
+}
@@ -5,10 +5,19 @@ import (
 
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/encoding"
+	"github.com/recolude/rap/format/encoding/boolean"
 	"github.com/recolude/rap/format/encoding/enum"
 	"github.com/recolude/rap/format/encoding/euler"
 	"github.com/recolude/rap/format/encoding/event"
+	"github.com/recolude/rap/format/encoding/float"
+	"github.com/recolude/rap/format/encoding/floatvec"
+	"github.com/recolude/rap/format/encoding/gaze"
+	"github.com/recolude/rap/format/encoding/integer"
 	"github.com/recolude/rap/format/encoding/position"
+	"github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/recolude/rap/format/encoding/span"
+	"github.com/recolude/rap/format/encoding/structure"
+	"github.com/recolude/rap/format/encoding/vector2"
 )
 
 func GetRecoringVersion(file io.Reader) (int, int, error) {
@@ -24,9 +33,18 @@ func GetRecoringVersion(file io.Reader) (int, int, error) {
 
 func Load(in io.Reader) (format.Recording, int, error) {
 	return NewReader([]encoding.Encoder{
-		event.NewEncoder(),
+		event.NewEncoder(event.Columnar),
 		position.NewEncoder(position.Oct48),
 		euler.NewEncoder(euler.Raw32),
-		enum.NewEncoder(),
+		enum.NewEncoder(enum.RunLength),
+		float.NewEncoder(float.Raw32),
+		integer.NewEncoder(integer.ZigZagDelta),
+		vector2.NewEncoder(vector2.Quad32),
+		boolean.NewEncoder(),
+		span.NewEncoder(),
+		skeleton.NewEncoder(skeleton.Quantized16),
+		gaze.NewEncoder(gaze.Compact),
+		floatvec.NewEncoder(floatvec.BST16),
+		structure.NewEncoder(structure.Raw32),
 	}, in).Read()
 }
@@ -36,7 +36,7 @@ func Test_Load_ErrorsOnEmptyBuffer(t *testing.T) {
 func Test_Load_ErrorsOnUnrecognizedFileVersion(t *testing.T) {
 	// ARRANGE ================================================================
 	buf := bytes.Buffer{}
-	buf.Write([]byte{3})
+	buf.Write([]byte{4})
 
 	// ACT ====================================================================
 	rec, bytesRead, err := rapio.Load(&buf)
@@ -44,7 +44,7 @@ func Test_Load_ErrorsOnUnrecognizedFileVersion(t *testing.T) {
 	// ASSERT =================================================================
 	assert.Nil(t, rec)
 	assert.Equal(t, 1, bytesRead)
-	assert.EqualError(t, err, "Unrecognized file version: 3")
+	assert.EqualError(t, err, "Unrecognized file version: 4")
 }
 
 func TestLoad(t *testing.T) {
@@ -78,6 +78,9 @@ func readRecordingMetadataBlock(in io.Reader, metadataKeys []string) (metadata.B
 	}
 
 	for _, key := range keyIndecies {
+		if key >= uint(len(metadataKeys)) {
+			return metadata.EmptyBlock(), fmt.Errorf("metadata key index %d out of range of header", key)
+		}
 		propMapping[metadataKeys[key]], err = metadata.ReadProperty(in)
 		if err != nil {
 			return metadata.EmptyBlock(), err
@@ -87,7 +90,7 @@ func readRecordingMetadataBlock(in io.Reader, metadataKeys []string) (metadata.B
 	return metadata.NewBlock(propMapping), nil
 }
 
-func recursiveBuidRecordings(inStream io.Reader, metadataKeys []string, encoders []encoding.Encoder, headers [][]byte) (format.Recording, int, error) {
+func recursiveBuidRecordings(inStream io.Reader, version int, metadataKeys []string, encoders []encoding.Encoder, headers [][]byte) (format.Recording, int, error) {
 	// in := bytes.NewReader(recordingData)
 	er := binary.NewErrReader(inStream)
 
@@ -113,10 +116,22 @@ func recursiveBuidRecordings(inStream io.Reader, metadataKeys []string, encoders
 
 		streamName, _, _ := binary.ReadString(er)
 
+		// Version 2 files predate collection metadata
+		streamMetadata := metadata.EmptyBlock()
+		if version > 2 {
+			streamMetadata, err = readRecordingMetadataBlock(er, metadataKeys)
+			if err != nil {
+				return nil, er.TotalRead(), err
+			}
+		}
+
 		times, _ := decodeTime(er)
 		captureBody, _, _ := binary.ReadBytesArray(er)
-		stream, _ := encoders[encoderIndex].Decode(streamName, headers[encoderIndex], captureBody, times)
-		allStreams[i] = stream
+		stream, err := encoders[encoderIndex].Decode(streamName, headers[encoderIndex], captureBody, times)
+		if err != nil {
+			return nil, er.TotalRead(), err
+		}
+		allStreams[i] = stream.WithMetadata(streamMetadata)
 	}
 
 	// read binary references
@@ -155,7 +170,7 @@ func recursiveBuidRecordings(inStream io.Reader, metadataKeys []string, encoders
 
 	allChildRecordings := make([]format.Recording, numRecordings)
 	for i := 0; i < int(numRecordings); i++ {
-		childRec, _, err := recursiveBuidRecordings(er, metadataKeys, encoders, headers)
+		childRec, _, err := recursiveBuidRecordings(er, version, metadataKeys, encoders, headers)
 		if err != nil {
 			return nil, er.TotalRead(), err
 		}
@@ -184,7 +199,7 @@ func (r Reader) Read() (format.Recording, int, error) {
 		return rec, read + totalBytesRead, err
 	}
 
-	if version != 2 {
+	if version != 2 && version != 3 {
 		return nil, totalBytesRead, fmt.Errorf("Unrecognized file version: %d", version)
 	}
 
@@ -225,7 +240,7 @@ func (r Reader) Read() (format.Recording, int, error) {
 	}
 
 	// Read off recordings
-	rec, bytesRead, err := recursiveBuidRecordings(readcloser, metdataKeys, encodersToUse, encoderHeaders)
+	rec, bytesRead, err := recursiveBuidRecordings(readcloser, version, metdataKeys, encodersToUse, encoderHeaders)
 	totalBytesRead += bytesRead
 	if err != nil {
 		return nil, totalBytesRead, err
@@ -7,13 +7,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/recolude/rap/format"
 	"github.com/recolude/rap/format/encoding"
+	"github.com/recolude/rap/format/encoding/boolean"
 	"github.com/recolude/rap/format/encoding/enum"
 	"github.com/recolude/rap/format/encoding/euler"
 	"github.com/recolude/rap/format/encoding/event"
+	"github.com/recolude/rap/format/encoding/float"
+	"github.com/recolude/rap/format/encoding/floatvec"
+	"github.com/recolude/rap/format/encoding/gaze"
+	"github.com/recolude/rap/format/encoding/integer"
 	"github.com/recolude/rap/format/encoding/position"
+	"github.com/recolude/rap/format/encoding/skeleton"
+	"github.com/recolude/rap/format/encoding/span"
+	"github.com/recolude/rap/format/encoding/structure"
+	"github.com/recolude/rap/format/encoding/vector2"
 	"github.com/recolude/rap/format/metadata"
 	rapbinary "github.com/recolude/rap/internal/io/binary"
 )
@@ -54,6 +64,7 @@ type Writer struct {
 	encoders             []encoding.Encoder
 	timeStorageTechnique TimeStorageTechnique
 	compress             bool
+	canonical            bool
 	out                  io.Writer
 }
 
@@ -62,10 +73,19 @@ type Writer struct {
 func NewRecoludeWriter(out io.Writer) Writer {
 	return Writer{
 		encoders: []encoding.Encoder{
-			event.NewEncoder(),
+			event.NewEncoder(event.Columnar),
 			position.NewEncoder(position.Oct48),
 			euler.NewEncoder(euler.Raw32),
-			enum.NewEncoder(),
+			enum.NewEncoder(enum.RunLength),
+			float.NewEncoder(float.Raw32),
+			integer.NewEncoder(integer.ZigZagDelta),
+			vector2.NewEncoder(vector2.Quad32),
+			boolean.NewEncoder(),
+			span.NewEncoder(),
+			skeleton.NewEncoder(skeleton.Quantized16),
+			gaze.NewEncoder(gaze.Compact),
+			floatvec.NewEncoder(floatvec.BST16),
+			structure.NewEncoder(structure.Raw32),
 		},
 		compress:             true,
 		timeStorageTechnique: BST16,
@@ -73,6 +93,32 @@ func NewRecoludeWriter(out io.Writer) Writer {
 	}
 }
 
+// NewLosslessWriter builds a new recording writer whose encoders and time
+// storage keep every value at the fullest precision they offer, for when a
+// recording must be written back out without degrading it.
+func NewLosslessWriter(out io.Writer) Writer {
+	return Writer{
+		encoders: []encoding.Encoder{
+			event.NewEncoder(event.Columnar),
+			position.NewEncoder(position.Raw64),
+			euler.NewEncoder(euler.Raw64),
+			enum.NewEncoder(enum.Raw),
+			float.NewEncoder(float.Raw64),
+			integer.NewEncoder(integer.Raw),
+			vector2.NewEncoder(vector2.Raw64),
+			boolean.NewEncoder(),
+			span.NewEncoder(),
+			skeleton.NewEncoder(skeleton.Raw32),
+			gaze.NewEncoder(gaze.Raw32),
+			floatvec.NewEncoder(floatvec.Raw64),
+			structure.NewEncoder(structure.Raw64),
+		},
+		compress:             true,
+		timeStorageTechnique: Raw64,
+		out:                  out,
+	}
+}
+
 // NewWriter builds a new writer using the encoders provided.
 func NewWriter(encoders []encoding.Encoder, compress bool, out io.Writer, timeStorageTechnique TimeStorageTechnique) Writer {
 	return Writer{
@@ -83,12 +129,21 @@ func NewWriter(encoders []encoding.Encoder, compress bool, out io.Writer, timeSt
 	}
 }
 
+// Canonical returns a copy of the writer that sorts all metadata keys before
+// writing them, so that identical recordings always produce byte-identical
+// files.
+func (w Writer) Canonical() Writer {
+	w.canonical = true
+	return w
+}
+
 func calcNumStreams(recording format.Recording) int {
 	total := 0
-	for _, rec := range recording.Recordings() {
-		total += calcNumStreams(rec)
-	}
-	return len(recording.CaptureCollections()) + total
+	format.Walk(recording, func(rec format.Recording, path string, depth int) error {
+		total += len(rec.CaptureCollections())
+		return nil
+	})
+	return total
 }
 
 // accumulateMetdataKeys builds out a mapping of metadata keys to some unique
@@ -103,6 +158,15 @@ func accumulateMetdataKeys(recording format.Recording, keyMappingToIndex map[str
 		}
 	}
 
+	for _, collection := range recording.CaptureCollections() {
+		for key := range collection.Metadata().Mapping() {
+			if _, ok := keyMappingToIndex[key]; !ok {
+				keyMappingToIndex[key] = keyCount
+				keyCount++
+			}
+		}
+	}
+
 	for _, ref := range recording.BinaryReferences() {
 		for key := range ref.Metadata().Mapping() {
 			if _, ok := keyMappingToIndex[key]; !ok {
@@ -184,16 +248,23 @@ func (w Writer) evaluateCollections(recording format.Recording, offset int) ([]e
 	return mappings, curOffset, nil
 }
 
-func writeMetadata(out io.Writer, keyMappingToIndex map[string]int, block metadata.Block) (int, error) {
-	metadataIndices := make([]uint, len(block.Mapping()))
-
+func writeMetadata(out io.Writer, keyMappingToIndex map[string]int, block metadata.Block, canonical bool) (int, error) {
+	metadataIndices := make([]uint, 0, len(block.Mapping()))
 	metadataValuesBuffer := bytes.Buffer{}
-	i := 0
-	for key, val := range block.Mapping() {
-		metadataIndices[i] = uint(keyMappingToIndex[key])
 
+	writeEntry := func(key string, val metadata.Property) {
+		metadataIndices = append(metadataIndices, uint(keyMappingToIndex[key]))
 		metadata.WriteProprty(&metadataValuesBuffer, val)
-		i++
+	}
+
+	if canonical {
+		for _, key := range block.Keys() {
+			writeEntry(key, block.Mapping()[key])
+		}
+	} else {
+		for key, val := range block.Mapping() {
+			writeEntry(key, val)
+		}
 	}
 
 	totalBytes := 0
@@ -241,7 +312,7 @@ func writeEncoders(out io.Writer, encoders []encoderCollectionMapping) (int, err
 	return totalWritten, err
 }
 
-func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappingToIndex map[string]int, encodingBlocks [][]byte, streamIndexToEncoderUsedIndex []int, offset int, tech TimeStorageTechnique) (int, int, error) {
+func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappingToIndex map[string]int, encodingBlocks [][]byte, streamIndexToEncoderUsedIndex []int, offset int, tech TimeStorageTechnique, canonical bool) (int, int, error) {
 	ew := &errWriter{Writer: out}
 
 	// Write id
@@ -251,7 +322,7 @@ func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappi
 	ew.Write(rapbinary.StringToBytes(recording.Name()))
 
 	// Write metadata
-	writeMetadata(ew, keyMappingToIndex, recording.Metadata())
+	writeMetadata(ew, keyMappingToIndex, recording.Metadata(), canonical)
 
 	// Write number of streams
 	numStreams := make([]byte, binary.MaxVarintLen64)
@@ -266,6 +337,7 @@ func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappi
 		ew.Write(encoderIndex[:read])
 
 		ew.Write(rapbinary.StringToBytes(recording.CaptureCollections()[streamIndex].Name()))
+		writeMetadata(ew, keyMappingToIndex, recording.CaptureCollections()[streamIndex].Metadata(), canonical)
 		encodeTime(tech, ew, recording.CaptureCollections()[streamIndex].Captures())
 
 		// Write stream data
@@ -286,7 +358,7 @@ func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappi
 		read = binary.PutUvarint(refSize, ref.Size())
 		ew.Write(refSize[:read])
 
-		writeMetadata(ew, keyMappingToIndex, ref.Metadata())
+		writeMetadata(ew, keyMappingToIndex, ref.Metadata(), canonical)
 	}
 
 	// Write number of binaries
@@ -302,7 +374,7 @@ func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappi
 		read = binary.PutUvarint(refSize, bin.Size())
 		ew.Write(refSize[:read])
 
-		writeMetadata(ew, keyMappingToIndex, bin.Metadata())
+		writeMetadata(ew, keyMappingToIndex, bin.Metadata(), canonical)
 
 		actualbinaryWritten, _ := io.Copy(ew, bin.Data())
 		if actualbinaryWritten != int64(bin.Size()) {
@@ -318,7 +390,7 @@ func recurseRecordingToBytes(out io.Writer, recording format.Recording, keyMappi
 	// Write all child recordings
 	newOffset := offset + len(recording.CaptureCollections())
 	for _, rec := range recording.Recordings() {
-		_, updatedOffset, err := recurseRecordingToBytes(ew, rec, keyMappingToIndex, encodingBlocks, streamIndexToEncoderUsedIndex, newOffset, tech)
+		_, updatedOffset, err := recurseRecordingToBytes(ew, rec, keyMappingToIndex, encodingBlocks, streamIndexToEncoderUsedIndex, newOffset, tech, canonical)
 		if err != nil {
 			return ew.TotalWritten(), -1, err
 		}
@@ -375,7 +447,7 @@ func (w Writer) Write(recording format.Recording) (int, error) {
 	totalBytesWritten := 0
 
 	// Write version number
-	written, err := w.out.Write([]byte{2})
+	written, err := w.out.Write([]byte{3})
 	totalBytesWritten += written
 	if err != nil {
 		return totalBytesWritten, err
@@ -439,6 +511,12 @@ func (w Writer) Write(recording format.Recording) (int, error) {
 	for key, index := range keyMappingToIndex {
 		allKeys[index] = key
 	}
+	if w.canonical {
+		sort.Strings(allKeys)
+		for index, key := range allKeys {
+			keyMappingToIndex[key] = index
+		}
+	}
 	written, err = compressWriter.Write(rapbinary.StringArrayToBytes(allKeys))
 	totalBytesWritten += written
 	if err != nil {
@@ -446,7 +524,7 @@ func (w Writer) Write(recording format.Recording) (int, error) {
 	}
 
 	// Write out all recordings
-	written, _, err = recurseRecordingToBytes(compressWriter, recording, keyMappingToIndex, encodingBlocks, streamIndexToEncoderUsedIndex, 0, w.timeStorageTechnique)
+	written, _, err = recurseRecordingToBytes(compressWriter, recording, keyMappingToIndex, encodingBlocks, streamIndexToEncoderUsedIndex, 0, w.timeStorageTechnique, w.canonical)
 	totalBytesWritten += written
 	if err != nil {
 		return totalBytesWritten, err
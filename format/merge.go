@@ -0,0 +1,505 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/recolude/rap/format/metadata"
+)
+
+// IDCollision is how Merge handles two recordings in the merged tree ending up
+// with the same ID.
+type IDCollision int
+
+const (
+	// RenameCollidingIDs keeps the first recording's ID and suffixes later
+	// ones with "-2", "-3" and so on
+	RenameCollidingIDs IDCollision = iota
+
+	// ClearCollidingIDs keeps the first recording's ID and clears the IDs of
+	// later ones
+	ClearCollidingIDs
+
+	// FailOnCollidingIDs makes Merge return an error
+	FailOnCollidingIDs
+)
+
+// MetadataMerge is how Merge combines the metadata of the recordings it
+// brings together. Keys found in only one block are always kept, and nested
+// blocks are combined key by key. The strategy decides what happens when the
+// same key holds different values.
+type MetadataMerge int
+
+const (
+	// PreferFirstMetadata keeps the value of the earliest recording
+	PreferFirstMetadata MetadataMerge = iota
+
+	// PreferLastMetadata keeps the value of the latest recording
+	PreferLastMetadata
+
+	// FailOnMetadataConflicts makes Merge return an error
+	FailOnMetadataConflicts
+)
+
+type MergeOption func(options *mergeOptions)
+
+type mergeOptions struct {
+	name            string
+	matchIDs        bool
+	offsets         map[int]float64
+	alignEvents     bool
+	alignmentEvents []string
+	idCollision     IDCollision
+	metadata        MetadataMerge
+}
+
+// NameOfMerge sets the name of the root recording Merge creates when nesting
+// each recording as a child. Defaults to "merged".
+func NameOfMerge(name string) MergeOption {
+	return func(options *mergeOptions) {
+		options.name = name
+	}
+}
+
+// MatchIDsInMerge has Merge combine recordings that share an ID instead of
+// nesting each recording as a child of a new root. The roots of all
+// recordings become a single root, and children sharing an ID anywhere below
+// are combined into one recording holding the binaries and children of each.
+// Collections sharing a name are appended into one in the order their
+// captures begin. Recordings that can't be combined, because their IDs
+// differ or they hold collections of the same name that overlap in time or
+// can't be appended, are kept as separate recordings, with roots nested
+// under a new one, and the IDs they share are handled by IDCollisionsInMerge.
+func MatchIDsInMerge(match bool) MergeOption {
+	return func(options *mergeOptions) {
+		options.matchIDs = match
+	}
+}
+
+// OffsetInMerge adds the offset, in seconds, to the time of every capture of
+// the recording at the index provided, moving its binaries along with it as
+// Retime does.
+func OffsetInMerge(index int, offset float64) MergeOption {
+	return func(options *mergeOptions) {
+		options.offsets[index] = offset
+	}
+}
+
+// AlignEventsInMerge has Merge estimate the offset of every recording after
+// the first from events found in both it and the first recording. Events are
+// paired up by name in the order they occur, and the median difference in
+// their times is used as the offset. If names are provided, only events with
+// those names are considered. A recording sharing no events with the first
+// keeps whatever offset OffsetInMerge gave it.
+func AlignEventsInMerge(names ...string) MergeOption {
+	return func(options *mergeOptions) {
+		options.alignEvents = true
+		options.alignmentEvents = names
+	}
+}
+
+// IDCollisionsInMerge sets how recordings that end up sharing an ID are
+// handled. Defaults to RenameCollidingIDs.
+func IDCollisionsInMerge(resolution IDCollision) MergeOption {
+	return func(options *mergeOptions) {
+		options.idCollision = resolution
+	}
+}
+
+// MetadataInMerge sets how conflicting metadata is resolved. Defaults to
+// PreferFirstMetadata.
+func MetadataInMerge(strategy MetadataMerge) MergeOption {
+	return func(options *mergeOptions) {
+		options.metadata = strategy
+	}
+}
+
+// eventTimes gathers the times of every event in the tree by event name.
+func eventTimes(rec Recording, names []string) map[string][]float64 {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+
+	times := make(map[string][]float64)
+	Walk(rec, func(current Recording, path string, depth int) error {
+		for _, collection := range current.CaptureCollections() {
+			if collection.Signature() != "recolude.event" {
+				continue
+			}
+			for _, capture := range collection.Captures() {
+				event, ok := capture.(eventCapture)
+				if !ok || (len(allowed) > 0 && !allowed[event.Name()]) {
+					continue
+				}
+				times[event.Name()] = append(times[event.Name()], capture.Time())
+			}
+		}
+		return nil
+	})
+
+	for _, eventTimes := range times {
+		sort.Float64s(eventTimes)
+	}
+	return times
+}
+
+// estimateOffset finds the offset that best lines the events of the recording
+// up with the reference events, or false if they have none in common.
+func estimateOffset(reference, events map[string][]float64) (float64, bool) {
+	differences := make([]float64, 0)
+	for name, times := range events {
+		referenceTimes := reference[name]
+		for i := 0; i < len(times) && i < len(referenceTimes); i++ {
+			differences = append(differences, referenceTimes[i]-times[i])
+		}
+	}
+
+	if len(differences) == 0 {
+		return 0, false
+	}
+
+	sort.Float64s(differences)
+	middle := len(differences) / 2
+	if len(differences)%2 == 1 {
+		return differences[middle], true
+	}
+	return (differences[middle-1] + differences[middle]) / 2, true
+}
+
+func propertiesEqual(a, b metadata.Property) bool {
+	return a.Code() == b.Code() && bytes.Equal(a.Data(), b.Data())
+}
+
+func mergeMetadata(blocks []metadata.Block, strategy MetadataMerge, path string) (metadata.Block, error) {
+	keys := make([]string, 0)
+	values := make(map[string][]metadata.Property)
+	for _, block := range blocks {
+		for _, key := range block.Keys() {
+			if _, seen := values[key]; !seen {
+				keys = append(keys, key)
+			}
+			values[key] = append(values[key], block.Mapping()[key])
+		}
+	}
+
+	merged := make(map[string]metadata.Property, len(keys))
+	for _, key := range keys {
+		props := values[key]
+
+		nested := make([]metadata.Block, 0, len(props))
+		for _, prop := range props {
+			if block, ok := prop.(metadata.MetadataProperty); ok {
+				nested = append(nested, block.Block())
+			}
+		}
+		if len(nested) == len(props) && len(props) > 1 {
+			block, err := mergeMetadata(nested, strategy, path+"/"+key)
+			if err != nil {
+				return metadata.Block{}, err
+			}
+			merged[key] = metadata.NewMetadataProperty(block)
+			continue
+		}
+
+		merged[key] = props[0]
+		for _, prop := range props[1:] {
+			if propertiesEqual(merged[key], prop) {
+				continue
+			}
+			switch strategy {
+			case PreferLastMetadata:
+				merged[key] = prop
+			case FailOnMetadataConflicts:
+				return metadata.Block{}, fmt.Errorf("metadata %s/%s has conflicting values %s and %s", path, key, merged[key].String(), prop.String())
+			}
+		}
+	}
+
+	return metadata.NewBlock(merged), nil
+}
+
+// collectionSpan is the time of the first and last capture of the
+// collection, and false for collections without captures.
+func collectionSpan(collection CaptureCollection) (float64, float64, bool) {
+	if collection.Length() == 0 {
+		return 0, 0, false
+	}
+	return collection.CaptureAt(0).Time(), collection.CaptureAt(collection.Length() - 1).Time(), true
+}
+
+// joinable reports whether two collections sharing a name can be appended
+// into one without their captures falling out of order.
+func joinable(a, b CaptureCollection) bool {
+	if a.Signature() != b.Signature() {
+		return false
+	}
+	if _, ok := a.(Appender); !ok {
+		return false
+	}
+	if _, ok := b.(Appender); !ok {
+		return false
+	}
+
+	aStart, aEnd, aCaptured := collectionSpan(a)
+	bStart, bEnd, bCaptured := collectionSpan(b)
+	return !aCaptured || !bCaptured || aEnd <= bStart || bEnd <= aStart
+}
+
+// combinable reports whether the recording can be merged with every
+// recording of the set, sharing its ID and holding no collection that
+// collides with one of theirs.
+func combinable(set []Recording, rec Recording) bool {
+	if set[0].ID() != rec.ID() {
+		return false
+	}
+
+	for _, member := range set {
+		for _, existing := range member.CaptureCollections() {
+			for _, collection := range rec.CaptureCollections() {
+				if existing.Name() == collection.Name() && !joinable(existing, collection) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// joinCollections appends collections sharing a name into one, in the order
+// their captures begin.
+func joinCollections(collections []CaptureCollection, path string) ([]CaptureCollection, error) {
+	names := make([]string, 0)
+	byName := make(map[string][]CaptureCollection)
+	for _, collection := range collections {
+		if _, seen := byName[collection.Name()]; !seen {
+			names = append(names, collection.Name())
+		}
+		byName[collection.Name()] = append(byName[collection.Name()], collection)
+	}
+
+	joined := make([]CaptureCollection, 0, len(names))
+	for _, name := range names {
+		group := byName[name]
+		sort.SliceStable(group, func(i, j int) bool {
+			iStart, _, iCaptured := collectionSpan(group[i])
+			jStart, _, jCaptured := collectionSpan(group[j])
+			return jCaptured && (!iCaptured || iStart < jStart)
+		})
+
+		collection := group[0]
+		for _, next := range group[1:] {
+			var err error
+			collection, err = collection.(Appender).Append(next)
+			if err != nil {
+				return nil, fmt.Errorf("%s#%s: %w", path, name, err)
+			}
+		}
+		joined = append(joined, collection)
+	}
+
+	return joined, nil
+}
+
+// mergeMatching combines recordings sharing an ID into as few recordings as
+// it can. Recordings whose IDs differ, or whose collections collide with
+// ones of the same name, are kept apart.
+func mergeMatching(recs []Recording, strategy MetadataMerge, path string) ([]Recording, error) {
+	sets := make([][]Recording, 0)
+	for _, rec := range recs {
+		placed := false
+		for i, set := range sets {
+			if combinable(set, rec) {
+				sets[i] = append(set, rec)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			sets = append(sets, []Recording{rec})
+		}
+	}
+
+	merged := make([]Recording, len(sets))
+	for i, set := range sets {
+		rec, err := combineRecordings(set, strategy, path+set[0].Name())
+		if err != nil {
+			return nil, err
+		}
+		merged[i] = rec
+	}
+	return merged, nil
+}
+
+func combineRecordings(recs []Recording, strategy MetadataMerge, path string) (Recording, error) {
+	blocks := make([]metadata.Block, len(recs))
+	for i, rec := range recs {
+		blocks[i] = rec.Metadata()
+	}
+	block, err := mergeMetadata(blocks, strategy, "")
+	if err != nil {
+		return nil, err
+	}
+
+	builder := NewBuilder().SetID(recs[0].ID()).SetName(recs[0].Name()).SetMetadata(block)
+
+	collections := make([]CaptureCollection, 0)
+	groups := make([][]Recording, 0)
+	groupIndex := make(map[string]int)
+	for _, rec := range recs {
+		collections = append(collections, rec.CaptureCollections()...)
+		builder.AddBinary(rec.Binaries()...).
+			AddBinaryReference(rec.BinaryReferences()...)
+
+		for _, child := range rec.Recordings() {
+			if index, ok := groupIndex[child.ID()]; ok && child.ID() != "" {
+				groups[index] = append(groups[index], child)
+				continue
+			}
+			groupIndex[child.ID()] = len(groups)
+			groups = append(groups, []Recording{child})
+		}
+	}
+
+	joined, err := joinCollections(collections, path)
+	if err != nil {
+		return nil, err
+	}
+	builder.AddCollection(joined...)
+
+	for _, group := range groups {
+		if len(group) == 1 {
+			builder.AddChild(group[0])
+			continue
+		}
+
+		children, err := mergeMatching(group, strategy, path+"/")
+		if err != nil {
+			return nil, err
+		}
+		builder.AddChild(children...)
+	}
+
+	return builder.Build(), nil
+}
+
+func resolveIDCollisions(rec Recording, seen map[string]bool, resolution IDCollision) (Recording, error) {
+	id := rec.ID()
+	if id != "" && seen[id] {
+		switch resolution {
+		case FailOnCollidingIDs:
+			return nil, fmt.Errorf("recording ID %s is used more than once", id)
+		case ClearCollidingIDs:
+			id = ""
+		default:
+			suffix := 2
+			for seen[rec.ID()+"-"+strconv.Itoa(suffix)] {
+				suffix++
+			}
+			id = rec.ID() + "-" + strconv.Itoa(suffix)
+		}
+	}
+	if id != "" {
+		seen[id] = true
+	}
+
+	children := make([]Recording, len(rec.Recordings()))
+	for i, child := range rec.Recordings() {
+		resolved, err := resolveIDCollisions(child, seen, resolution)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = resolved
+	}
+
+	return NewRecording(id, rec.Name(), rec.CaptureCollections(), children, rec.Metadata(), rec.Binaries(), rec.BinaryReferences()), nil
+}
+
+// Merge combines recordings made at the same time, such as one per client of
+// a multiplayer session, into a single recording. By default, every recording
+// becomes a child of a new root, see MatchIDsInMerge to combine recordings by
+// ID instead. The metadata of all recordings is merged into the root either
+// way.
+func Merge(recs []Recording, options ...MergeOption) (Recording, error) {
+	if len(recs) == 0 {
+		return nil, errors.New("merge requires at least one recording")
+	}
+
+	finalOpts := &mergeOptions{
+		name:        "merged",
+		offsets:     make(map[int]float64),
+		idCollision: RenameCollidingIDs,
+		metadata:    PreferFirstMetadata,
+	}
+
+	for _, opt := range options {
+		opt(finalOpts)
+	}
+
+	for index := range finalOpts.offsets {
+		if index < 0 || index >= len(recs) {
+			return nil, fmt.Errorf("offset index %d must be within the %d recordings being merged", index, len(recs))
+		}
+	}
+
+	offsets := make([]float64, len(recs))
+	for i := range recs {
+		offsets[i] = finalOpts.offsets[i]
+	}
+
+	if finalOpts.alignEvents {
+		reference := eventTimes(recs[0], finalOpts.alignmentEvents)
+		for _, times := range reference {
+			for i := range times {
+				times[i] += offsets[0]
+			}
+		}
+
+		for i := 1; i < len(recs); i++ {
+			if offset, ok := estimateOffset(reference, eventTimes(recs[i], finalOpts.alignmentEvents)); ok {
+				offsets[i] = offset
+			}
+		}
+	}
+
+	shifted := make([]Recording, len(recs))
+	for i, rec := range recs {
+		shifted[i] = rec
+		if offset := offsets[i]; offset != 0 {
+			var err error
+			shifted[i], err = Retime(rec, Shift(offset))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	children := shifted
+	if finalOpts.matchIDs {
+		var err error
+		children, err = mergeMatching(shifted, finalOpts.metadata, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var merged Recording
+	if len(children) == 1 && finalOpts.matchIDs {
+		merged = children[0]
+	} else {
+		blocks := make([]metadata.Block, len(shifted))
+		for i, rec := range shifted {
+			blocks[i] = rec.Metadata()
+		}
+		block, err := mergeMetadata(blocks, finalOpts.metadata, "")
+		if err != nil {
+			return nil, err
+		}
+		merged = NewBuilder().SetName(finalOpts.name).SetMetadata(block).AddChild(children...).Build()
+	}
+
+	return resolveIDCollisions(merged, make(map[string]bool), finalOpts.idCollision)
+}
@@ -0,0 +1,203 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func clientRecording(id string, start float64, block metadata.Block, players ...format.Recording) format.Recording {
+	return format.NewBuilder().
+		SetID(id).
+		SetName("session").
+		SetMetadata(block).
+		AddCollection(event.NewCollection("Events", []event.Capture{
+			event.NewCapture(start+1, "RoundStart", metadata.EmptyBlock()),
+			event.NewCapture(start+5, "RoundEnd", metadata.EmptyBlock()),
+		})).
+		AddChild(players...).
+		Build()
+}
+
+func player(id string, times ...float64) format.Recording {
+	captures := make([]position.Capture, len(times))
+	for i, time := range times {
+		captures[i] = position.NewCapture(time, 0, 0, 0)
+	}
+	return format.NewBuilder().
+		SetID(id).
+		SetName(id).
+		AddCollection(position.NewCollection("Position", captures)).
+		Build()
+}
+
+func Test_Merge_AsChildren(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("session-id", 0, metadata.NewBlock(map[string]metadata.Property{
+		"map":    metadata.NewStringProperty("castle"),
+		"client": metadata.NewStringProperty("a"),
+	}), player("p1", 1))
+	b := clientRecording("session-id", 0, metadata.NewBlock(map[string]metadata.Property{
+		"client": metadata.NewStringProperty("b"),
+		"mode":   metadata.NewStringProperty("coop"),
+	}), player("p1", 1))
+
+	// ACT ====================================================================
+	merged, err := format.Merge([]format.Recording{a, b}, format.OffsetInMerge(1, 2.5))
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "merged", merged.Name())
+	assert.Equal(t, "castle", merged.Metadata().Mapping()["map"].String())
+	assert.Equal(t, "a", merged.Metadata().Mapping()["client"].String())
+	assert.Equal(t, "coop", merged.Metadata().Mapping()["mode"].String())
+
+	if assert.Len(t, merged.Recordings(), 2) {
+		first := merged.Recordings()[0]
+		second := merged.Recordings()[1]
+		assert.Equal(t, "session-id", first.ID())
+		assert.Equal(t, "session-id-2", second.ID())
+		assert.Equal(t, "p1", first.Recordings()[0].ID())
+		assert.Equal(t, "p1-2", second.Recordings()[0].ID())
+
+		assert.Equal(t, 1.0, first.Recordings()[0].CaptureCollections()[0].Start())
+		assert.Equal(t, 3.5, second.Recordings()[0].CaptureCollections()[0].Start())
+		assert.Equal(t, 3.5, second.CaptureCollections()[0].Start())
+	}
+}
+
+func Test_Merge_MatchingIDs(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("session-id", 10, metadata.EmptyBlock(), player("p1", 13), player("p2", 1))
+	b := clientRecording("session-id", 0, metadata.EmptyBlock(), player("p1", 1, 2), player("p3", 1))
+
+	// ACT ====================================================================
+	merged, err := format.Merge([]format.Recording{a, b}, format.MatchIDsInMerge(true))
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "session-id", merged.ID())
+	assert.Equal(t, "session", merged.Name())
+	if assert.Len(t, merged.CaptureCollections(), 1) {
+		assert.Equal(t, 4, merged.CaptureCollections()[0].Length())
+		assert.Equal(t, 1.0, merged.CaptureCollections()[0].Start())
+		assert.Equal(t, 15.0, merged.CaptureCollections()[0].End())
+	}
+
+	if assert.Len(t, merged.Recordings(), 3) {
+		assert.Equal(t, "p1", merged.Recordings()[0].ID())
+		if assert.Len(t, merged.Recordings()[0].CaptureCollections(), 1) {
+			position := merged.Recordings()[0].CaptureCollections()[0]
+			assert.Equal(t, 3, position.Length())
+			assert.Equal(t, 1.0, position.CaptureAt(0).Time())
+			assert.Equal(t, 2.0, position.CaptureAt(1).Time())
+			assert.Equal(t, 13.0, position.CaptureAt(2).Time())
+		}
+		assert.Equal(t, "p2", merged.Recordings()[1].ID())
+		assert.Equal(t, "p3", merged.Recordings()[2].ID())
+	}
+}
+
+func Test_Merge_MatchingIDs_CollidingCollections(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("session-id", 10, metadata.EmptyBlock(), player("p1", 11, 12))
+	b := clientRecording("session-id", 20, metadata.EmptyBlock(), player("p1", 11.5))
+	recs := []format.Recording{a, b}
+
+	// ACT ====================================================================
+	renamed, renamedErr := format.Merge(recs, format.MatchIDsInMerge(true))
+	_, failedErr := format.Merge(recs, format.MatchIDsInMerge(true), format.IDCollisionsInMerge(format.FailOnCollidingIDs))
+
+	// ASSERT =================================================================
+	assert.NoError(t, renamedErr)
+	assert.Equal(t, "session-id", renamed.ID())
+	if assert.Len(t, renamed.Recordings(), 2) {
+		assert.Equal(t, "p1", renamed.Recordings()[0].ID())
+		assert.Equal(t, "p1-2", renamed.Recordings()[1].ID())
+		for _, player := range renamed.Recordings() {
+			assert.Len(t, player.CaptureCollections(), 1)
+		}
+	}
+	assert.EqualError(t, failedErr, "recording ID p1 is used more than once")
+}
+
+func Test_Merge_MatchingIDs_DifferentRoots(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("a", 0, metadata.EmptyBlock(), player("p1", 1))
+	b := clientRecording("b", 10, metadata.EmptyBlock(), player("p1", 11))
+
+	// ACT ====================================================================
+	merged, err := format.Merge([]format.Recording{a, b}, format.MatchIDsInMerge(true))
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "merged", merged.Name())
+	if assert.Len(t, merged.Recordings(), 2) {
+		assert.Equal(t, "a", merged.Recordings()[0].ID())
+		assert.Equal(t, "b", merged.Recordings()[1].ID())
+		assert.Equal(t, "p1", merged.Recordings()[0].Recordings()[0].ID())
+		assert.Equal(t, "p1-2", merged.Recordings()[1].Recordings()[0].ID())
+	}
+}
+
+func Test_Merge_AlignEvents(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("a", 10, metadata.EmptyBlock(), player("p1", 11))
+	b := clientRecording("b", 100, metadata.EmptyBlock(), player("p2", 101))
+	c := format.NewBuilder().SetID("c").AddChild(player("p3", 7)).Build()
+
+	// ACT ====================================================================
+	merged, err := format.Merge(
+		[]format.Recording{a, b, c},
+		format.AlignEventsInMerge("RoundStart", "RoundEnd"),
+		format.OffsetInMerge(2, 1),
+	)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	if assert.Len(t, merged.Recordings(), 3) {
+		assert.Equal(t, 11.0, merged.Recordings()[0].Recordings()[0].CaptureCollections()[0].Start())
+		assert.Equal(t, 11.0, merged.Recordings()[1].Recordings()[0].CaptureCollections()[0].Start())
+		assert.Equal(t, 8.0, merged.Recordings()[2].Recordings()[0].CaptureCollections()[0].Start())
+	}
+}
+
+func Test_Merge_Collisions(t *testing.T) {
+	// ARRANGE ================================================================
+	a := clientRecording("session-id", 0, metadata.NewBlock(map[string]metadata.Property{
+		"settings": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"difficulty": metadata.NewStringProperty("hard"),
+		})),
+	}))
+	b := clientRecording("session-id", 0, metadata.NewBlock(map[string]metadata.Property{
+		"settings": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"difficulty": metadata.NewStringProperty("easy"),
+		})),
+	}))
+	recs := []format.Recording{a, b}
+
+	// ACT ====================================================================
+	cleared, clearedErr := format.Merge(recs, format.IDCollisionsInMerge(format.ClearCollidingIDs), format.MetadataInMerge(format.PreferLastMetadata))
+	_, idErr := format.Merge(recs, format.IDCollisionsInMerge(format.FailOnCollidingIDs))
+	_, metadataErr := format.Merge(recs, format.MetadataInMerge(format.FailOnMetadataConflicts))
+	_, offsetErr := format.Merge(recs, format.OffsetInMerge(2, 1))
+	_, emptyErr := format.Merge(nil)
+
+	// ASSERT =================================================================
+	assert.NoError(t, clearedErr)
+	if assert.Len(t, cleared.Recordings(), 2) {
+		assert.Equal(t, "session-id", cleared.Recordings()[0].ID())
+		assert.Equal(t, "", cleared.Recordings()[1].ID())
+	}
+	difficulty, _ := cleared.Metadata().GetString("settings.difficulty")
+	assert.Equal(t, "easy", difficulty)
+
+	assert.EqualError(t, idErr, "recording ID session-id is used more than once")
+	assert.EqualError(t, metadataErr, "metadata /settings/difficulty has conflicting values hard and easy")
+	assert.EqualError(t, offsetErr, "offset index 2 must be within the 2 recordings being merged")
+	assert.EqualError(t, emptyErr, "merge requires at least one recording")
+}
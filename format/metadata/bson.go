@@ -0,0 +1,216 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// bsonFloatProperty picks Float32 when the double is exactly what a float32
+// property would have written, and Float64 otherwise.
+func bsonFloatProperty(f float64) Property {
+	if math.IsNaN(f) || float64(float32(f)) == f {
+		return NewFloat32Property(float32(f))
+	}
+	return NewFloat64Property(f)
+}
+
+func bsonInt64(value bson.RawValue) (int64, bool) {
+	switch value.Type {
+	case bson.TypeInt32:
+		return int64(value.Int32()), true
+	case bson.TypeInt64:
+		return value.Int64(), true
+	}
+	return 0, false
+}
+
+func bsonFloat64(value bson.RawValue) (float64, bool) {
+	if value.Type == bson.TypeDouble {
+		return value.Double(), true
+	}
+	i, ok := bsonInt64(value)
+	return float64(i), ok
+}
+
+func bsonUint64(value bson.RawValue) (uint64, bool) {
+	if i, ok := bsonInt64(value); ok {
+		return uint64(i), i >= 0
+	}
+
+	decimal, ok := value.Decimal128OK()
+	if !ok {
+		return 0, false
+	}
+
+	significand, exponent, err := decimal.BigInt()
+	if err != nil || significand.Sign() < 0 {
+		return 0, false
+	}
+
+	for ; exponent > 0; exponent-- {
+		significand.Mul(significand, big.NewInt(10))
+	}
+	if exponent < 0 || !significand.IsUint64() {
+		return 0, false
+	}
+	return significand.Uint64(), true
+}
+
+func bsonVector(doc bson.Raw, keys ...string) ([]float64, bool) {
+	elements, err := doc.Elements()
+	if err != nil || len(elements) != len(keys) {
+		return nil, false
+	}
+
+	components := make([]float64, len(keys))
+	for i, key := range keys {
+		component, err := doc.LookupErr(key)
+		if err != nil {
+			return nil, false
+		}
+
+		var ok bool
+		components[i], ok = bsonFloat64(component)
+		if !ok {
+			return nil, false
+		}
+	}
+	return components, true
+}
+
+func bsonBlock(doc bson.Raw) (Block, error) {
+	elements, err := doc.Elements()
+	if err != nil {
+		return EmptyBlock(), err
+	}
+
+	mapping := make(map[string]Property, len(elements))
+	for _, element := range elements {
+		prop, err := PropertyFromBSONValue(element.Value())
+		if err != nil {
+			return EmptyBlock(), fmt.Errorf("%s: %w", element.Key(), err)
+		}
+		mapping[element.Key()] = prop
+	}
+	return NewBlock(mapping), nil
+}
+
+func bsonArray(arr bson.Raw) (Property, error) {
+	values, err := arr.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return NewStringArrayProperty(nil), nil
+	}
+
+	if values[0].Type == bson.TypeBoolean {
+		bools := make([]bool, len(values))
+		for i, value := range values {
+			b, ok := value.BooleanOK()
+			if !ok {
+				return nil, errors.New("metadata arrays must contain a single type")
+			}
+			bools[i] = b
+		}
+		return NewBoolArrayProperty(bools), nil
+	}
+
+	props := make([]Property, len(values))
+	allNumeric := true
+	sameType := true
+	for i, value := range values {
+		props[i], err = PropertyFromBSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		allNumeric = allNumeric && isNumericCode(props[i].Code())
+		sameType = sameType && props[i].Code() == props[0].Code()
+	}
+
+	if !sameType {
+		if !allNumeric {
+			return nil, errors.New("metadata arrays must contain a single type")
+		}
+		props = promoteNumbers(props)
+	}
+	if _, isNull := props[0].(NullProperty); isNull {
+		return nil, errors.New("metadata arrays can not contain null")
+	}
+	return newArrayProperty(props[0].Code(), props), nil
+}
+
+// PropertyFromBSONValue interprets a BSON value as the property type that
+// would have marshaled into it. Where BSON can't tell two property types apart
+// (bytes are written as int32, and float32s as doubles) the narrowest type
+// that holds the value exactly is chosen.
+func PropertyFromBSONValue(value bson.RawValue) (Property, error) {
+	switch value.Type {
+	case bson.TypeString:
+		return NewStringProperty(value.StringValue()), nil
+
+	case bson.TypeInt32:
+		return NewIntProperty(int(value.Int32())), nil
+
+	case bson.TypeInt64:
+		return NewInt64Property(value.Int64()), nil
+
+	case bson.TypeDouble:
+		return bsonFloatProperty(value.Double()), nil
+
+	case bson.TypeDecimal128:
+		u, ok := bsonUint64(value)
+		if !ok {
+			return nil, fmt.Errorf("decimal %s is not an unsigned 64 bit integer", value.Decimal128().String())
+		}
+		return NewUintProperty(u), nil
+
+	case bson.TypeBoolean:
+		return NewBoolProperty(value.Boolean()), nil
+
+	case bson.TypeDateTime:
+		return NewTimeProperty(value.Time()), nil
+
+	case bson.TypeNull:
+		return NewNullProperty(), nil
+
+	case bson.TypeBinary:
+		_, data := value.Binary()
+		return NewBinaryArrayProperty(data), nil
+
+	case bson.TypeEmbeddedDocument:
+		doc := value.Document()
+		if v, ok := bsonVector(doc, "x", "y"); ok {
+			return NewVector2Property(v[0], v[1]), nil
+		}
+		if v, ok := bsonVector(doc, "x", "y", "z"); ok {
+			return NewVector3Property(v[0], v[1], v[2]), nil
+		}
+		block, err := bsonBlock(doc)
+		if err != nil {
+			return nil, err
+		}
+		return NewMetadataProperty(block), nil
+
+	case bson.TypeArray:
+		return bsonArray(value.Array())
+	}
+
+	return nil, fmt.Errorf("no metadata property for bson type %s", value.Type)
+}
+
+// BlockFromBSON builds a block from a BSON document, interpreting every
+// element with PropertyFromBSONValue.
+func BlockFromBSON(doc bson.Raw) (Block, error) {
+	return bsonBlock(doc)
+}
+
+func bsonTypeError(property string, t bsontype.Type) error {
+	return fmt.Errorf("%s can not be unmarshaled from bson %s", property, t)
+}
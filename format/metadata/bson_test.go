@@ -0,0 +1,173 @@
+package metadata_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func Test_MetadataProperty_BSONRoundTrip(t *testing.T) {
+	// ARRANGE ================================================================
+	timestamp := time.Unix(1634567890, 123000000)
+	original := metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+		"str":      metadata.NewStringProperty("hello"),
+		"int32":    metadata.NewIntProperty(-42),
+		"int64":    metadata.NewInt64Property(math.MaxInt64),
+		"float32":  metadata.NewFloat32Property(1.5),
+		"float64":  metadata.NewFloat64Property(0.1),
+		"uint":     metadata.NewUintProperty(math.MaxUint64),
+		"bool":     metadata.NewBoolProperty(true),
+		"time":     metadata.NewTimeProperty(timestamp),
+		"null":     metadata.NewNullProperty(),
+		"vec2":     metadata.NewVector2Property(1, 2),
+		"vec3":     metadata.NewVector3Property(1, 2, 3),
+		"binary":   metadata.NewBinaryArrayProperty([]byte{1, 2, 3}),
+		"bools":    metadata.NewBoolArrayProperty([]bool{true, false}),
+		"strs":     metadata.NewStringArrayProperty([]string{"a", "b"}),
+		"float64s": metadata.NewFloat64ArrayProperty([]float64{0.1, 0.2}),
+		"nested": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"deeper": metadata.NewStringProperty("value"),
+		})),
+	}))
+
+	// ACT ====================================================================
+	data, marshalErr := bson.Marshal(original)
+	var decoded metadata.MetadataProperty
+	unmarshalErr := bson.Unmarshal(data, &decoded)
+
+	// ASSERT =================================================================
+	assert.NoError(t, marshalErr)
+	assert.NoError(t, unmarshalErr)
+	assert.Equal(t, original, decoded)
+}
+
+func Test_MetadataProperty_MarshalBSONIsOrdered(t *testing.T) {
+	// ARRANGE ================================================================
+	prop := metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+		"c": metadata.NewIntProperty(3),
+		"a": metadata.NewIntProperty(1),
+		"b": metadata.NewIntProperty(2),
+	}))
+
+	// ACT ====================================================================
+	data, err := bson.Marshal(prop)
+	elements, elementsErr := bson.Raw(data).Elements()
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.NoError(t, elementsErr)
+	if assert.Len(t, elements, 3) {
+		assert.Equal(t, "a", elements[0].Key())
+		assert.Equal(t, "b", elements[1].Key())
+		assert.Equal(t, "c", elements[2].Key())
+	}
+}
+
+func Test_PropertyFromBSONValue(t *testing.T) {
+	tests := map[string]struct {
+		value    interface{}
+		expected metadata.Property
+	}{
+		"string":          {value: "str", expected: metadata.NewStringProperty("str")},
+		"int32":           {value: int32(7), expected: metadata.NewIntProperty(7)},
+		"int64":           {value: int64(7), expected: metadata.NewInt64Property(7)},
+		"float32":         {value: float64(float32(1.1)), expected: metadata.NewFloat32Property(1.1)},
+		"float64":         {value: 1.1, expected: metadata.NewFloat64Property(1.1)},
+		"bool":            {value: false, expected: metadata.NewBoolProperty(false)},
+		"null":            {value: primitive.Null{}, expected: metadata.NewNullProperty()},
+		"binary":          {value: []byte{9, 8}, expected: metadata.NewBinaryArrayProperty([]byte{9, 8})},
+		"vector2":         {value: bson.D{{Key: "x", Value: 1}, {Key: "y", Value: 2.5}}, expected: metadata.NewVector2Property(1, 2.5)},
+		"empty array":     {value: bson.A{}, expected: metadata.NewStringArrayProperty(nil)},
+		"promoted array":  {value: bson.A{int32(1), int64(2)}, expected: metadata.NewInt64ArrayProperty([]int64{1, 2})},
+		"bool array":      {value: bson.A{true, true}, expected: metadata.NewBoolArrayProperty([]bool{true, true})},
+		"not quite a vec": {value: bson.D{{Key: "x", Value: 1}, {Key: "y", Value: "2"}}, expected: metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{"x": metadata.NewIntProperty(1), "y": metadata.NewStringProperty("2")}))},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			bsonType, data, err := bson.MarshalValue(tc.value)
+			assert.NoError(t, err)
+
+			prop, err := metadata.PropertyFromBSONValue(bson.RawValue{Type: bsonType, Value: data})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, prop)
+		})
+	}
+}
+
+func Test_PropertyFromBSONValue_MixedArrayErrors(t *testing.T) {
+	// ARRANGE ================================================================
+	bsonType, data, err := bson.MarshalValue(bson.A{"a", int32(1)})
+
+	// ACT ====================================================================
+	prop, propErr := metadata.PropertyFromBSONValue(bson.RawValue{Type: bsonType, Value: data})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.EqualError(t, propErr, "metadata arrays must contain a single type")
+	assert.Nil(t, prop)
+}
+
+func Test_PropertyFromBSONValue_NullArrayErrors(t *testing.T) {
+	// ARRANGE ================================================================
+	bsonType, data, err := bson.MarshalValue(bson.A{nil, nil})
+
+	// ACT ====================================================================
+	prop, propErr := metadata.PropertyFromBSONValue(bson.RawValue{Type: bsonType, Value: data})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.EqualError(t, propErr, "metadata arrays can not contain null")
+	assert.Nil(t, prop)
+}
+
+func Test_Property_UnmarshalBSONValue(t *testing.T) {
+	// ARRANGE ================================================================
+	type document struct {
+		Byte  metadata.ByteProperty    `bson:"byte"`
+		Int   metadata.Int32Property   `bson:"int"`
+		Float metadata.Float32Property `bson:"float"`
+		Uint  metadata.UintProperty    `bson:"uint"`
+		Vec   metadata.Vector3Property `bson:"vec"`
+		Time  metadata.TimeProperty    `bson:"time"`
+	}
+
+	original := document{
+		Byte:  metadata.NewByteProperty(200),
+		Int:   metadata.NewIntProperty(12),
+		Float: metadata.NewFloat32Property(1.1),
+		Uint:  metadata.NewUintProperty(math.MaxUint64 - 1),
+		Vec:   metadata.NewVector3Property(4, 5, 6),
+		Time:  metadata.NewTimeProperty(time.Unix(1634567890, 0)),
+	}
+
+	// ACT ====================================================================
+	data, marshalErr := bson.Marshal(original)
+	var decoded document
+	unmarshalErr := bson.Unmarshal(data, &decoded)
+
+	// ASSERT =================================================================
+	assert.NoError(t, marshalErr)
+	assert.NoError(t, unmarshalErr)
+	assert.Equal(t, original, decoded)
+}
+
+func Test_Property_UnmarshalBSONValue_WrongType(t *testing.T) {
+	// ARRANGE ================================================================
+	data, err := bson.Marshal(bson.M{"byte": "not a byte"})
+	var decoded struct {
+		Byte metadata.ByteProperty `bson:"byte"`
+	}
+
+	// ACT ====================================================================
+	unmarshalErr := bson.Unmarshal(data, &decoded)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Error(t, unmarshalErr)
+}
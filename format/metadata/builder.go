@@ -0,0 +1,58 @@
+package metadata
+
+// Builder constructs metadata blocks. Builders are immutable; every method
+// returns a new builder and leaves the one it was called on untouched, so a
+// shared base can safely be extended in different directions.
+type Builder struct {
+	mapping map[string]Property
+}
+
+// NewBuilder creates a builder with no properties.
+func NewBuilder() Builder {
+	return Builder{mapping: make(map[string]Property)}
+}
+
+// BuilderFrom creates a builder starting with all properties of the block.
+func BuilderFrom(block Block) Builder {
+	return NewBuilder().Merge(block)
+}
+
+func (b Builder) copy(extra int) map[string]Property {
+	mapping := make(map[string]Property, len(b.mapping)+extra)
+	for key, prop := range b.mapping {
+		mapping[key] = prop
+	}
+	return mapping
+}
+
+// With sets the key to the property provided, replacing any existing value.
+func (b Builder) With(key string, prop Property) Builder {
+	mapping := b.copy(1)
+	mapping[key] = prop
+	return Builder{mapping: mapping}
+}
+
+// Without removes the keys provided.
+func (b Builder) Without(keys ...string) Builder {
+	mapping := b.copy(0)
+	for _, key := range keys {
+		delete(mapping, key)
+	}
+	return Builder{mapping: mapping}
+}
+
+// Merge copies every property of the block into the builder. Keys already
+// present in the builder are replaced by the block's values.
+func (b Builder) Merge(block Block) Builder {
+	mapping := b.copy(len(block.Mapping()))
+	for key, prop := range block.Mapping() {
+		mapping[key] = prop
+	}
+	return Builder{mapping: mapping}
+}
+
+// Build creates a block from the builder's properties. Later changes to the
+// builder are not reflected in the block.
+func (b Builder) Build() Block {
+	return NewBlock(b.copy(0))
+}
@@ -0,0 +1,65 @@
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Builder(t *testing.T) {
+	// ARRANGE ================================================================
+	base := metadata.NewBuilder().
+		With("a", metadata.NewIntProperty(1)).
+		With("b", metadata.NewIntProperty(2))
+
+	// ACT ====================================================================
+	withC := base.With("c", metadata.NewIntProperty(3))
+	withoutA := base.Without("a", "not present")
+	merged := base.Merge(metadata.NewBlock(map[string]metadata.Property{
+		"b": metadata.NewStringProperty("replaced"),
+		"d": metadata.NewIntProperty(4),
+	}))
+
+	// ASSERT =================================================================
+	assert.Equal(t, map[string]metadata.Property{
+		"a": metadata.NewIntProperty(1),
+		"b": metadata.NewIntProperty(2),
+	}, base.Build().Mapping())
+
+	assert.Equal(t, map[string]metadata.Property{
+		"a": metadata.NewIntProperty(1),
+		"b": metadata.NewIntProperty(2),
+		"c": metadata.NewIntProperty(3),
+	}, withC.Build().Mapping())
+
+	assert.Equal(t, map[string]metadata.Property{
+		"b": metadata.NewIntProperty(2),
+	}, withoutA.Build().Mapping())
+
+	assert.Equal(t, map[string]metadata.Property{
+		"a": metadata.NewIntProperty(1),
+		"b": metadata.NewStringProperty("replaced"),
+		"d": metadata.NewIntProperty(4),
+	}, merged.Build().Mapping())
+}
+
+func Test_BuilderFrom_DoesNotModifyOriginalBlock(t *testing.T) {
+	// ARRANGE ================================================================
+	original := metadata.NewBlock(map[string]metadata.Property{
+		"a": metadata.NewIntProperty(1),
+	})
+
+	// ACT ====================================================================
+	built := metadata.BuilderFrom(original).
+		With("b", metadata.NewIntProperty(2)).
+		Without("a").
+		Build()
+
+	// ASSERT =================================================================
+	assert.Len(t, original.Mapping(), 1)
+	assert.Equal(t, metadata.NewIntProperty(1), original.Mapping()["a"])
+	assert.Equal(t, map[string]metadata.Property{
+		"b": metadata.NewIntProperty(2),
+	}, built.Mapping())
+}
@@ -0,0 +1,364 @@
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind describes what happened to a path between two blocks.
+type ChangeKind int
+
+const (
+	// Added paths only exist in the newer block
+	Added ChangeKind = iota
+
+	// Removed paths only exist in the older block
+	Removed
+
+	// Changed paths exist in both blocks with different values
+	Changed
+)
+
+func (ck ChangeKind) String() string {
+	switch ck {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(ck))
+}
+
+// Change is a single difference between two blocks.
+type Change struct {
+	Kind ChangeKind
+
+	// Path is a JSON pointer to the property that differs
+	Path string
+
+	// Before is the older value, nil when the path was added
+	Before Property
+
+	// After is the newer value, nil when the path was removed
+	After Property
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Path, c.After.String())
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Path, c.Before.String())
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Before.String(), c.After.String())
+}
+
+// Patch is an ordered list of changes that turns one block into another.
+type Patch []Change
+
+func propertiesEqual(a, b Property) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Code() == b.Code() && bytes.Equal(a.Data(), b.Data())
+}
+
+func diffProperties(path string, before, after Property) Patch {
+	if propertiesEqual(before, after) {
+		return nil
+	}
+
+	beforeBlock, beforeIsBlock := before.(MetadataProperty)
+	afterBlock, afterIsBlock := after.(MetadataProperty)
+	if beforeIsBlock && afterIsBlock {
+		return diffBlocks(path, beforeBlock.Block(), afterBlock.Block())
+	}
+
+	beforeArr, beforeIsArr := before.(ArrayProperty)
+	afterArr, afterIsArr := after.(ArrayProperty)
+	if beforeIsArr && afterIsArr && beforeArr.Code() == afterArr.Code() {
+		beforeProps, afterProps := beforeArr.Properties(), afterArr.Properties()
+
+		patch := make(Patch, 0)
+		for i := 0; i < len(beforeProps) && i < len(afterProps); i++ {
+			patch = append(patch, diffProperties(fmt.Sprintf("%s/%d", path, i), beforeProps[i], afterProps[i])...)
+		}
+
+		// Removals go from the back so each index is still valid when the
+		// patch is applied in order
+		for i := len(beforeProps) - 1; i >= len(afterProps); i-- {
+			patch = append(patch, Change{Kind: Removed, Path: fmt.Sprintf("%s/%d", path, i), Before: beforeProps[i]})
+		}
+
+		for i := len(beforeProps); i < len(afterProps); i++ {
+			patch = append(patch, Change{Kind: Added, Path: fmt.Sprintf("%s/%d", path, i), After: afterProps[i]})
+		}
+		return patch
+	}
+
+	return Patch{{Kind: Changed, Path: path, Before: before, After: after}}
+}
+
+func diffBlocks(prefix string, before, after Block) Patch {
+	patch := make(Patch, 0)
+
+	for _, key := range before.Keys() {
+		path := prefix + "/" + escapePointerSegment(key)
+		afterProp, ok := after.mapping[key]
+		if !ok {
+			patch = append(patch, Change{Kind: Removed, Path: path, Before: before.mapping[key]})
+			continue
+		}
+		patch = append(patch, diffProperties(path, before.mapping[key], afterProp)...)
+	}
+
+	for _, key := range after.Keys() {
+		if _, ok := before.mapping[key]; !ok {
+			patch = append(patch, Change{Kind: Added, Path: prefix + "/" + escapePointerSegment(key), After: after.mapping[key]})
+		}
+	}
+
+	return patch
+}
+
+// Diff lists every path that differs between the two blocks, stepping into
+// nested blocks and arrays so only the properties that actually changed are
+// reported. Applying the patch returned to a results in b.
+func Diff(a, b Block) Patch {
+	return diffBlocks("", a, b)
+}
+
+func checkChangeTarget(change Change, current Property, exists bool) error {
+	if change.Kind == Added {
+		if exists {
+			return fmt.Errorf("%s: can not add a path that already exists", change.Path)
+		}
+		return nil
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: path does not exist", change.Path)
+	}
+
+	if !propertiesEqual(current, change.Before) {
+		return fmt.Errorf("%s: patch expected %s but found %s", change.Path, change.Before.String(), current.String())
+	}
+	return nil
+}
+
+func applyChange(container Property, segments []string, change Change) (Property, error) {
+	segment, last := segments[0], len(segments) == 1
+
+	switch c := container.(type) {
+	case MetadataProperty:
+		current, exists := c.block.mapping[segment]
+
+		mapping := make(map[string]Property, len(c.block.mapping)+1)
+		for key, prop := range c.block.mapping {
+			mapping[key] = prop
+		}
+
+		if !last {
+			if !exists {
+				return nil, fmt.Errorf("%s: path does not exist", change.Path)
+			}
+
+			child, err := applyChange(current, segments[1:], change)
+			if err != nil {
+				return nil, err
+			}
+			mapping[segment] = child
+			return NewMetadataProperty(NewBlock(mapping)), nil
+		}
+
+		if err := checkChangeTarget(change, current, exists); err != nil {
+			return nil, err
+		}
+
+		if change.Kind == Removed {
+			delete(mapping, segment)
+		} else {
+			mapping[segment] = change.After
+		}
+		return NewMetadataProperty(NewBlock(mapping)), nil
+
+	case ArrayProperty:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index > len(c.props) {
+			return nil, fmt.Errorf("%s: invalid array index '%s'", change.Path, segment)
+		}
+
+		exists := index < len(c.props)
+		var current Property
+		if exists {
+			current = c.props[index]
+		}
+
+		props := make([]Property, 0, len(c.props)+1)
+		props = append(props, c.props...)
+
+		if !last {
+			if !exists {
+				return nil, fmt.Errorf("%s: path does not exist", change.Path)
+			}
+
+			props[index], err = applyChange(current, segments[1:], change)
+			if err != nil {
+				return nil, err
+			}
+			return newArrayProperty(c.originalBaseCode, props), nil
+		}
+
+		// Anywhere but the end of the array, an index being added to shifts
+		// the existing element up rather than clashing with it
+		if change.Kind == Added {
+			exists = false
+		}
+
+		if err := checkChangeTarget(change, current, exists); err != nil {
+			return nil, err
+		}
+
+		if change.Kind != Removed && change.After.Code() != c.originalBaseCode {
+			return nil, fmt.Errorf("%s: array of type %d can not hold type %d", change.Path, c.originalBaseCode, change.After.Code())
+		}
+
+		switch change.Kind {
+		case Added:
+			props = append(props[:index], append([]Property{change.After}, props[index:]...)...)
+		case Removed:
+			props = append(props[:index], props[index+1:]...)
+		default:
+			props[index] = change.After
+		}
+		return newArrayProperty(c.originalBaseCode, props), nil
+	}
+
+	return nil, fmt.Errorf("%s: can not step into %s", change.Path, TypeOf(container))
+}
+
+// Apply returns a copy of the block with every change of the patch made to
+// it, in order. The block is left untouched. Changes and removals must find
+// the value they expect to be replacing, so a patch that no longer lines up
+// with the block errors rather than overwriting something else.
+func (m Block) Apply(patch Patch) (Block, error) {
+	root := Property(NewMetadataProperty(m))
+	for _, change := range patch {
+		if len(change.Path) < 2 || change.Path[0] != '/' {
+			return m, fmt.Errorf("%s: change path must be a JSON pointer", change.Path)
+		}
+
+		var err error
+		root, err = applyChange(root, splitPath(change.Path), change)
+		if err != nil {
+			return m, err
+		}
+	}
+	return root.(MetadataProperty).Block(), nil
+}
+
+// Conflict is a path that was changed differently on both sides of a three
+// way merge.
+type Conflict struct {
+	// Path is a JSON pointer to the property in conflict
+	Path string
+
+	// Base, Ours and Theirs hold the property's value in each block, nil
+	// where the block doesn't contain the path
+	Base   Property
+	Ours   Property
+	Theirs Property
+}
+
+func (c Conflict) String() string {
+	describe := func(prop Property) string {
+		if prop == nil {
+			return "<missing>"
+		}
+		return prop.String()
+	}
+	return fmt.Sprintf("%s: ours %s, theirs %s", c.Path, describe(c.Ours), describe(c.Theirs))
+}
+
+func mergeProperties(path string, base, ours, theirs Property) (Property, []Conflict) {
+	switch {
+	case propertiesEqual(ours, theirs):
+		return ours, nil
+	case propertiesEqual(base, ours):
+		return theirs, nil
+	case propertiesEqual(base, theirs):
+		return ours, nil
+	}
+
+	oursBlock, oursIsBlock := ours.(MetadataProperty)
+	theirsBlock, theirsIsBlock := theirs.(MetadataProperty)
+	if oursIsBlock && theirsIsBlock {
+		baseBlock, baseIsBlock := base.(MetadataProperty)
+		if !baseIsBlock {
+			baseBlock = NewMetadataProperty(EmptyBlock())
+		}
+		merged, conflicts := mergeBlocks(path, baseBlock.Block(), oursBlock.Block(), theirsBlock.Block())
+		return NewMetadataProperty(merged), conflicts
+	}
+
+	baseArr, baseIsArr := base.(ArrayProperty)
+	oursArr, oursIsArr := ours.(ArrayProperty)
+	theirsArr, theirsIsArr := theirs.(ArrayProperty)
+	if baseIsArr && oursIsArr && theirsIsArr &&
+		baseArr.Code() == oursArr.Code() && oursArr.Code() == theirsArr.Code() &&
+		len(baseArr.props) == len(oursArr.props) && len(oursArr.props) == len(theirsArr.props) {
+		props := make([]Property, len(baseArr.props))
+		conflicts := make([]Conflict, 0)
+		for i := range props {
+			var elementConflicts []Conflict
+			props[i], elementConflicts = mergeProperties(fmt.Sprintf("%s/%d", path, i), baseArr.props[i], oursArr.props[i], theirsArr.props[i])
+			conflicts = append(conflicts, elementConflicts...)
+		}
+		return newArrayProperty(oursArr.originalBaseCode, props), conflicts
+	}
+
+	return ours, []Conflict{{Path: path, Base: base, Ours: ours, Theirs: theirs}}
+}
+
+func mergeBlocks(prefix string, base, ours, theirs Block) (Block, []Conflict) {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, block := range []Block{base, ours, theirs} {
+		for key := range block.mapping {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	mapping := make(map[string]Property, len(keys))
+	conflicts := make([]Conflict, 0)
+	for _, key := range keys {
+		merged, keyConflicts := mergeProperties(
+			prefix+"/"+escapePointerSegment(key),
+			base.mapping[key],
+			ours.mapping[key],
+			theirs.mapping[key],
+		)
+		conflicts = append(conflicts, keyConflicts...)
+		if merged != nil {
+			mapping[key] = merged
+		}
+	}
+
+	return NewBlock(mapping), conflicts
+}
+
+// Merge performs a three way merge, combining the changes ours and theirs
+// each made to base. Nested blocks, and arrays whose length nobody changed,
+// are merged path by path. Paths both sides changed differently are reported
+// as conflicts and keep our value in the resulting block.
+func Merge(base, ours, theirs Block) (Block, []Conflict) {
+	return mergeBlocks("", base, ours, theirs)
+}
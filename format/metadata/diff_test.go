@@ -0,0 +1,217 @@
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func session() metadata.Block {
+	return metadata.NewBlock(map[string]metadata.Property{
+		"level": metadata.NewStringProperty("castle"),
+		"score": metadata.NewIntProperty(10),
+		"player": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name": metadata.NewStringProperty("eli"),
+			"tags": metadata.NewStringArrayProperty([]string{"a", "b", "c"}),
+		})),
+	})
+}
+
+func Test_Diff(t *testing.T) {
+	// ARRANGE ================================================================
+	before := session()
+	after := metadata.BuilderFrom(before).
+		Without("score").
+		With("mode", metadata.NewStringProperty("coop")).
+		With("player", metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name":   metadata.NewStringProperty("eli"),
+			"tags":   metadata.NewStringArrayProperty([]string{"a", "x"}),
+			"a/b~c":  metadata.NewBoolProperty(true),
+			"health": metadata.NewFloat32Property(1),
+		}))).
+		Build()
+
+	// ACT ====================================================================
+	patch := metadata.Diff(before, after)
+
+	// ASSERT =================================================================
+	assert.Equal(t, metadata.Patch{
+		{Kind: metadata.Changed, Path: "/player/tags/1", Before: metadata.NewStringProperty("b"), After: metadata.NewStringProperty("x")},
+		{Kind: metadata.Removed, Path: "/player/tags/2", Before: metadata.NewStringProperty("c")},
+		{Kind: metadata.Added, Path: "/player/a~1b~0c", After: metadata.NewBoolProperty(true)},
+		{Kind: metadata.Added, Path: "/player/health", After: metadata.NewFloat32Property(1)},
+		{Kind: metadata.Removed, Path: "/score", Before: metadata.NewIntProperty(10)},
+		{Kind: metadata.Added, Path: "/mode", After: metadata.NewStringProperty("coop")},
+	}, patch)
+	assert.Equal(t, "~ /player/tags/1: b -> x", patch[0].String())
+	assert.Equal(t, "- /score: 10", patch[4].String())
+	assert.Equal(t, "+ /mode: coop", patch[5].String())
+}
+
+func Test_Diff_Identical(t *testing.T) {
+	assert.Empty(t, metadata.Diff(session(), session()))
+}
+
+func Test_Diff_TypeChangeIsWholeValue(t *testing.T) {
+	// ARRANGE ================================================================
+	before := metadata.NewBlock(map[string]metadata.Property{
+		"values": metadata.NewIntArrayProperty([]int{1, 2}),
+	})
+	after := metadata.NewBlock(map[string]metadata.Property{
+		"values": metadata.NewFloat32ArrayProperty([]float32{1, 2}),
+	})
+
+	// ACT ====================================================================
+	patch := metadata.Diff(before, after)
+
+	// ASSERT =================================================================
+	if assert.Len(t, patch, 1) {
+		assert.Equal(t, metadata.Changed, patch[0].Kind)
+		assert.Equal(t, "/values", patch[0].Path)
+	}
+}
+
+func Test_Apply_RoundTripsDiff(t *testing.T) {
+	// ARRANGE ================================================================
+	before := session()
+	after := metadata.BuilderFrom(before).
+		Without("level").
+		With("player", metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name": metadata.NewStringProperty("eli"),
+			"tags": metadata.NewStringArrayProperty([]string{"z"}),
+		}))).
+		With("extra", metadata.NewStringArrayProperty([]string{"1", "2"})).
+		Build()
+
+	// ACT ====================================================================
+	patched, err := before.Apply(metadata.Diff(before, after))
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Empty(t, metadata.Diff(patched, after))
+	assert.Equal(t, session(), before, "original block should be left alone")
+}
+
+func Test_Apply_GrowsArrays(t *testing.T) {
+	// ARRANGE ================================================================
+	before := metadata.NewBlock(map[string]metadata.Property{
+		"tags": metadata.NewStringArrayProperty([]string{"a"}),
+	})
+	after := metadata.NewBlock(map[string]metadata.Property{
+		"tags": metadata.NewStringArrayProperty([]string{"a", "b", "c"}),
+	})
+
+	// ACT ====================================================================
+	patched, err := before.Apply(metadata.Diff(before, after))
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, after, patched)
+}
+
+func Test_Apply_Errors(t *testing.T) {
+	tests := map[string]struct {
+		change metadata.Change
+		err    string
+	}{
+		"stale value": {
+			change: metadata.Change{Kind: metadata.Changed, Path: "/score", Before: metadata.NewIntProperty(11), After: metadata.NewIntProperty(12)},
+			err:    "/score: patch expected 11 but found 10",
+		},
+		"missing path": {
+			change: metadata.Change{Kind: metadata.Removed, Path: "/nope/deeper", Before: metadata.NewIntProperty(1)},
+			err:    "/nope/deeper: path does not exist",
+		},
+		"already exists": {
+			change: metadata.Change{Kind: metadata.Added, Path: "/level", After: metadata.NewStringProperty("forest")},
+			err:    "/level: can not add a path that already exists",
+		},
+		"wrong array type": {
+			change: metadata.Change{Kind: metadata.Added, Path: "/player/tags/3", After: metadata.NewIntProperty(1)},
+			err:    "/player/tags/3: array of type 0 can not hold type 1",
+		},
+		"bad index": {
+			change: metadata.Change{Kind: metadata.Added, Path: "/player/tags/9", After: metadata.NewStringProperty("d")},
+			err:    "/player/tags/9: invalid array index '9'",
+		},
+		"step into leaf": {
+			change: metadata.Change{Kind: metadata.Added, Path: "/level/inner", After: metadata.NewStringProperty("d")},
+			err:    "/level/inner: can not step into string",
+		},
+		"not a pointer": {
+			change: metadata.Change{Kind: metadata.Added, Path: "level", After: metadata.NewStringProperty("d")},
+			err:    "level: change path must be a JSON pointer",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			block := session()
+			patched, err := block.Apply(metadata.Patch{tc.change})
+			assert.EqualError(t, err, tc.err)
+			assert.Equal(t, block, patched)
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	// ARRANGE ================================================================
+	base := session()
+	ours := metadata.BuilderFrom(base).
+		With("score", metadata.NewIntProperty(20)).
+		With("player", metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name": metadata.NewStringProperty("eli"),
+			"tags": metadata.NewStringArrayProperty([]string{"a", "ours", "c"}),
+		}))).
+		Build()
+	theirs := metadata.BuilderFrom(base).
+		Without("level").
+		With("server", metadata.NewStringProperty("us-east")).
+		With("player", metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name": metadata.NewStringProperty("eli davis"),
+			"tags": metadata.NewStringArrayProperty([]string{"theirs", "b", "c"}),
+		}))).
+		Build()
+
+	// ACT ====================================================================
+	merged, conflicts := metadata.Merge(base, ours, theirs)
+
+	// ASSERT =================================================================
+	assert.Empty(t, conflicts)
+	assert.Equal(t, metadata.NewBlock(map[string]metadata.Property{
+		"score":  metadata.NewIntProperty(20),
+		"server": metadata.NewStringProperty("us-east"),
+		"player": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"name": metadata.NewStringProperty("eli davis"),
+			"tags": metadata.NewStringArrayProperty([]string{"theirs", "ours", "c"}),
+		})),
+	}), merged)
+}
+
+func Test_Merge_Conflicts(t *testing.T) {
+	// ARRANGE ================================================================
+	base := session()
+	ours := metadata.BuilderFrom(base).
+		With("score", metadata.NewIntProperty(20)).
+		Without("level").
+		Build()
+	theirs := metadata.BuilderFrom(base).
+		With("score", metadata.NewIntProperty(30)).
+		With("level", metadata.NewStringProperty("forest")).
+		Build()
+
+	// ACT ====================================================================
+	merged, conflicts := metadata.Merge(base, ours, theirs)
+
+	// ASSERT =================================================================
+	assert.Equal(t, []metadata.Conflict{
+		{Path: "/level", Base: metadata.NewStringProperty("castle"), Theirs: metadata.NewStringProperty("forest")},
+		{Path: "/score", Base: metadata.NewIntProperty(10), Ours: metadata.NewIntProperty(20), Theirs: metadata.NewIntProperty(30)},
+	}, conflicts)
+	assert.Equal(t, "/level: ours <missing>, theirs forest", conflicts[0].String())
+
+	_, hasLevel := merged.Mapping()["level"]
+	assert.False(t, hasLevel)
+	assert.Equal(t, metadata.NewIntProperty(20), merged.Mapping()["score"])
+}
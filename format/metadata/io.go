@@ -99,6 +99,30 @@ func readPropData(b io.Reader, propertyType byte) (Property, error) {
 			return nil, err
 		}
 		return NewVector3Property(vals[0], vals[1], vals[2]), nil
+	case 8:
+		var int64Val int64
+		err := binary.Read(b, binary.LittleEndian, &int64Val)
+		if err != nil {
+			return nil, err
+		}
+		return NewInt64Property(int64Val), nil
+
+	case 9:
+		var float64Val float64
+		err := binary.Read(b, binary.LittleEndian, &float64Val)
+		if err != nil {
+			return nil, err
+		}
+		return NewFloat64Property(float64Val), nil
+
+	case 10:
+		var uint64Val uint64
+		err := binary.Read(b, binary.LittleEndian, &uint64Val)
+		if err != nil {
+			return nil, err
+		}
+		return NewUintProperty(uint64Val), nil
+
 	case 11:
 		metadataBlock, err := readNestedMetadatablock(b)
 		if err != nil {
@@ -114,6 +138,9 @@ func readPropData(b io.Reader, propertyType byte) (Property, error) {
 		}
 		return NewTimeProperty(time.UnixMicro(unixTimeMicro)), nil
 
+	case 26:
+		return NewNullProperty(), nil
+
 	case 16:
 		allbytes, _, err := rapbin.ReadBytesArray(b)
 		if err != nil {
@@ -140,6 +167,12 @@ func readPropData(b io.Reader, propertyType byte) (Property, error) {
 		fallthrough
 	case 20:
 		fallthrough
+	case 21:
+		fallthrough
+	case 22:
+		fallthrough
+	case 23:
+		fallthrough
 	case 24:
 		fallthrough
 	case 25:
@@ -23,6 +23,10 @@ func Test_BasicIO(t *testing.T) {
 		"byte test":     metadata.NewByteProperty(22),
 		"vec2 test":     metadata.NewVector2Property(1.2, 3.4),
 		"vec3 test":     metadata.NewVector3Property(1.2, 3.4, 5.6),
+		"int64":         metadata.NewInt64Property(-1634567890123),
+		"float64":       metadata.NewFloat64Property(3.141592653589793),
+		"uint":          metadata.NewUintProperty(18446744073709551615),
+		"null":          metadata.NewNullProperty(),
 		"time":          metadata.NewTimeProperty(time.Date(1, time.February, 3, 4, 5, 6, 7, time.UTC)),
 		"block test": metadata.NewMetadataProperty(metadata.NewBlock(
 			map[string]metadata.Property{
@@ -32,12 +36,15 @@ func Test_BasicIO(t *testing.T) {
 				"nested prop time": metadata.NewTimeProperty(time.Now()),
 			},
 		)),
-		"String Array": metadata.NewStringArrayProperty([]string{"x", "y", "z"}),
-		"Int Array":    metadata.NewIntArrayProperty([]int{1, 2, 3, 4}),
-		"time array":   metadata.NewTimestampArrayProperty([]time.Time{time.Now(), time.Now().Add(1)}),
-		"float array":  metadata.NewFloat32ArrayProperty([]float32{1.2, 3.4}),
-		"vec2 array":   metadata.NewVector2ArrayProperty([]vector.Vector2{vector.NewVector2(1, 2), vector.NewVector2(3, 4)}),
-		"vec3 array":   metadata.NewVector3ArrayProperty([]vector.Vector3{vector.NewVector3(1, 2, 3), vector.NewVector3(4, 5, 6)}),
+		"String Array":  metadata.NewStringArrayProperty([]string{"x", "y", "z"}),
+		"Int Array":     metadata.NewIntArrayProperty([]int{1, 2, 3, 4}),
+		"time array":    metadata.NewTimestampArrayProperty([]time.Time{time.Now(), time.Now().Add(1)}),
+		"float array":   metadata.NewFloat32ArrayProperty([]float32{1.2, 3.4}),
+		"int64 array":   metadata.NewInt64ArrayProperty([]int64{1634567890123, -1}),
+		"float64 array": metadata.NewFloat64ArrayProperty([]float64{3.141592653589793, 2}),
+		"uint array":    metadata.NewUintArrayProperty([]uint64{18446744073709551615, 0}),
+		"vec2 array":    metadata.NewVector2ArrayProperty([]vector.Vector2{vector.NewVector2(1, 2), vector.NewVector2(3, 4)}),
+		"vec3 array":    metadata.NewVector3ArrayProperty([]vector.Vector3{vector.NewVector3(1, 2, 3), vector.NewVector3(4, 5, 6)}),
 		"metadata array": metadata.NewMetadataArrayProperty([]metadata.Block{
 			metadata.EmptyBlock(),
 			metadata.NewBlock(map[string]metadata.Property{
@@ -1,5 +1,7 @@
 package metadata
 
+import "sort"
+
 type Block struct {
 	mapping map[string]Property
 }
@@ -15,3 +17,14 @@ func EmptyBlock() Block {
 func (m Block) Mapping() map[string]Property {
 	return m.mapping
 }
+
+// Keys returns all keys within the block in sorted order, for when the block
+// needs to be iterated the same way every time.
+func (m Block) Keys() []string {
+	keys := make([]string, 0, len(m.mapping))
+	for key := range m.mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
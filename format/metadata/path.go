@@ -0,0 +1,197 @@
+package metadata
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EliCDavis/vector"
+)
+
+// splitPath breaks a path into the keys and indices it's made of. Paths
+// starting with "/" are treated as JSON pointers (RFC 6901), anything else is
+// split on ".".
+func splitPath(path string) []string {
+	if strings.HasPrefix(path, "/") {
+		segments := strings.Split(path[1:], "/")
+		for i, segment := range segments {
+			segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		}
+		return segments
+	}
+	return strings.Split(path, ".")
+}
+
+func arrayElements(prop Property) ([]Property, bool) {
+	switch arr := prop.(type) {
+	case ArrayProperty:
+		return arr.Properties(), true
+	case ArrayPropertyRaw:
+		return arr.Properties(), true
+	}
+	return nil, false
+}
+
+// Get looks up the property found at the path provided. Nested blocks are
+// stepped into by key and arrays by index, so both "player.scores.0" and
+// "/player/scores/0" resolve to the first score of the player block. A key
+// that exists in the block exactly as written takes priority over treating
+// the path as nested.
+func (m Block) Get(path string) (Property, bool) {
+	if prop, ok := m.mapping[path]; ok {
+		return prop, true
+	}
+
+	segments := splitPath(path)
+	current, ok := m.mapping[segments[0]]
+	if !ok {
+		return nil, false
+	}
+
+	for _, segment := range segments[1:] {
+		if nested, isBlock := current.(MetadataProperty); isBlock {
+			current, ok = nested.Block().mapping[segment]
+			if !ok {
+				return nil, false
+			}
+			continue
+		}
+
+		elements, isArray := arrayElements(current)
+		if !isArray {
+			return nil, false
+		}
+
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index >= len(elements) {
+			return nil, false
+		}
+		current = elements[index]
+	}
+
+	return current, true
+}
+
+// GetString returns the string found at the path.
+func (m Block) GetString(path string) (string, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return "", false
+	}
+	str, ok := prop.(StringProperty)
+	return str.String(), ok
+}
+
+// GetInt returns the integer found at the path. Int32, Int64, Byte and Uint
+// properties are all accepted, so long as the value fits in an int64.
+func (m Block) GetInt(path string) (int64, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	switch p := prop.(type) {
+	case Int32Property:
+		return int64(p.Value()), true
+	case Int64Property:
+		return p.Value(), true
+	case ByteProperty:
+		return int64(p.Value()), true
+	case UintProperty:
+		if p.Value() <= math.MaxInt64 {
+			return int64(p.Value()), true
+		}
+	}
+	return 0, false
+}
+
+// GetFloat returns the number found at the path as a float64. Integer
+// properties are accepted as well as floating point ones.
+func (m Block) GetFloat(path string) (float64, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return 0, false
+	}
+	return numericValue(prop)
+}
+
+func numericValue(prop Property) (float64, bool) {
+	switch p := prop.(type) {
+	case Float32Property:
+		return float64(p.Value()), true
+	case Float64Property:
+		return p.Value(), true
+	case Int32Property:
+		return float64(p.Value()), true
+	case Int64Property:
+		return float64(p.Value()), true
+	case UintProperty:
+		return float64(p.Value()), true
+	case ByteProperty:
+		return float64(p.Value()), true
+	}
+	return 0, false
+}
+
+// GetBool returns the bool found at the path.
+func (m Block) GetBool(path string) (bool, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return false, false
+	}
+	b, ok := prop.(BoolProperty)
+	return b.Value(), ok
+}
+
+// GetTime returns the time found at the path.
+func (m Block) GetTime(path string) (time.Time, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, ok := prop.(TimeProperty)
+	return t.Value(), ok
+}
+
+// GetVector2 returns the vector2 found at the path.
+func (m Block) GetVector2(path string) (vector.Vector2, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return vector.Vector2{}, false
+	}
+	v, ok := prop.(Vector2Property)
+	return v.Value(), ok
+}
+
+// GetVector3 returns the vector3 found at the path.
+func (m Block) GetVector3(path string) (vector.Vector3, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return vector.Vector3{}, false
+	}
+	v, ok := prop.(Vector3Property)
+	return v.Value(), ok
+}
+
+// GetBlock returns the nested block found at the path.
+func (m Block) GetBlock(path string) (Block, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return EmptyBlock(), false
+	}
+	mp, ok := prop.(MetadataProperty)
+	if !ok {
+		return EmptyBlock(), false
+	}
+	return mp.Block(), true
+}
+
+// GetArray returns the elements of the array found at the path.
+func (m Block) GetArray(path string) ([]Property, bool) {
+	prop, ok := m.Get(path)
+	if !ok {
+		return nil, false
+	}
+	return arrayElements(prop)
+}
@@ -0,0 +1,115 @@
+package metadata_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildPathTestBlock() metadata.Block {
+	return metadata.NewBlock(map[string]metadata.Property{
+		"name":       metadata.NewStringProperty("Eli"),
+		"dotted.key": metadata.NewStringProperty("literal"),
+		"score":      metadata.NewIntProperty(12),
+		"steam id":   metadata.NewUintProperty(76561197960287930),
+		"ratio":      metadata.NewFloat32Property(0.5),
+		"alive":      metadata.NewBoolProperty(true),
+		"born":       metadata.NewTimeProperty(time.Unix(1234567, 0)),
+		"spawn":      metadata.NewVector3Property(1, 2, 3),
+		"look":       metadata.NewVector2Property(4, 5),
+		"flags":      metadata.NewBoolArrayProperty([]bool{false, true}),
+		"player": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"a/b":    metadata.NewStringProperty("slash"),
+			"scores": metadata.NewIntArrayProperty([]int{7, 8, 9}),
+			"items": metadata.NewMetadataArrayProperty([]metadata.Block{
+				metadata.NewBlock(map[string]metadata.Property{
+					"kind": metadata.NewStringProperty("sword"),
+				}),
+			}),
+		})),
+	})
+}
+
+func Test_Block_Get(t *testing.T) {
+	block := buildPathTestBlock()
+
+	tests := map[string]struct {
+		path     string
+		expected metadata.Property
+	}{
+		"top level":            {path: "name", expected: metadata.NewStringProperty("Eli")},
+		"literal dotted key":   {path: "dotted.key", expected: metadata.NewStringProperty("literal")},
+		"nested dotted":        {path: "player.scores.1", expected: metadata.NewIntProperty(8)},
+		"nested pointer":       {path: "/player/scores/2", expected: metadata.NewIntProperty(9)},
+		"pointer escape":       {path: "/player/a~1b", expected: metadata.NewStringProperty("slash")},
+		"block in array":       {path: "player.items.0.kind", expected: metadata.NewStringProperty("sword")},
+		"raw array":            {path: "flags.1", expected: metadata.NewBoolProperty(true)},
+		"missing":              {path: "nope", expected: nil},
+		"missing nested":       {path: "player.nope", expected: nil},
+		"index out of range":   {path: "player.scores.3", expected: nil},
+		"negative index":       {path: "player.scores.-1", expected: nil},
+		"index not a number":   {path: "player.scores.x", expected: nil},
+		"stepping into scalar": {path: "name.first", expected: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prop, ok := block.Get(tc.path)
+			assert.Equal(t, tc.expected != nil, ok)
+			assert.Equal(t, tc.expected, prop)
+		})
+	}
+}
+
+func Test_Block_TypedGetters(t *testing.T) {
+	// ARRANGE ================================================================
+	block := buildPathTestBlock()
+
+	// ACT ====================================================================
+	name, nameOk := block.GetString("name")
+	score, scoreOk := block.GetInt("player.scores.0")
+	steamID, steamIDOk := block.GetInt("steam id")
+	ratio, ratioOk := block.GetFloat("ratio")
+	scoreAsFloat, scoreAsFloatOk := block.GetFloat("score")
+	alive, aliveOk := block.GetBool("alive")
+	born, bornOk := block.GetTime("born")
+	spawn, spawnOk := block.GetVector3("spawn")
+	look, lookOk := block.GetVector2("look")
+	player, playerOk := block.GetBlock("player")
+	scores, scoresOk := block.GetArray("/player/scores")
+
+	_, wrongTypeOk := block.GetString("score")
+	_, wrongIntOk := block.GetInt("name")
+	_, wrongBlockOk := block.GetBlock("name")
+
+	// ASSERT =================================================================
+	assert.True(t, nameOk)
+	assert.Equal(t, "Eli", name)
+	assert.True(t, scoreOk)
+	assert.Equal(t, int64(7), score)
+	assert.True(t, steamIDOk)
+	assert.Equal(t, int64(76561197960287930), steamID)
+	assert.True(t, ratioOk)
+	assert.Equal(t, 0.5, ratio)
+	assert.True(t, scoreAsFloatOk)
+	assert.Equal(t, 12., scoreAsFloat)
+	assert.True(t, aliveOk)
+	assert.True(t, alive)
+	assert.True(t, bornOk)
+	assert.Equal(t, time.Unix(1234567, 0).UnixNano(), born.UnixNano())
+	assert.True(t, spawnOk)
+	assert.Equal(t, vector.NewVector3(1, 2, 3), spawn)
+	assert.True(t, lookOk)
+	assert.Equal(t, vector.NewVector2(4, 5), look)
+	assert.True(t, playerOk)
+	assert.Len(t, player.Mapping(), 3)
+	assert.True(t, scoresOk)
+	assert.Len(t, scores, 3)
+
+	assert.False(t, wrongTypeOk)
+	assert.False(t, wrongIntOk)
+	assert.False(t, wrongBlockOk)
+}
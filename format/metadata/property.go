@@ -7,12 +7,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/EliCDavis/vector"
 	rapbin "github.com/recolude/rap/internal/io/binary"
@@ -20,6 +22,24 @@ import (
 
 const HEX_PREFIX = "0x"
 
+// Codes identifying each property type when written to a stream
+const (
+	StringCode   byte = 0
+	Int32Code    byte = 1
+	Float32Code  byte = 2
+	TrueCode     byte = 3
+	FalseCode    byte = 4
+	ByteCode     byte = 5
+	Vector2Code  byte = 6
+	Vector3Code  byte = 7
+	Int64Code    byte = 8
+	Float64Code  byte = 9
+	UintCode     byte = 10
+	MetadataCode byte = 11
+	TimeCode     byte = 12
+	NullCode     byte = 26
+)
+
 type Property interface {
 	Code() byte
 	String() string
@@ -38,7 +58,7 @@ func NewStringProperty(str string) StringProperty {
 }
 
 func (sp StringProperty) Code() byte {
-	return 0
+	return StringCode
 }
 
 func (sp StringProperty) String() string {
@@ -66,6 +86,15 @@ func (sp StringProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(sp.str)
 }
 
+func (sp *StringProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	str, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return bsonTypeError("string property", t)
+	}
+	sp.str = str
+	return nil
+}
+
 // INT32 ======================================================================
 type Int32Property struct {
 	i int32
@@ -78,7 +107,7 @@ func NewIntProperty(i int) Int32Property {
 }
 
 func (ip Int32Property) Code() byte {
-	return 1
+	return Int32Code
 }
 
 func (ip Int32Property) String() string {
@@ -91,6 +120,10 @@ func (ip Int32Property) Data() []byte {
 	return buf.Bytes()
 }
 
+func (ip Int32Property) Value() int32 {
+	return ip.i
+}
+
 func UnmarshalNewInt32Property(b []byte) (Int32Property, error) {
 	var p Int32Property
 	err := json.Unmarshal(b, &p)
@@ -119,6 +152,15 @@ func (ip Int32Property) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(ip.i)
 }
 
+func (ip *Int32Property) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	i, ok := bsonInt64(bson.RawValue{Type: t, Value: data})
+	if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+		return bsonTypeError("int32 property", t)
+	}
+	ip.i = int32(i)
+	return nil
+}
+
 // FLOAT32 ====================================================================
 type Float32Property struct {
 	f float32
@@ -131,7 +173,7 @@ func NewFloat32Property(f float32) Float32Property {
 }
 
 func (fp Float32Property) Code() byte {
-	return 2
+	return Float32Code
 }
 
 func (fp Float32Property) String() string {
@@ -144,6 +186,10 @@ func (fp Float32Property) Data() []byte {
 	return buf.Bytes()
 }
 
+func (fp Float32Property) Value() float32 {
+	return fp.f
+}
+
 func UnmarshalNewFloat32Property(b []byte) (Float32Property, error) {
 	var p Float32Property
 	err := json.Unmarshal(b, &p)
@@ -172,6 +218,209 @@ func (fp Float32Property) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(fp.f)
 }
 
+func (fp *Float32Property) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	f, ok := bsonFloat64(bson.RawValue{Type: t, Value: data})
+	if !ok {
+		return bsonTypeError("float32 property", t)
+	}
+	fp.f = float32(f)
+	return nil
+}
+
+// INT64 ======================================================================
+type Int64Property struct {
+	i int64
+}
+
+func NewInt64Property(i int64) Int64Property {
+	return Int64Property{
+		i: i,
+	}
+}
+
+func (ip Int64Property) Code() byte {
+	return Int64Code
+}
+
+func (ip Int64Property) String() string {
+	return fmt.Sprintf("%d", ip.i)
+}
+
+func (ip Int64Property) Data() []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, ip.i)
+	return buf.Bytes()
+}
+
+func (ip Int64Property) Value() int64 {
+	return ip.i
+}
+
+func UnmarshalNewInt64Property(b []byte) (Int64Property, error) {
+	var p Int64Property
+	err := json.Unmarshal(b, &p)
+	return p, err
+}
+
+func (ip *Int64Property) UnmarshalJSON(b []byte) error {
+	var data int64
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	ip.i = data
+	return nil
+}
+
+func (ip Int64Property) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ip.i)
+}
+
+func (ip Int64Property) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(ip.i)
+}
+
+func (ip *Int64Property) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	i, ok := bsonInt64(bson.RawValue{Type: t, Value: data})
+	if !ok {
+		return bsonTypeError("int64 property", t)
+	}
+	ip.i = i
+	return nil
+}
+
+// FLOAT64 ====================================================================
+type Float64Property struct {
+	f float64
+}
+
+func NewFloat64Property(f float64) Float64Property {
+	return Float64Property{
+		f: f,
+	}
+}
+
+func (fp Float64Property) Code() byte {
+	return Float64Code
+}
+
+func (fp Float64Property) String() string {
+	return fmt.Sprintf("%f", fp.f)
+}
+
+func (fp Float64Property) Data() []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, fp.f)
+	return buf.Bytes()
+}
+
+func (fp Float64Property) Value() float64 {
+	return fp.f
+}
+
+func UnmarshalNewFloat64Property(b []byte) (Float64Property, error) {
+	var p Float64Property
+	err := json.Unmarshal(b, &p)
+	return p, err
+}
+
+func (fp *Float64Property) UnmarshalJSON(b []byte) error {
+	var data float64
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	fp.f = data
+	return nil
+}
+
+func (fp Float64Property) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fp.f)
+}
+
+func (fp Float64Property) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(fp.f)
+}
+
+func (fp *Float64Property) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	f, ok := bsonFloat64(bson.RawValue{Type: t, Value: data})
+	if !ok {
+		return bsonTypeError("float64 property", t)
+	}
+	fp.f = f
+	return nil
+}
+
+// UINT =======================================================================
+type UintProperty struct {
+	u uint64
+}
+
+func NewUintProperty(u uint64) UintProperty {
+	return UintProperty{
+		u: u,
+	}
+}
+
+func (up UintProperty) Code() byte {
+	return UintCode
+}
+
+func (up UintProperty) String() string {
+	return fmt.Sprintf("%d", up.u)
+}
+
+func (up UintProperty) Data() []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, up.u)
+	return buf.Bytes()
+}
+
+func (up UintProperty) Value() uint64 {
+	return up.u
+}
+
+func UnmarshalNewUintProperty(b []byte) (UintProperty, error) {
+	var p UintProperty
+	err := json.Unmarshal(b, &p)
+	return p, err
+}
+
+func (up *UintProperty) UnmarshalJSON(b []byte) error {
+	var data uint64
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	up.u = data
+	return nil
+}
+
+func (up UintProperty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(up.u)
+}
+
+// MarshalBSONValue writes the value as an int64 whenever it fits, as BSON has
+// no unsigned 64 bit type. Larger values are written as a decimal128 to avoid
+// losing precision.
+func (up UintProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if up.u <= math.MaxInt64 {
+		return bson.MarshalValue(int64(up.u))
+	}
+
+	decimal, err := primitive.ParseDecimal128(strconv.FormatUint(up.u, 10))
+	if err != nil {
+		return bson.TypeUndefined, nil, err
+	}
+	return bson.MarshalValue(decimal)
+}
+
+func (up *UintProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	u, ok := bsonUint64(bson.RawValue{Type: t, Value: data})
+	if !ok {
+		return bsonTypeError("uint property", t)
+	}
+	up.u = u
+	return nil
+}
+
 // BOOL =======================================================================
 type BoolProperty struct {
 	b bool
@@ -185,9 +434,9 @@ func NewBoolProperty(b bool) BoolProperty {
 
 func (bp BoolProperty) Code() byte {
 	if bp.b {
-		return 3
+		return TrueCode
 	}
-	return 4
+	return FalseCode
 }
 
 func (bp BoolProperty) String() string {
@@ -233,6 +482,15 @@ func (bp BoolProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(bp.b)
 }
 
+func (bp *BoolProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	b, ok := bson.RawValue{Type: t, Value: data}.BooleanOK()
+	if !ok {
+		return bsonTypeError("bool property", t)
+	}
+	bp.b = b
+	return nil
+}
+
 // BYTE =======================================================================
 type ByteProperty struct {
 	b byte
@@ -245,7 +503,7 @@ func NewByteProperty(b byte) ByteProperty {
 }
 
 func (bp ByteProperty) Code() byte {
-	return 5
+	return ByteCode
 }
 
 func (bp ByteProperty) String() string {
@@ -292,6 +550,15 @@ func (bp ByteProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(bp.b) // should we store this as hex string?
 }
 
+func (bp *ByteProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	i, ok := bsonInt64(bson.RawValue{Type: t, Value: data})
+	if !ok || i < 0 || i > math.MaxUint8 {
+		return bsonTypeError("byte property", t)
+	}
+	bp.b = byte(i)
+	return nil
+}
+
 // VECTOR2 ====================================================================
 type Vector2Property struct {
 	x float64
@@ -306,7 +573,7 @@ func NewVector2Property(x, y float64) Vector2Property {
 }
 
 func (v2p Vector2Property) Code() byte {
-	return 6
+	return Vector2Code
 }
 
 func (v2p Vector2Property) String() string {
@@ -320,6 +587,10 @@ func (v2p Vector2Property) Data() []byte {
 	return buf.Bytes()
 }
 
+func (v2p Vector2Property) Value() vector.Vector2 {
+	return vector.NewVector2(v2p.x, v2p.y)
+}
+
 func UnmarshalNewVector2Property(b []byte) (Vector2Property, error) {
 	var p Vector2Property
 	err := json.Unmarshal(b, &p)
@@ -366,6 +637,16 @@ func (v2p Vector2Property) MarshalBSON() ([]byte, error) {
 	return bson.Marshal(data)
 }
 
+func (v2p *Vector2Property) UnmarshalBSON(data []byte) error {
+	v, ok := bsonVector(bson.Raw(data), "x", "y")
+	if !ok {
+		return errors.New("vector2 property requires a document of only numeric x and y")
+	}
+	v2p.x = v[0]
+	v2p.y = v[1]
+	return nil
+}
+
 // VECTOR3 ====================================================================
 type Vector3Property struct {
 	x float64
@@ -382,7 +663,7 @@ func NewVector3Property(x, y, z float64) Vector3Property {
 }
 
 func (v3p Vector3Property) Code() byte {
-	return 7
+	return Vector3Code
 }
 
 func (v3p Vector3Property) String() string {
@@ -397,6 +678,10 @@ func (v3p Vector3Property) Data() []byte {
 	return buf.Bytes()
 }
 
+func (v3p Vector3Property) Value() vector.Vector3 {
+	return vector.NewVector3(v3p.x, v3p.y, v3p.z)
+}
+
 func UnmarshalNewVector3Property(b []byte) (Vector3Property, error) {
 	var p Vector3Property
 	err := json.Unmarshal(b, &p)
@@ -448,6 +733,17 @@ func (v3p Vector3Property) MarshalBSON() ([]byte, error) {
 	return bson.Marshal(data)
 }
 
+func (v3p *Vector3Property) UnmarshalBSON(data []byte) error {
+	v, ok := bsonVector(bson.Raw(data), "x", "y", "z")
+	if !ok {
+		return errors.New("vector3 property requires a document of only numeric x, y and z")
+	}
+	v3p.x = v[0]
+	v3p.y = v[1]
+	v3p.z = v[2]
+	return nil
+}
+
 // METADATA ===================================================================
 type MetadataProperty struct {
 	block Block
@@ -462,15 +758,11 @@ func (mp MetadataProperty) Block() Block {
 }
 
 func (mp MetadataProperty) Code() byte {
-	return 11
+	return MetadataCode
 }
 
 func (mp MetadataProperty) String() string {
-	keys := make([]string, 0, len(mp.block.Mapping()))
-	for k := range mp.block.Mapping() {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
+	keys := mp.block.Keys()
 
 	out := strings.Builder{}
 	out.WriteString("{\n")
@@ -484,12 +776,7 @@ func (mp MetadataProperty) String() string {
 func (mp MetadataProperty) Data() []byte {
 	buf := new(bytes.Buffer)
 
-	i := 0
-	mappingWithIndex := make([]string, len(mp.block.Mapping()))
-	for key := range mp.block.Mapping() {
-		mappingWithIndex[i] = key
-		i++
-	}
+	mappingWithIndex := mp.block.Keys()
 
 	buf.Write(rapbin.StringArrayToBytes(mappingWithIndex))
 
@@ -513,7 +800,7 @@ func (mp MetadataProperty) UnmarshalProperty(data interface{}) error {
 
 func (mp *MetadataProperty) UnmarshalJSON(b []byte) error {
 	var data map[string]interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := unmarshalUsingNumbers(b, &data); err != nil {
 		return err
 	}
 	prop, err := getProperties(data)
@@ -533,7 +820,20 @@ func (mp MetadataProperty) MarshalJSON() ([]byte, error) {
 }
 
 func (mp MetadataProperty) MarshalBSON() ([]byte, error) {
-	return bson.Marshal(mp.block.Mapping())
+	doc := make(bson.D, 0, len(mp.block.Mapping()))
+	for _, key := range mp.block.Keys() {
+		doc = append(doc, bson.E{Key: key, Value: mp.block.Mapping()[key]})
+	}
+	return bson.Marshal(doc)
+}
+
+func (mp *MetadataProperty) UnmarshalBSON(data []byte) error {
+	block, err := bsonBlock(bson.Raw(data))
+	if err != nil {
+		return err
+	}
+	mp.block = block
+	return nil
 }
 
 // Time =======================================================================
@@ -548,7 +848,7 @@ func NewTimeProperty(t time.Time) TimeProperty {
 }
 
 func (tp TimeProperty) Code() byte {
-	return 12
+	return TimeCode
 }
 
 func (tp TimeProperty) String() string {
@@ -561,6 +861,10 @@ func (tp TimeProperty) Data() []byte {
 	return buf.Bytes()
 }
 
+func (tp TimeProperty) Value() time.Time {
+	return time.UnixMicro(tp.microseconds)
+}
+
 func UnmarshalNewTimeProperty(b []byte) (TimeProperty, error) {
 	var p TimeProperty
 	err := json.Unmarshal(b, &p)
@@ -593,6 +897,59 @@ func (tp TimeProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(time.Unix(0, tp.microseconds*1000))
 }
 
+func (tp *TimeProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	val, ok := bson.RawValue{Type: t, Value: data}.TimeOK()
+	if !ok {
+		return bsonTypeError("time property", t)
+	}
+	tp.microseconds = val.UnixMicro()
+	return nil
+}
+
+// NULL =======================================================================
+
+// NullProperty explicitly marks a key as having no value, as opposed to the
+// key being absent from the block entirely. Nulls can't be stored in arrays.
+type NullProperty struct{}
+
+func NewNullProperty() NullProperty {
+	return NullProperty{}
+}
+
+func (NullProperty) Code() byte {
+	return NullCode
+}
+
+func (NullProperty) String() string {
+	return "null"
+}
+
+func (NullProperty) Data() []byte {
+	return nil
+}
+
+func (np *NullProperty) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) != "null" {
+		return fmt.Errorf("null property can not be unmarshaled from %s", string(b))
+	}
+	return nil
+}
+
+func (NullProperty) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+func (NullProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.TypeNull, nil, nil
+}
+
+func (np *NullProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t != bson.TypeNull {
+		return bsonTypeError("null property", t)
+	}
+	return nil
+}
+
 // ARRAY =====================================================================
 type ArrayProperty struct {
 	originalBaseCode byte
@@ -606,6 +963,11 @@ func newArrayProperty(originalBaseCode byte, props []Property) ArrayProperty {
 	}
 }
 
+// Properties returns the elements of the array.
+func (ap ArrayProperty) Properties() []Property {
+	return ap.props
+}
+
 func (ap ArrayProperty) Code() byte {
 	return 13 + ap.originalBaseCode
 }
@@ -634,7 +996,7 @@ func (ap ArrayProperty) UnmarshalProperty(data interface{}) error {
 
 func (ap *ArrayProperty) UnmarshalJSON(b []byte) error {
 	var data []interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := unmarshalUsingNumbers(b, &data); err != nil {
 		return err
 	}
 	prop, err := getProperties(data)
@@ -658,6 +1020,23 @@ func (ap ArrayProperty) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.MarshalValue(ap.props)
 }
 
+func (ap *ArrayProperty) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t != bson.TypeArray {
+		return bsonTypeError("array property", t)
+	}
+	prop, err := bsonArray(bson.Raw(data))
+	if err != nil {
+		return err
+	}
+	newAp, ok := prop.(ArrayProperty)
+	if !ok {
+		return errors.New("unable to unmarshal ArrayProperty")
+	}
+	ap.originalBaseCode = newAp.originalBaseCode
+	ap.props = newAp.props
+	return nil
+}
+
 func NewStringArrayProperty(strs []string) ArrayProperty {
 	strProps := make([]Property, len(strs))
 	for i, str := range strs {
@@ -714,6 +1093,30 @@ func NewTimestampArrayProperty(entries []time.Time) ArrayProperty {
 	return newArrayProperty(12, props)
 }
 
+func NewInt64ArrayProperty(entries []int64) ArrayProperty {
+	props := make([]Property, len(entries))
+	for i, entry := range entries {
+		props[i] = NewInt64Property(entry)
+	}
+	return newArrayProperty(8, props)
+}
+
+func NewFloat64ArrayProperty(entries []float64) ArrayProperty {
+	props := make([]Property, len(entries))
+	for i, entry := range entries {
+		props[i] = NewFloat64Property(entry)
+	}
+	return newArrayProperty(9, props)
+}
+
+func NewUintArrayProperty(entries []uint64) ArrayProperty {
+	props := make([]Property, len(entries))
+	for i, entry := range entries {
+		props[i] = NewUintProperty(entry)
+	}
+	return newArrayProperty(10, props)
+}
+
 // BINARY ARRAY ===============================================================
 
 type ArrayPropertyRaw struct {
@@ -734,9 +1137,28 @@ func (apr ArrayPropertyRaw) Data() []byte {
 	return apr.data
 }
 
+// Properties returns the elements of the array as individual byte or bool
+// properties.
+func (apr ArrayPropertyRaw) Properties() []Property {
+	br, _, err := rapbin.ReadBytesArray(bytes.NewBuffer(apr.data))
+	if err != nil {
+		return nil
+	}
+
+	props := make([]Property, len(br))
+	for i, b := range br {
+		if apr.originalBaseCode == 3 {
+			props[i] = NewBoolProperty(b&1 == 1)
+		} else {
+			props[i] = NewByteProperty(b)
+		}
+	}
+	return props
+}
+
 func (apr *ArrayPropertyRaw) UnmarshalJSON(b []byte) error {
 	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := unmarshalUsingNumbers(b, &data); err != nil {
 		return err
 	}
 	prop, err := getProperties(data)
@@ -802,6 +1224,21 @@ func (apr ArrayPropertyRaw) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return bson.TypeNull, nil, nil
 }
 
+func (apr *ArrayPropertyRaw) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	prop, err := PropertyFromBSONValue(bson.RawValue{Type: t, Value: data})
+	if err != nil {
+		return err
+	}
+	newApr, ok := prop.(ArrayPropertyRaw)
+	if !ok {
+		return bsonTypeError("raw array property", t)
+	}
+	apr.data = newApr.data
+	apr.originalBaseCode = newApr.originalBaseCode
+	apr.division = newApr.division
+	return nil
+}
+
 func NewBinaryArrayProperty(binarr []byte) ArrayPropertyRaw {
 	return ArrayPropertyRaw{
 		data:             rapbin.BytesArrayToBytes(binarr),
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -424,3 +425,250 @@ func Test_MetadataBlockProperty_MarshalBSON(t *testing.T) {
 	assert.Equal(t, int32(888888), docMap["INT32"])
 	assert.Equal(t, primitive.Binary{Subtype: 0x0, Data: []uint8{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}}, docMap["BIN_ARR"])
 }
+
+func Test_Int64Property(t *testing.T) {
+	tests := map[string]struct {
+		value     int64
+		stringVal string
+	}{
+		"0":         {value: 0, stringVal: "0"},
+		"-10":       {value: -10, stringVal: "-10"},
+		"unix ms":   {value: 1634567890123, stringVal: "1634567890123"},
+		"min int64": {value: math.MinInt64, stringVal: "-9223372036854775808"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prop := metadata.NewInt64Property(tc.value)
+			assert.Equal(t, byte(8), prop.Code())
+			assert.Equal(t, tc.stringVal, prop.String())
+
+			var out int64
+			binary.Read(bytes.NewBuffer(prop.Data()), binary.LittleEndian, &out)
+			assert.Equal(t, tc.value, out)
+
+			b, err := json.Marshal(prop)
+			assert.Nil(t, err)
+
+			var ip metadata.Int64Property
+			assert.Nil(t, json.Unmarshal(b, &ip))
+			assert.Equal(t, prop, ip)
+		})
+	}
+}
+
+func Test_Float64Property(t *testing.T) {
+	tests := map[string]struct {
+		value     float64
+		stringVal string
+	}{
+		"0":  {value: 0, stringVal: "0.000000"},
+		"pi": {value: math.Pi, stringVal: "3.141593"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prop := metadata.NewFloat64Property(tc.value)
+			assert.Equal(t, byte(9), prop.Code())
+			assert.Equal(t, tc.stringVal, prop.String())
+
+			var out float64
+			binary.Read(bytes.NewBuffer(prop.Data()), binary.LittleEndian, &out)
+			assert.Equal(t, tc.value, out)
+
+			b, err := json.Marshal(prop)
+			assert.Nil(t, err)
+
+			var fp metadata.Float64Property
+			assert.Nil(t, json.Unmarshal(b, &fp))
+			assert.Equal(t, prop, fp)
+		})
+	}
+}
+
+func Test_UintProperty(t *testing.T) {
+	tests := map[string]struct {
+		value     uint64
+		stringVal string
+	}{
+		"0":          {value: 0, stringVal: "0"},
+		"max uint64": {value: math.MaxUint64, stringVal: "18446744073709551615"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prop := metadata.NewUintProperty(tc.value)
+			assert.Equal(t, byte(10), prop.Code())
+			assert.Equal(t, tc.stringVal, prop.String())
+
+			var out uint64
+			binary.Read(bytes.NewBuffer(prop.Data()), binary.LittleEndian, &out)
+			assert.Equal(t, tc.value, out)
+
+			b, err := json.Marshal(prop)
+			assert.Nil(t, err)
+
+			var up metadata.UintProperty
+			assert.Nil(t, json.Unmarshal(b, &up))
+			assert.Equal(t, prop, up)
+		})
+	}
+}
+
+func Test_NullProperty(t *testing.T) {
+	prop := metadata.NewNullProperty()
+	assert.Equal(t, byte(26), prop.Code())
+	assert.Equal(t, "null", prop.String())
+	assert.Len(t, prop.Data(), 0)
+
+	b, err := json.Marshal(prop)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+
+	var np metadata.NullProperty
+	assert.NoError(t, np.UnmarshalJSON(b))
+	assert.Error(t, np.UnmarshalJSON([]byte("1")))
+}
+
+func Test_MetadataBlockProperty_InfersSmallestLosslessNumericType(t *testing.T) {
+	// ARRANGE ================================================================
+	mp := metadata.NewMetadataProperty(metadata.EmptyBlock())
+
+	// ACT ====================================================================
+	err := mp.UnmarshalJSON([]byte(`{
+		"small": 12,
+		"negative": -12,
+		"unix ms": 1634567890123,
+		"steam id": 76561197960287930,
+		"huge": 18446744073709551615,
+		"float32": 1.5,
+		"float64": 3.141592653589793,
+		"whole float": 10.0,
+		"nothing": null
+	}`))
+	mapping := mp.Block().Mapping()
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, metadata.NewIntProperty(12), mapping["small"])
+	assert.Equal(t, metadata.NewIntProperty(-12), mapping["negative"])
+	assert.Equal(t, metadata.NewInt64Property(1634567890123), mapping["unix ms"])
+	assert.Equal(t, metadata.NewInt64Property(76561197960287930), mapping["steam id"])
+	assert.Equal(t, metadata.NewUintProperty(math.MaxUint64), mapping["huge"])
+	assert.Equal(t, metadata.NewFloat32Property(1.5), mapping["float32"])
+	assert.Equal(t, metadata.NewFloat64Property(3.141592653589793), mapping["float64"])
+	assert.Equal(t, metadata.NewIntProperty(10), mapping["whole float"])
+	assert.Equal(t, metadata.NewNullProperty(), mapping["nothing"])
+}
+
+func Test_MetadataBlockProperty_NewNumericTypesSurviveJSON(t *testing.T) {
+	// ARRANGE ================================================================
+	blockProp := metadata.NewMetadataProperty(metadata.NewBlock(
+		map[string]metadata.Property{
+			"INT64":       metadata.NewInt64Property(-1634567890123),
+			"FLOAT64":     metadata.NewFloat64Property(math.E),
+			"UINT":        metadata.NewUintProperty(math.MaxUint64),
+			"NULL":        metadata.NewNullProperty(),
+			"INT64_ARR":   metadata.NewInt64ArrayProperty([]int64{1634567890123, -1634567890123}),
+			"FLOAT64_ARR": metadata.NewFloat64ArrayProperty([]float64{math.Pi, math.E}),
+			"UINT_ARR":    metadata.NewUintArrayProperty([]uint64{math.MaxUint64, 1}),
+		},
+	))
+	mp := metadata.NewMetadataProperty(metadata.EmptyBlock())
+
+	// ACT ====================================================================
+	jsonMarshal, errMarsh := blockProp.MarshalJSON()
+	unmarshErr := mp.UnmarshalJSON(jsonMarshal)
+
+	// ASSERT =================================================================
+	assert.NoError(t, errMarsh)
+	assert.NoError(t, unmarshErr)
+	assert.Equal(t, blockProp.Block().Mapping(), mp.Block().Mapping())
+}
+
+func Test_ArrayProperty_PromotesMixedNumbers(t *testing.T) {
+	tests := map[string]struct {
+		json     string
+		expected metadata.ArrayProperty
+	}{
+		"int32 and int64": {
+			json:     `[1, 1634567890123]`,
+			expected: metadata.NewInt64ArrayProperty([]int64{1, 1634567890123}),
+		},
+		"int32 and uint": {
+			json:     `[1, 18446744073709551615]`,
+			expected: metadata.NewUintArrayProperty([]uint64{1, math.MaxUint64}),
+		},
+		"int32 and float32": {
+			json:     `[1, 2.5]`,
+			expected: metadata.NewFloat32ArrayProperty([]float32{1, 2.5}),
+		},
+		"int64 and float32": {
+			json:     `[1634567890123, 2.5]`,
+			expected: metadata.NewFloat64ArrayProperty([]float64{1634567890123, 2.5}),
+		},
+		"float32 and float64": {
+			json:     `[2.5, 3.141592653589793]`,
+			expected: metadata.NewFloat64ArrayProperty([]float64{2.5, 3.141592653589793}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ap metadata.ArrayProperty
+			assert.NoError(t, ap.UnmarshalJSON([]byte(tc.json)))
+			assert.Equal(t, tc.expected, ap)
+		})
+	}
+}
+
+func Test_ArrayProperty_MixedTypes_Errors(t *testing.T) {
+	var ap metadata.ArrayProperty
+	assert.EqualError(t, ap.UnmarshalJSON([]byte(`[1, "a"]`)), "metadata arrays must contain a single type")
+	assert.EqualError(t, ap.UnmarshalJSON([]byte(`[true, 1]`)), "metadata arrays must contain a single type")
+}
+
+func Test_ArrayProperty_Nulls_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	mp := metadata.NewMetadataProperty(metadata.EmptyBlock())
+
+	// ACT ====================================================================
+	err := mp.UnmarshalJSON([]byte(`{"nothing": [null, null], "something": null}`))
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "metadata arrays can not contain null")
+}
+
+func Test_ArrayProperty_Empty(t *testing.T) {
+	var ap metadata.ArrayProperty
+	assert.NoError(t, ap.UnmarshalJSON([]byte(`[]`)))
+	assert.Equal(t, metadata.NewStringArrayProperty(nil), ap)
+}
+
+func Test_NewNumericProperties_MarshalBSON(t *testing.T) {
+	blockProp := metadata.NewMetadataProperty(metadata.NewBlock(
+		map[string]metadata.Property{
+			"INT64":      metadata.NewInt64Property(1634567890123),
+			"FLOAT64":    metadata.NewFloat64Property(math.Pi),
+			"UINT_SMALL": metadata.NewUintProperty(42),
+			"UINT_HUGE":  metadata.NewUintProperty(math.MaxUint64),
+			"NULL":       metadata.NewNullProperty(),
+		},
+	))
+
+	data, err := bson.Marshal(blockProp)
+	assert.NoError(t, err)
+
+	var doc bson.D
+	err = bson.Unmarshal(data, &doc)
+	assert.NoError(t, err)
+	docMap := doc.Map()
+
+	expectedHuge, _ := primitive.ParseDecimal128("18446744073709551615")
+	assert.Equal(t, int64(1634567890123), docMap["INT64"])
+	assert.Equal(t, math.Pi, docMap["FLOAT64"])
+	assert.Equal(t, int64(42), docMap["UINT_SMALL"])
+	assert.Equal(t, expectedHuge, docMap["UINT_HUGE"])
+	assert.Nil(t, docMap["NULL"])
+	assert.Contains(t, docMap, "NULL")
+}
@@ -0,0 +1,231 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PropertyType is a broad category of property that a schema can require a
+// key to hold. Categories ignore storage width, so IntegerType is satisfied
+// by Int32, Int64 and Uint properties alike.
+type PropertyType int
+
+const (
+	// AnyType accepts every property
+	AnyType PropertyType = iota
+	StringType
+	IntegerType
+
+	// NumberType accepts both integer and floating point properties
+	NumberType
+	BoolType
+	ByteType
+	TimeType
+	Vector2Type
+	Vector3Type
+	BlockType
+	ArrayType
+	BinaryType
+	NullType
+)
+
+func (pt PropertyType) String() string {
+	switch pt {
+	case AnyType:
+		return "any"
+	case StringType:
+		return "string"
+	case IntegerType:
+		return "integer"
+	case NumberType:
+		return "number"
+	case BoolType:
+		return "bool"
+	case ByteType:
+		return "byte"
+	case TimeType:
+		return "time"
+	case Vector2Type:
+		return "vector2"
+	case Vector3Type:
+		return "vector3"
+	case BlockType:
+		return "block"
+	case ArrayType:
+		return "array"
+	case BinaryType:
+		return "binary"
+	case NullType:
+		return "null"
+	}
+	return fmt.Sprintf("PropertyType(%d)", int(pt))
+}
+
+// TypeOf reports the category the property falls under.
+func TypeOf(prop Property) PropertyType {
+	switch prop.Code() {
+	case 0:
+		return StringType
+	case 1, 8, 10:
+		return IntegerType
+	case 2, 9:
+		return NumberType
+	case 3, 4:
+		return BoolType
+	case 5:
+		return ByteType
+	case 6:
+		return Vector2Type
+	case 7:
+		return Vector3Type
+	case 11:
+		return BlockType
+	case 12:
+		return TimeType
+	case 18:
+		return BinaryType
+	case 26:
+		return NullType
+	}
+	return ArrayType
+}
+
+func (pt PropertyType) accepts(prop Property) bool {
+	actual := TypeOf(prop)
+	switch pt {
+	case AnyType:
+		return true
+	case NumberType:
+		return actual == NumberType || actual == IntegerType
+	}
+	return actual == pt
+}
+
+// ValueRange bounds numeric values, inclusive on both ends.
+type ValueRange struct {
+	Min float64
+	Max float64
+}
+
+// KeySchema describes what is allowed to be stored under a single key.
+type KeySchema struct {
+	Type     PropertyType
+	Required bool
+
+	// Range, if set, bounds the value of numeric properties
+	Range *ValueRange
+
+	// Options, if set, are the only values a string property may take
+	Options []string
+
+	// Nested, if set, validates the contents of block properties
+	Nested *Schema
+}
+
+// Schema describes the keys a metadata block is expected to contain.
+type Schema struct {
+	Keys map[string]KeySchema
+
+	// Strict rejects any key not found in Keys
+	Strict bool
+}
+
+// Violation is a single way a block failed to meet its schema.
+type Violation struct {
+	// Path is a JSON pointer to the offending key within the block
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+func escapePointerSegment(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// Validate checks the block against the schema, reporting every violation
+// found. Violations are ordered by path.
+func (s Schema) Validate(block Block) []Violation {
+	return s.validate("", block)
+}
+
+func (s Schema) validate(prefix string, block Block) []Violation {
+	violations := make([]Violation, 0)
+
+	keys := block.Keys()
+	for key := range s.Keys {
+		if _, ok := block.Mapping()[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + "/" + escapePointerSegment(key)
+		keySchema, declared := s.Keys[key]
+		prop, present := block.Mapping()[key]
+
+		if !declared {
+			if s.Strict {
+				violations = append(violations, Violation{Path: path, Message: "key not allowed by schema"})
+			}
+			continue
+		}
+
+		if !present {
+			if keySchema.Required {
+				violations = append(violations, Violation{Path: path, Message: "required key is missing"})
+			}
+			continue
+		}
+
+		violations = append(violations, keySchema.validate(path, prop)...)
+	}
+
+	return violations
+}
+
+func (ks KeySchema) validate(path string, prop Property) []Violation {
+	if !ks.Type.accepts(prop) {
+		return []Violation{{
+			Path:    path,
+			Message: fmt.Sprintf("expected %s but found %s", ks.Type, TypeOf(prop)),
+		}}
+	}
+
+	violations := make([]Violation, 0)
+
+	if value, ok := numericValue(prop); ok && ks.Range != nil {
+		if value < ks.Range.Min || value > ks.Range.Max {
+			violations = append(violations, Violation{
+				Path:    path,
+				Message: fmt.Sprintf("%v is outside of range [%v, %v]", value, ks.Range.Min, ks.Range.Max),
+			})
+		}
+	}
+
+	if str, ok := prop.(StringProperty); ok && len(ks.Options) > 0 {
+		found := false
+		for _, option := range ks.Options {
+			if option == str.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			violations = append(violations, Violation{
+				Path:    path,
+				Message: fmt.Sprintf("'%s' is not one of [%s]", str.String(), strings.Join(ks.Options, ", ")),
+			})
+		}
+	}
+
+	if nested, ok := prop.(MetadataProperty); ok && ks.Nested != nil {
+		violations = append(violations, ks.Nested.validate(path, nested.Block())...)
+	}
+
+	return violations
+}
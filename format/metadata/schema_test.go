@@ -0,0 +1,101 @@
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TypeOf(t *testing.T) {
+	tests := map[string]struct {
+		prop     metadata.Property
+		expected metadata.PropertyType
+	}{
+		"string":     {prop: metadata.NewStringProperty("a"), expected: metadata.StringType},
+		"int32":      {prop: metadata.NewIntProperty(1), expected: metadata.IntegerType},
+		"int64":      {prop: metadata.NewInt64Property(1), expected: metadata.IntegerType},
+		"uint":       {prop: metadata.NewUintProperty(1), expected: metadata.IntegerType},
+		"float32":    {prop: metadata.NewFloat32Property(1), expected: metadata.NumberType},
+		"float64":    {prop: metadata.NewFloat64Property(1), expected: metadata.NumberType},
+		"bool":       {prop: metadata.NewBoolProperty(false), expected: metadata.BoolType},
+		"byte":       {prop: metadata.NewByteProperty(1), expected: metadata.ByteType},
+		"vector2":    {prop: metadata.NewVector2Property(1, 2), expected: metadata.Vector2Type},
+		"vector3":    {prop: metadata.NewVector3Property(1, 2, 3), expected: metadata.Vector3Type},
+		"block":      {prop: metadata.NewMetadataProperty(metadata.EmptyBlock()), expected: metadata.BlockType},
+		"array":      {prop: metadata.NewIntArrayProperty([]int{1}), expected: metadata.ArrayType},
+		"bool array": {prop: metadata.NewBoolArrayProperty([]bool{true}), expected: metadata.ArrayType},
+		"binary":     {prop: metadata.NewBinaryArrayProperty([]byte{1}), expected: metadata.BinaryType},
+		"null":       {prop: metadata.NewNullProperty(), expected: metadata.NullType},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, metadata.TypeOf(tc.prop))
+		})
+	}
+}
+
+func Test_Schema_Validate(t *testing.T) {
+	// ARRANGE ================================================================
+	schema := metadata.Schema{
+		Strict: true,
+		Keys: map[string]metadata.KeySchema{
+			"studio":   {Type: metadata.StringType, Required: true},
+			"platform": {Type: metadata.StringType, Options: []string{"pc", "quest"}},
+			"score":    {Type: metadata.NumberType, Range: &metadata.ValueRange{Min: 0, Max: 100}},
+			"level":    {Type: metadata.IntegerType},
+			"player": {
+				Type:     metadata.BlockType,
+				Required: true,
+				Nested: &metadata.Schema{
+					Keys: map[string]metadata.KeySchema{
+						"id/name": {Type: metadata.StringType, Required: true},
+					},
+				},
+			},
+		},
+	}
+
+	block := metadata.NewBlock(map[string]metadata.Property{
+		"platform": metadata.NewStringProperty("switch"),
+		"score":    metadata.NewIntProperty(101),
+		"level":    metadata.NewFloat32Property(1.5),
+		"extra":    metadata.NewBoolProperty(true),
+		"player": metadata.NewMetadataProperty(metadata.NewBlock(map[string]metadata.Property{
+			"whatever": metadata.NewBoolProperty(true),
+		})),
+	})
+
+	// ACT ====================================================================
+	violations := schema.Validate(block)
+
+	// ASSERT =================================================================
+	assert.Equal(t, []metadata.Violation{
+		{Path: "/extra", Message: "key not allowed by schema"},
+		{Path: "/level", Message: "expected integer but found number"},
+		{Path: "/platform", Message: "'switch' is not one of [pc, quest]"},
+		{Path: "/player/id~1name", Message: "required key is missing"},
+		{Path: "/score", Message: "101 is outside of range [0, 100]"},
+		{Path: "/studio", Message: "required key is missing"},
+	}, violations)
+}
+
+func Test_Schema_ValidBlockHasNoViolations(t *testing.T) {
+	schema := metadata.Schema{
+		Keys: map[string]metadata.KeySchema{
+			"studio": {Type: metadata.StringType, Required: true},
+			"score":  {Type: metadata.NumberType, Range: &metadata.ValueRange{Min: 0, Max: 100}},
+			"any":    {},
+		},
+	}
+
+	violations := schema.Validate(metadata.NewBlock(map[string]metadata.Property{
+		"studio": metadata.NewStringProperty("us"),
+		"score":  metadata.NewFloat64Property(99.5),
+		"any":    metadata.NewVector2Property(1, 2),
+		"extra":  metadata.NewBoolProperty(true),
+	}))
+
+	assert.Len(t, violations, 0)
+}
@@ -1,24 +1,178 @@
 package metadata
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// unmarshalUsingNumbers decodes JSON while keeping numbers as json.Number, so
+// large integers don't lose precision passing through a float64.
+func unmarshalUsingNumbers(b []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// floatProperty picks Float32 when the value survives being written out as a
+// float32 and read back, and Float64 otherwise.
+func floatProperty(f float64) Property {
+	if math.Abs(f) <= math.MaxFloat32 {
+		f32 := float32(f)
+		roundTrip, err := strconv.ParseFloat(strconv.FormatFloat(float64(f32), 'g', -1, 32), 64)
+		if err == nil && roundTrip == f {
+			return NewFloat32Property(f32)
+		}
+	}
+	return NewFloat64Property(f)
+}
+
+func integerProperty(i int64) Property {
+	if i >= math.MinInt32 && i <= math.MaxInt32 {
+		return NewIntProperty(int(i))
+	}
+	return NewInt64Property(i)
+}
+
+// numberProperty chooses the smallest property type that can hold the JSON
+// number without losing precision.
+func numberProperty(n json.Number) (Property, error) {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return integerProperty(i), nil
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return NewUintProperty(u), nil
+	}
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return nil, err
+	}
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return integerProperty(int64(f)), nil
+	}
+	return floatProperty(f), nil
+}
+
+func toFloat(data interface{}) (float64, bool) {
+	switch v := data.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isNumericCode(code byte) bool {
+	switch code {
+	case 1, 2, 8, 9, 10:
+		return true
+	}
+	return false
+}
+
+// promoteNumbers converts a mix of numeric properties into a single type wide
+// enough to hold all of them.
+func promoteNumbers(props []Property) []Property {
+	anyFloat, anyNegative, anyUint, anyInt64 := false, false, false, false
+	for _, prop := range props {
+		switch p := prop.(type) {
+		case Float32Property, Float64Property:
+			anyFloat = true
+		case Int32Property:
+			anyNegative = anyNegative || p.Value() < 0
+		case Int64Property:
+			anyInt64 = true
+			anyNegative = anyNegative || p.Value() < 0
+		case UintProperty:
+			anyUint = true
+		}
+	}
+
+	promoted := make([]Property, len(props))
+	for i, prop := range props {
+		var asFloat float64
+		switch p := prop.(type) {
+		case Int32Property:
+			asFloat = float64(p.Value())
+			if !anyFloat && !(anyUint && anyNegative) {
+				switch {
+				case anyUint:
+					promoted[i] = NewUintProperty(uint64(p.Value()))
+				case anyInt64:
+					promoted[i] = NewInt64Property(int64(p.Value()))
+				default:
+					promoted[i] = p
+				}
+				continue
+			}
+		case Int64Property:
+			asFloat = float64(p.Value())
+			if !anyFloat && !(anyUint && anyNegative) {
+				if anyUint {
+					promoted[i] = NewUintProperty(uint64(p.Value()))
+				} else {
+					promoted[i] = p
+				}
+				continue
+			}
+		case UintProperty:
+			asFloat = float64(p.Value())
+			if !anyFloat && !anyNegative {
+				promoted[i] = p
+				continue
+			}
+		case Float32Property:
+			asFloat, _ = strconv.ParseFloat(strconv.FormatFloat(float64(p.Value()), 'g', -1, 32), 64)
+		case Float64Property:
+			asFloat = p.Value()
+		}
+		promoted[i] = NewFloat64Property(asFloat)
+	}
+
+	// Stick with float32 if every entry survives the trip
+	allFloat32 := true
+	for _, prop := range promoted {
+		f, ok := prop.(Float64Property)
+		if !ok {
+			allFloat32 = false
+			break
+		}
+		if _, ok := floatProperty(f.Value()).(Float32Property); !ok {
+			allFloat32 = false
+			break
+		}
+	}
+	if allFloat32 {
+		for i, prop := range promoted {
+			promoted[i] = floatProperty(prop.(Float64Property).Value())
+		}
+	}
+
+	return promoted
+}
+
 func getProperties(data interface{}) (Property, error) {
 	switch d := data.(type) {
 	case map[string]interface{}:
 		x, foundX := d["x"]
 		y, foundY := d["y"]
 		z, foundZ := d["z"]
-		if len(d) == 2 && foundX && foundY {
-			return NewVector2Property(x.(float64), y.(float64)), nil
+		xVal, xNumeric := toFloat(x)
+		yVal, yNumeric := toFloat(y)
+		zVal, zNumeric := toFloat(z)
+		if len(d) == 2 && foundX && foundY && xNumeric && yNumeric {
+			return NewVector2Property(xVal, yVal), nil
 		}
-		if len(d) == 3 && foundX && foundY && foundZ {
-			return NewVector3Property(x.(float64), y.(float64), z.(float64)), nil
+		if len(d) == 3 && foundX && foundY && foundZ && xNumeric && yNumeric && zNumeric {
+			return NewVector3Property(xVal, yVal, zVal), nil
 		}
 		nestedProps := make(map[string]Property)
 		for key, val := range d {
@@ -30,23 +184,45 @@ func getProperties(data interface{}) (Property, error) {
 		}
 		return NewMetadataProperty(NewBlock(nestedProps)), nil
 	case []interface{}:
+		if len(d) == 0 {
+			return NewStringArrayProperty(nil), nil
+		}
 		if _, ok := d[0].(bool); ok {
 			bools := make([]bool, 0, len(d))
 			for _, item := range d {
-				b, _ := item.(bool)
+				b, ok := item.(bool)
+				if !ok {
+					return nil, errors.New("metadata arrays must contain a single type")
+				}
 				bools = append(bools, b)
 			}
 			return NewBoolArrayProperty(bools), nil
 		}
 		props := make([]Property, 0, len(d))
-		for _, item := range d {
+		allNumeric := true
+		sameType := true
+		for i, item := range d {
 			propItem, err := getProperties(item)
 			if err != nil {
 				return nil, err
 			}
+			if propItem == nil {
+				return nil, fmt.Errorf("unable to interpret array element %v", item)
+			}
+			allNumeric = allNumeric && isNumericCode(propItem.Code())
+			sameType = sameType && (i == 0 || propItem.Code() == props[0].Code())
 			props = append(props, propItem)
 		}
-		return ArrayProperty{props: props, originalBaseCode: props[0].Code()}, nil
+		if !sameType {
+			if !allNumeric {
+				return nil, errors.New("metadata arrays must contain a single type")
+			}
+			props = promoteNumbers(props)
+		}
+		if _, isNull := props[0].(NullProperty); isNull {
+			return nil, errors.New("metadata arrays can not contain null")
+		}
+		return newArrayProperty(props[0].Code(), props), nil
 	case string:
 		if strings.HasPrefix(d, HEX_PREFIX) && len(d) == 4 {
 			var p ByteProperty
@@ -74,25 +250,18 @@ func getProperties(data interface{}) (Property, error) {
 			return nil, err
 		}
 		return p, nil
+	case json.Number:
+		return numberProperty(d)
 	case float64:
-		if d == float64(int64(d)) {
-			var p Int32Property
-			if err := json.Unmarshal([]byte(fmt.Sprintf(`%v`, d)), &p); err != nil {
-				return nil, err
-			}
-			return p, nil
-		}
-		var p Float32Property
-		if err := json.Unmarshal([]byte(fmt.Sprintf(`%v`, d)), &p); err != nil {
-			return nil, err
-		}
-		return p, nil
+		return numberProperty(json.Number(strconv.FormatFloat(d, 'f', -1, 64)))
 	case bool:
 		var p BoolProperty
 		if err := json.Unmarshal([]byte(fmt.Sprintf(`%v`, d)), &p); err != nil {
 			return nil, err
 		}
 		return p, nil
+	case nil:
+		return NewNullProperty(), nil
 	}
 	return nil, nil
 }
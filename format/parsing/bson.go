@@ -0,0 +1,813 @@
+package parsing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/euler"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Every metadata block is written twice. "metadata" is a plain document meant
+// for indexing and querying, while "metadataEncoded" holds the block in the
+// RAP binary encoding, which keeps the exact property types that BSON can't
+// (bytes, float32s, microsecond timestamps). When reading, the encoded copy
+// is used whenever it is present, but only after checking it still agrees
+// with the plain document, so edits made to the document in a store are never
+// silently dropped. Removing "metadataEncoded" makes the edited document
+// authoritative.
+
+type bsonVector3 struct {
+	X float64 `bson:"x"`
+	Y float64 `bson:"y"`
+	Z float64 `bson:"z"`
+}
+
+func newBSONVector3(v vector.Vector3) bsonVector3 {
+	return bsonVector3{v.X(), v.Y(), v.Z()}
+}
+
+func (v bsonVector3) vector() vector.Vector3 {
+	return vector.NewVector3(v.X, v.Y, v.Z)
+}
+
+type bsonVector2 struct {
+	X float64 `bson:"x"`
+	Y float64 `bson:"y"`
+}
+
+type bsonCapture struct {
+	Time float64       `bson:"time"`
+	Data bson.RawValue `bson:"data"`
+}
+
+type bsonMetadataCapture struct {
+	Name            string   `bson:"name,omitempty"`
+	End             *float64 `bson:"end,omitempty"`
+	Label           string   `bson:"label,omitempty"`
+	Metadata        bson.Raw `bson:"metadata"`
+	MetadataEncoded []byte   `bson:"metadataEncoded,omitempty"`
+}
+
+type bsonGazeCapture struct {
+	Origin        bsonVector3 `bson:"origin"`
+	Direction     bsonVector3 `bson:"direction"`
+	LeftOpenness  float64     `bson:"leftOpenness"`
+	RightOpenness float64     `bson:"rightOpenness"`
+	PupilDiameter float64     `bson:"pupilDiameter"`
+	Confidence    float64     `bson:"confidence"`
+}
+
+type bsonSkeletonCapture struct {
+	Root      bsonVector3   `bson:"root"`
+	Rotations []bsonVector3 `bson:"rotations"`
+}
+
+type bsonJoint struct {
+	Name   string      `bson:"name"`
+	Parent int         `bson:"parent"`
+	Offset bsonVector3 `bson:"offset"`
+}
+
+type bsonStructField struct {
+	Name    string   `bson:"name"`
+	Type    string   `bson:"type"`
+	Options []string `bson:"options,omitempty"`
+}
+
+type bsonCollection struct {
+	Name            string            `bson:"name"`
+	Type            string            `bson:"type"`
+	Metadata        bson.Raw          `bson:"metadata"`
+	MetadataEncoded []byte            `bson:"metadataEncoded,omitempty"`
+	Members         []string          `bson:"members,omitempty"`
+	Skeleton        []bsonJoint       `bson:"skeleton,omitempty"`
+	Channels        []string          `bson:"channels,omitempty"`
+	Schema          []bsonStructField `bson:"schema,omitempty"`
+	Captures        []bsonCapture     `bson:"captures"`
+}
+
+type bsonBinary struct {
+	Name            string   `bson:"name"`
+	Data            []byte   `bson:"data"`
+	Metadata        bson.Raw `bson:"metadata"`
+	MetadataEncoded []byte   `bson:"metadataEncoded,omitempty"`
+}
+
+type bsonReference struct {
+	Name            string   `bson:"name"`
+	URI             string   `bson:"uri"`
+	Size            int64    `bson:"size"`
+	Metadata        bson.Raw `bson:"metadata"`
+	MetadataEncoded []byte   `bson:"metadataEncoded,omitempty"`
+}
+
+type bsonRecording struct {
+	ID              string           `bson:"id"`
+	Name            string           `bson:"name"`
+	Metadata        bson.Raw         `bson:"metadata"`
+	MetadataEncoded []byte           `bson:"metadataEncoded,omitempty"`
+	Collections     []bsonCollection `bson:"collections"`
+	Binaries        []bsonBinary     `bson:"binaries"`
+	References      []bsonReference  `bson:"references"`
+	Recordings      []bsonRecording  `bson:"recordings"`
+}
+
+func metadataToBSON(block metadata.Block) (bson.Raw, []byte, error) {
+	prop := metadata.NewMetadataProperty(block)
+	natural, err := bson.Marshal(prop)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(block.Mapping()) == 0 {
+		return natural, nil, nil
+	}
+
+	encoded := new(bytes.Buffer)
+	if _, err := metadata.WriteProprty(encoded, prop); err != nil {
+		return nil, nil, err
+	}
+	return natural, encoded.Bytes(), nil
+}
+
+func metadataFromBSON(natural bson.Raw, encoded []byte) (metadata.Block, error) {
+	naturalBlock := metadata.EmptyBlock()
+	if len(natural) > 0 {
+		var err error
+		naturalBlock, err = metadata.BlockFromBSON(natural)
+		if err != nil {
+			return metadata.EmptyBlock(), err
+		}
+	}
+
+	if len(encoded) == 0 {
+		return naturalBlock, nil
+	}
+
+	prop, err := metadata.ReadProperty(bytes.NewReader(encoded))
+	if err != nil {
+		return metadata.EmptyBlock(), err
+	}
+
+	encodedBlock, ok := prop.(metadata.MetadataProperty)
+	if !ok {
+		return metadata.EmptyBlock(), errors.New("encoded metadata is not a metadata block")
+	}
+
+	// Compare the two copies at the precision BSON can hold
+	naturalData, err := bson.Marshal(metadata.NewMetadataProperty(naturalBlock))
+	if err != nil {
+		return metadata.EmptyBlock(), err
+	}
+	encodedData, err := bson.Marshal(encodedBlock)
+	if err != nil {
+		return metadata.EmptyBlock(), err
+	}
+	if !bytes.Equal(naturalData, encodedData) {
+		return metadata.EmptyBlock(), errors.New("metadata document does not match metadataEncoded, remove metadataEncoded to keep the document's values")
+	}
+
+	return encodedBlock.Block(), nil
+}
+
+func toRawValue(value interface{}) (bson.RawValue, error) {
+	t, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.RawValue{Type: t, Value: data}, nil
+}
+
+func captureDataToBSON(collection format.CaptureCollection, capture format.Capture) (interface{}, error) {
+	switch c := capture.(type) {
+	case position.Capture:
+		return newBSONVector3(c.Position()), nil
+
+	case euler.Capture:
+		return newBSONVector3(c.EulerZXY()), nil
+
+	case vector2.Capture:
+		return bsonVector2{c.Value().X(), c.Value().Y()}, nil
+
+	case float.Capture:
+		return c.Value(), nil
+
+	case integer.Capture:
+		return c.Value(), nil
+
+	case boolean.Capture:
+		return c.Value(), nil
+
+	case enum.Capture:
+		members := collection.(enum.Collection).EnumMembers()
+		if c.Value() < 0 || c.Value() >= len(members) {
+			return nil, fmt.Errorf("enum capture value %d has no member", c.Value())
+		}
+		return members[c.Value()], nil
+
+	case event.Capture:
+		natural, encoded, err := metadataToBSON(c.Metadata())
+		if err != nil {
+			return nil, err
+		}
+		return bsonMetadataCapture{Name: c.Name(), Metadata: natural, MetadataEncoded: encoded}, nil
+
+	case span.Capture:
+		natural, encoded, err := metadataToBSON(c.Metadata())
+		if err != nil {
+			return nil, err
+		}
+		end := c.End()
+		return bsonMetadataCapture{End: &end, Label: c.Label(), Metadata: natural, MetadataEncoded: encoded}, nil
+
+	case gaze.Capture:
+		return bsonGazeCapture{
+			Origin:        newBSONVector3(c.Origin()),
+			Direction:     newBSONVector3(c.Direction()),
+			LeftOpenness:  c.LeftOpenness(),
+			RightOpenness: c.RightOpenness(),
+			PupilDiameter: c.PupilDiameter(),
+			Confidence:    c.Confidence(),
+		}, nil
+
+	case skeleton.Capture:
+		rotations := make([]bsonVector3, len(c.Rotations()))
+		for i, rotation := range c.Rotations() {
+			rotations[i] = newBSONVector3(rotation)
+		}
+		return bsonSkeletonCapture{Root: newBSONVector3(c.Root()), Rotations: rotations}, nil
+
+	case floatvec.Capture:
+		return c.Values(), nil
+
+	case structure.Capture:
+		fields := collection.(structure.Collection).Schema().Fields()
+		doc := make(bson.D, len(fields))
+		for i, field := range fields {
+			var value interface{} = c.Value(i)
+			switch v := value.(type) {
+			case vector.Vector3:
+				value = newBSONVector3(v)
+			case int:
+				if field.Type == structure.Enum {
+					value = field.Options[v]
+				}
+			}
+			doc[i] = bson.E{Key: field.Name, Value: value}
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unable to convert capture of collection type '%s' to bson", collection.Signature())
+}
+
+func collectionToBSON(collection format.CaptureCollection) (bsonCollection, error) {
+	natural, encoded, err := metadataToBSON(collection.Metadata())
+	if err != nil {
+		return bsonCollection{}, err
+	}
+
+	doc := bsonCollection{
+		Name:            collection.Name(),
+		Type:            collection.Signature(),
+		Metadata:        natural,
+		MetadataEncoded: encoded,
+		Captures:        make([]bsonCapture, collection.Length()),
+	}
+
+	switch c := collection.(type) {
+	case enum.Collection:
+		doc.Members = c.EnumMembers()
+
+	case skeleton.Collection:
+		joints := c.Skeleton().Joints()
+		doc.Skeleton = make([]bsonJoint, len(joints))
+		for i, joint := range joints {
+			doc.Skeleton[i] = bsonJoint{Name: joint.Name, Parent: joint.Parent, Offset: newBSONVector3(joint.Offset)}
+		}
+
+	case floatvec.Collection:
+		doc.Channels = c.Channels()
+
+	case structure.Collection:
+		fields := c.Schema().Fields()
+		doc.Schema = make([]bsonStructField, len(fields))
+		for i, field := range fields {
+			doc.Schema[i] = bsonStructField{Name: field.Name, Type: field.Type.String()}
+			if field.Type == structure.Enum {
+				doc.Schema[i].Options = field.Options
+			}
+		}
+	}
+
+	for i := 0; i < collection.Length(); i++ {
+		capture := collection.CaptureAt(i)
+
+		data, err := captureDataToBSON(collection, capture)
+		if err != nil {
+			return bsonCollection{}, err
+		}
+
+		doc.Captures[i].Time = capture.Time()
+		doc.Captures[i].Data, err = toRawValue(data)
+		if err != nil {
+			return bsonCollection{}, err
+		}
+	}
+
+	return doc, nil
+}
+
+func recordingToBSON(recording format.Recording) (bsonRecording, error) {
+	if recording == nil {
+		return bsonRecording{}, errors.New("can not build bson from nil recording")
+	}
+
+	natural, encoded, err := metadataToBSON(recording.Metadata())
+	if err != nil {
+		return bsonRecording{}, err
+	}
+
+	doc := bsonRecording{
+		ID:              recording.ID(),
+		Name:            recording.Name(),
+		Metadata:        natural,
+		MetadataEncoded: encoded,
+		Collections:     make([]bsonCollection, len(recording.CaptureCollections())),
+		Binaries:        make([]bsonBinary, len(recording.Binaries())),
+		References:      make([]bsonReference, len(recording.BinaryReferences())),
+		Recordings:      make([]bsonRecording, len(recording.Recordings())),
+	}
+
+	for i, collection := range recording.CaptureCollections() {
+		doc.Collections[i], err = collectionToBSON(collection)
+		if err != nil {
+			return bsonRecording{}, err
+		}
+	}
+
+	for i, binary := range recording.Binaries() {
+		data, err := ioutil.ReadAll(binary.Data())
+		if err != nil {
+			return bsonRecording{}, err
+		}
+
+		natural, encoded, err := metadataToBSON(binary.Metadata())
+		if err != nil {
+			return bsonRecording{}, err
+		}
+
+		doc.Binaries[i] = bsonBinary{Name: binary.Name(), Data: data, Metadata: natural, MetadataEncoded: encoded}
+	}
+
+	for i, reference := range recording.BinaryReferences() {
+		natural, encoded, err := metadataToBSON(reference.Metadata())
+		if err != nil {
+			return bsonRecording{}, err
+		}
+
+		doc.References[i] = bsonReference{
+			Name:            reference.Name(),
+			URI:             reference.URI(),
+			Size:            int64(reference.Size()),
+			Metadata:        natural,
+			MetadataEncoded: encoded,
+		}
+	}
+
+	for i, child := range recording.Recordings() {
+		doc.Recordings[i], err = recordingToBSON(child)
+		if err != nil {
+			return bsonRecording{}, err
+		}
+	}
+
+	return doc, nil
+}
+
+// ToBSON builds a BSON document out of the recording, including all of its
+// collections, captures, binaries, references and child recordings.
+func ToBSON(recording format.Recording) ([]byte, error) {
+	doc, err := recordingToBSON(recording)
+	if err != nil {
+		return nil, err
+	}
+	return bson.Marshal(doc)
+}
+
+func unmarshalCaptureData(capture bsonCapture, thing string, out interface{}) error {
+	if capture.Data.Type != bson.TypeEmbeddedDocument {
+		return fmt.Errorf("%s capture data must be a document", thing)
+	}
+	if err := capture.Data.Unmarshal(out); err != nil {
+		return fmt.Errorf("%s capture: %w", thing, err)
+	}
+	return nil
+}
+
+func bsonCaptureFloat(capture bsonCapture, thing string) (float64, error) {
+	switch capture.Data.Type {
+	case bson.TypeDouble:
+		return capture.Data.Double(), nil
+	case bson.TypeInt32:
+		return float64(capture.Data.Int32()), nil
+	case bson.TypeInt64:
+		return float64(capture.Data.Int64()), nil
+	}
+	return 0, fmt.Errorf("%s capture data must be number", thing)
+}
+
+func bsonStructValue(field structure.Field, value bson.RawValue) (interface{}, error) {
+	switch field.Type {
+	case structure.Float:
+		var f float64
+		if err := value.Unmarshal(&f); err != nil {
+			return nil, fmt.Errorf("struct field %s must be number", field.Name)
+		}
+		return f, nil
+
+	case structure.Int:
+		switch value.Type {
+		case bson.TypeInt32:
+			return int64(value.Int32()), nil
+		case bson.TypeInt64:
+			return value.Int64(), nil
+		}
+		return nil, fmt.Errorf("struct field %s must be integer", field.Name)
+
+	case structure.Bool:
+		b, ok := value.BooleanOK()
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be boolean", field.Name)
+		}
+		return b, nil
+
+	case structure.Vector3:
+		var v bsonVector3
+		if value.Type != bson.TypeEmbeddedDocument || value.Unmarshal(&v) != nil {
+			return nil, fmt.Errorf("struct field %s must be vector3 document", field.Name)
+		}
+		return v.vector(), nil
+
+	case structure.String:
+		str, ok := value.StringValueOK()
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be string", field.Name)
+		}
+		return str, nil
+
+	case structure.Enum:
+		str, ok := value.StringValueOK()
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be string", field.Name)
+		}
+		for i, option := range field.Options {
+			if option == str {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("struct field %s has no option '%s'", field.Name, str)
+	}
+
+	return nil, fmt.Errorf("struct field %s has unknown type %d", field.Name, int(field.Type))
+}
+
+func collectionFromBSON(doc bsonCollection) (format.CaptureCollection, error) {
+	var collection format.CaptureCollection
+
+	switch doc.Type {
+	case "recolude.position":
+		captures := make([]position.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var v bsonVector3
+			if err := unmarshalCaptureData(capture, "position", &v); err != nil {
+				return nil, err
+			}
+			captures[i] = position.NewCapture(capture.Time, v.X, v.Y, v.Z)
+		}
+		collection = position.NewCollection(doc.Name, captures)
+
+	case "recolude.euler":
+		captures := make([]euler.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var v bsonVector3
+			if err := unmarshalCaptureData(capture, "euler", &v); err != nil {
+				return nil, err
+			}
+			captures[i] = euler.NewEulerZXYCapture(capture.Time, v.X, v.Y, v.Z)
+		}
+		collection = euler.NewCollection(doc.Name, captures)
+
+	case "recolude.vector2":
+		captures := make([]vector2.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var v bsonVector2
+			if err := unmarshalCaptureData(capture, "vector2", &v); err != nil {
+				return nil, err
+			}
+			captures[i] = vector2.NewCapture(capture.Time, v.X, v.Y)
+		}
+		collection = vector2.NewCollection(doc.Name, captures)
+
+	case "recolude.float":
+		captures := make([]float.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			value, err := bsonCaptureFloat(capture, "float")
+			if err != nil {
+				return nil, err
+			}
+			captures[i] = float.NewCapture(capture.Time, value)
+		}
+		collection = float.NewCollection(doc.Name, captures)
+
+	case "recolude.int":
+		captures := make([]integer.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var value int64
+			switch capture.Data.Type {
+			case bson.TypeInt32:
+				value = int64(capture.Data.Int32())
+			case bson.TypeInt64:
+				value = capture.Data.Int64()
+			default:
+				return nil, errors.New("int capture data must be integer")
+			}
+			captures[i] = integer.NewCapture(capture.Time, value)
+		}
+		collection = integer.NewCollection(doc.Name, captures)
+
+	case "recolude.bool":
+		captures := make([]boolean.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			value, ok := capture.Data.BooleanOK()
+			if !ok {
+				return nil, errors.New("bool capture data must be boolean")
+			}
+			captures[i] = boolean.NewCapture(capture.Time, value)
+		}
+		collection = boolean.NewCollection(doc.Name, captures)
+
+	case "recolude.enum":
+		members := append([]string{}, doc.Members...)
+		memberIndices := make(map[string]int, len(members))
+		for i, member := range members {
+			memberIndices[member] = i
+		}
+
+		captures := make([]enum.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			member, ok := capture.Data.StringValueOK()
+			if !ok {
+				return nil, errors.New("enum capture data must be string")
+			}
+
+			index, ok := memberIndices[member]
+			if !ok {
+				index = len(members)
+				memberIndices[member] = index
+				members = append(members, member)
+			}
+			captures[i] = enum.NewCapture(capture.Time, index)
+		}
+		collection = enum.NewCollection(doc.Name, members, captures)
+
+	case "recolude.event":
+		captures := make([]event.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var data bsonMetadataCapture
+			if err := unmarshalCaptureData(capture, "event", &data); err != nil {
+				return nil, err
+			}
+
+			block, err := metadataFromBSON(data.Metadata, data.MetadataEncoded)
+			if err != nil {
+				return nil, err
+			}
+			captures[i] = event.NewCapture(capture.Time, data.Name, block)
+		}
+		collection = event.NewCollection(doc.Name, captures)
+
+	case "recolude.span":
+		captures := make([]span.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var data bsonMetadataCapture
+			if err := unmarshalCaptureData(capture, "span", &data); err != nil {
+				return nil, err
+			}
+
+			if data.End == nil {
+				return nil, errors.New("span capture requires end property")
+			}
+
+			if *data.End < capture.Time {
+				return nil, errors.New("span capture end must not come before its time")
+			}
+
+			block, err := metadataFromBSON(data.Metadata, data.MetadataEncoded)
+			if err != nil {
+				return nil, err
+			}
+			captures[i] = span.NewCapture(capture.Time, *data.End, data.Label, block)
+		}
+		collection = span.NewCollection(doc.Name, captures)
+
+	case "recolude.gaze":
+		captures := make([]gaze.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var data bsonGazeCapture
+			if err := unmarshalCaptureData(capture, "gaze", &data); err != nil {
+				return nil, err
+			}
+			captures[i] = gaze.NewCapture(
+				capture.Time,
+				data.Origin.vector(),
+				data.Direction.vector(),
+				data.LeftOpenness,
+				data.RightOpenness,
+				data.PupilDiameter,
+				data.Confidence,
+			)
+		}
+		collection = gaze.NewCollection(doc.Name, captures)
+
+	case "recolude.skeleton":
+		joints := make([]skeleton.Joint, len(doc.Skeleton))
+		for i, joint := range doc.Skeleton {
+			joints[i] = skeleton.Joint{Name: joint.Name, Parent: joint.Parent, Offset: joint.Offset.vector()}
+		}
+
+		definition, err := skeleton.NewSkeleton(joints)
+		if err != nil {
+			return nil, err
+		}
+
+		captures := make([]skeleton.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var data bsonSkeletonCapture
+			if err := unmarshalCaptureData(capture, "skeleton", &data); err != nil {
+				return nil, err
+			}
+
+			if len(data.Rotations) != definition.JointCount() {
+				return nil, fmt.Errorf("skeleton capture has %d rotations but skeleton has %d joints", len(data.Rotations), definition.JointCount())
+			}
+
+			rotations := make([]vector.Vector3, len(data.Rotations))
+			for j, rotation := range data.Rotations {
+				rotations[j] = rotation.vector()
+			}
+			captures[i] = skeleton.NewCapture(capture.Time, data.Root.vector(), rotations)
+		}
+		collection = skeleton.NewCollection(doc.Name, definition, captures)
+
+	case "recolude.floatvec":
+		captures := make([]floatvec.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			var values []float64
+			if capture.Data.Type != bson.TypeArray || capture.Data.Unmarshal(&values) != nil {
+				return nil, errors.New("floatvec capture data must be an array of numbers")
+			}
+
+			if len(values) != len(doc.Channels) {
+				return nil, fmt.Errorf("floatvec capture has %d values but collection has %d channels", len(values), len(doc.Channels))
+			}
+			captures[i] = floatvec.NewCapture(capture.Time, values)
+		}
+		collection = floatvec.NewCollection(doc.Name, doc.Channels, captures)
+
+	case "recolude.struct":
+		fields := make([]structure.Field, len(doc.Schema))
+		for i, field := range doc.Schema {
+			fieldType, err := structure.ParseFieldType(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = structure.Field{Name: field.Name, Type: fieldType, Options: field.Options}
+		}
+
+		schema, err := structure.NewSchema(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		captures := make([]structure.Capture, len(doc.Captures))
+		for i, capture := range doc.Captures {
+			data, ok := capture.Data.DocumentOK()
+			if !ok {
+				return nil, errors.New("struct capture data must be a document")
+			}
+
+			values := make([]interface{}, len(schema.Fields()))
+			for fieldIndex, field := range schema.Fields() {
+				value, err := data.LookupErr(field.Name)
+				if err != nil {
+					return nil, fmt.Errorf("struct capture requires %s property", field.Name)
+				}
+
+				values[fieldIndex], err = bsonStructValue(field, value)
+				if err != nil {
+					return nil, err
+				}
+			}
+			captures[i] = structure.NewCapture(capture.Time, values)
+		}
+		collection = structure.NewCollection(doc.Name, schema, captures)
+
+	default:
+		return nil, fmt.Errorf("unrecognized collection type: '%s'", doc.Type)
+	}
+
+	block, err := metadataFromBSON(doc.Metadata, doc.MetadataEncoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection.WithMetadata(block), nil
+}
+
+func recordingFromBSON(doc bsonRecording) (format.Recording, error) {
+	block, err := metadataFromBSON(doc.Metadata, doc.MetadataEncoded)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]format.CaptureCollection, len(doc.Collections))
+	for i, collection := range doc.Collections {
+		collections[i], err = collectionFromBSON(collection)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	binaries := make([]format.Binary, len(doc.Binaries))
+	for i, binary := range doc.Binaries {
+		binaryMetadata, err := metadataFromBSON(binary.Metadata, binary.MetadataEncoded)
+		if err != nil {
+			return nil, err
+		}
+		binaries[i] = io.NewBinary(binary.Name, binary.Data, binaryMetadata)
+	}
+
+	references := make([]format.BinaryReference, len(doc.References))
+	for i, reference := range doc.References {
+		if reference.Size < 0 {
+			return nil, fmt.Errorf("reference size must be non-negative")
+		}
+
+		referenceMetadata, err := metadataFromBSON(reference.Metadata, reference.MetadataEncoded)
+		if err != nil {
+			return nil, err
+		}
+		references[i] = io.NewBinaryReference(reference.Name, reference.URI, uint64(reference.Size), referenceMetadata)
+	}
+
+	subRecordings := make([]format.Recording, len(doc.Recordings))
+	for i, child := range doc.Recordings {
+		subRecordings[i], err = recordingFromBSON(child)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return format.NewRecording(
+		doc.ID,
+		doc.Name,
+		collections,
+		subRecordings,
+		block,
+		binaries,
+		references,
+	), nil
+}
+
+// FromBSON builds a recording from a BSON document produced by ToBSON.
+func FromBSON(bsonData []byte) (format.Recording, error) {
+	if err := bson.Raw(bsonData).Validate(); err != nil {
+		return nil, err
+	}
+
+	var doc bsonRecording
+	if err := bson.Unmarshal(bsonData, &doc); err != nil {
+		return nil, err
+	}
+	return recordingFromBSON(doc)
+}
@@ -0,0 +1,267 @@
+package parsing_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/euler"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/float"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/format/parsing"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func everyCollection(t *testing.T) []format.CaptureCollection {
+	arm, err := skeleton.NewSkeleton([]skeleton.Joint{
+		{Name: "shoulder", Parent: -1},
+		{Name: "elbow", Parent: 0, Offset: vector.NewVector3(0, 0, 1)},
+	})
+	assert.NoError(t, err)
+
+	schema, err := structure.NewSchema([]structure.Field{
+		{Name: "health", Type: structure.Float},
+		{Name: "ammo", Type: structure.Int},
+		{Name: "grounded", Type: structure.Bool},
+		{Name: "velocity", Type: structure.Vector3},
+		{Name: "zone", Type: structure.String},
+		{Name: "weapon", Type: structure.Enum, Options: []string{"sword", "bow"}},
+	})
+	assert.NoError(t, err)
+
+	return []format.CaptureCollection{
+		position.NewCollection("position", []position.Capture{position.NewCapture(1, 2, 3, 4)}),
+		euler.NewCollection("euler", []euler.Capture{euler.NewEulerZXYCapture(1, 90, 0, 45)}),
+		vector2.NewCollection("vector2", []vector2.Capture{vector2.NewCapture(1, 2, 3)}),
+		float.NewCollection("float", []float.Capture{float.NewCapture(1, 0.5)}),
+		integer.NewCollection("int", []integer.Capture{integer.NewCapture(1, math.MaxInt64)}),
+		boolean.NewCollection("bool", []boolean.Capture{boolean.NewCapture(1, true), boolean.NewCapture(2, false)}),
+		enum.NewCollection("enum", []string{"idle", "running", "unused"}, []enum.Capture{enum.NewCapture(1, 1), enum.NewCapture(2, 0)}),
+		event.NewCollection("event", []event.Capture{
+			event.NewCapture(1, "hit", metadata.NewBlock(map[string]metadata.Property{
+				"damage": metadata.NewByteProperty(12),
+			})),
+		}),
+		span.NewCollection("span", []span.Capture{
+			span.NewCapture(1, 3, "ability", metadata.NewBlock(map[string]metadata.Property{
+				"name": metadata.NewStringProperty("dash"),
+			})),
+		}),
+		gaze.NewCollection("gaze", []gaze.Capture{
+			gaze.NewCapture(1, vector.NewVector3(0, 1, 0), vector.NewVector3(0, 0, 1), 1, 0.5, 3, 0.9),
+		}),
+		skeleton.NewCollection("skeleton", arm, []skeleton.Capture{
+			skeleton.NewCapture(1, vector.NewVector3(1, 2, 3), []vector.Vector3{vector.NewVector3(0, 90, 0), vector.NewVector3(45, 0, 0)}),
+		}),
+		floatvec.NewCollection("floatvec", []string{"a", "b"}, []floatvec.Capture{floatvec.NewCapture(1, []float64{1, 2})}),
+		structure.NewCollection("struct", schema, []structure.Capture{
+			structure.NewCapture(1, []interface{}{87.5, int64(12), true, vector.NewVector3(1, 0, -2), "castle", 1}),
+		}),
+	}
+}
+
+func Test_BSON_RoundTrip(t *testing.T) {
+	// ARRANGE ================================================================
+	exactMetadata := metadata.NewBlock(map[string]metadata.Property{
+		"byte":    metadata.NewByteProperty(7),
+		"float32": metadata.NewFloat32Property(1.1),
+		"float64": metadata.NewFloat64Property(1.5),
+		"time":    metadata.NewTimeProperty(time.UnixMicro(1634567890123456)),
+		"uint":    metadata.NewUintProperty(3),
+		"empty":   metadata.NewInt64ArrayProperty(nil),
+		"vec":     metadata.NewVector2Property(1, 2),
+	})
+
+	collections := everyCollection(t)
+	collections[0] = collections[0].WithMetadata(exactMetadata)
+
+	original := format.NewRecording(
+		"id",
+		"name",
+		collections,
+		[]format.Recording{
+			format.NewRecording("child", "child name", []format.CaptureCollection{}, []format.Recording{}, metadata.EmptyBlock(), []format.Binary{}, []format.BinaryReference{}),
+		},
+		exactMetadata,
+		[]format.Binary{io.NewBinary("bin", []byte{1, 2, 3}, exactMetadata)},
+		[]format.BinaryReference{io.NewBinaryReference("ref", "https://example.com", 300, exactMetadata)},
+	)
+
+	// ACT ====================================================================
+	data, toErr := parsing.ToBSON(original)
+	back, fromErr := parsing.FromBSON(data)
+
+	// ASSERT =================================================================
+	assert.NoError(t, toErr)
+	assert.NoError(t, fromErr)
+	assert.Equal(t, original, back)
+}
+
+func Test_BSON_MetadataIsQueryable(t *testing.T) {
+	// ARRANGE ================================================================
+	original := format.NewRecording(
+		"id",
+		"name",
+		nil,
+		nil,
+		metadata.NewBlock(map[string]metadata.Property{
+			"level": metadata.NewStringProperty("castle"),
+			"score": metadata.NewIntProperty(12),
+		}),
+		nil,
+		nil,
+	)
+
+	// ACT ====================================================================
+	data, err := parsing.ToBSON(original)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, "castle", bson.Raw(data).Lookup("metadata", "level").StringValue())
+	assert.Equal(t, int32(12), bson.Raw(data).Lookup("metadata", "score").Int32())
+}
+
+func Test_BSON_FromHandWrittenDocument(t *testing.T) {
+	// ARRANGE ================================================================
+	data, err := bson.Marshal(bson.D{
+		{Key: "id", Value: "my id"},
+		{Key: "name", Value: "my name"},
+		{Key: "metadata", Value: bson.D{{Key: "level", Value: "castle"}, {Key: "attempts", Value: int64(3)}}},
+		{Key: "collections", Value: bson.A{
+			bson.D{
+				{Key: "name", Value: "state"},
+				{Key: "type", Value: "recolude.enum"},
+				{Key: "captures", Value: bson.A{
+					bson.D{{Key: "time", Value: 1.}, {Key: "data", Value: "idle"}},
+					bson.D{{Key: "time", Value: 2.}, {Key: "data", Value: "running"}},
+					bson.D{{Key: "time", Value: 3.}, {Key: "data", Value: "idle"}},
+				}},
+			},
+		}},
+	})
+	assert.NoError(t, err)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromBSON(data)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	if assert.NotNil(t, recording) {
+		assert.Equal(t, "my id", recording.ID())
+		assert.Equal(t, "castle", recording.Metadata().Mapping()["level"].String())
+		assert.Equal(t, metadata.NewInt64Property(3), recording.Metadata().Mapping()["attempts"])
+		if assert.Len(t, recording.CaptureCollections(), 1) {
+			collection := recording.CaptureCollections()[0].(enum.Collection)
+			assert.Equal(t, []string{"idle", "running"}, collection.EnumMembers())
+			assert.Equal(t, 3, collection.Length())
+		}
+	}
+}
+
+func Test_BSON_EditedMetadata(t *testing.T) {
+	// ARRANGE ================================================================
+	original := format.NewRecording(
+		"id",
+		"name",
+		nil,
+		nil,
+		metadata.NewBlock(map[string]metadata.Property{
+			"level": metadata.NewStringProperty("castle"),
+		}),
+		nil,
+		nil,
+	)
+	data, err := parsing.ToBSON(original)
+	assert.NoError(t, err)
+
+	var doc bson.M
+	assert.NoError(t, bson.Unmarshal(data, &doc))
+	doc["metadata"] = bson.M{"level": "dungeon"}
+	edited, err := bson.Marshal(doc)
+	assert.NoError(t, err)
+
+	delete(doc, "metadataEncoded")
+	editedWithoutEncoded, err := bson.Marshal(doc)
+	assert.NoError(t, err)
+
+	// ACT ====================================================================
+	conflicted, conflictErr := parsing.FromBSON(edited)
+	back, backErr := parsing.FromBSON(editedWithoutEncoded)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, conflictErr, "metadata document does not match metadataEncoded, remove metadataEncoded to keep the document's values")
+	assert.Nil(t, conflicted)
+	assert.NoError(t, backErr)
+	if assert.NotNil(t, back) {
+		assert.Equal(t, "dungeon", back.Metadata().Mapping()["level"].String())
+	}
+}
+
+func Test_BSON_Errors(t *testing.T) {
+	tests := map[string]struct {
+		collection bson.D
+		err        string
+	}{
+		"unknown type": {
+			collection: bson.D{{Key: "name", Value: "a"}, {Key: "type", Value: "recolude.unknown"}},
+			err:        "unrecognized collection type: 'recolude.unknown'",
+		},
+		"bad int": {
+			collection: bson.D{
+				{Key: "type", Value: "recolude.int"},
+				{Key: "captures", Value: bson.A{bson.D{{Key: "time", Value: 1.}, {Key: "data", Value: "1"}}}},
+			},
+			err: "int capture data must be integer",
+		},
+		"span ends early": {
+			collection: bson.D{
+				{Key: "type", Value: "recolude.span"},
+				{Key: "captures", Value: bson.A{bson.D{{Key: "time", Value: 2.}, {Key: "data", Value: bson.D{{Key: "end", Value: 1.}}}}}},
+			},
+			err: "span capture end must not come before its time",
+		},
+		"floatvec channel mismatch": {
+			collection: bson.D{
+				{Key: "type", Value: "recolude.floatvec"},
+				{Key: "channels", Value: bson.A{"a"}},
+				{Key: "captures", Value: bson.A{bson.D{{Key: "time", Value: 1.}, {Key: "data", Value: bson.A{1., 2.}}}}},
+			},
+			err: "floatvec capture has 2 values but collection has 1 channels",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := bson.Marshal(bson.D{{Key: "collections", Value: bson.A{tc.collection}}})
+			assert.NoError(t, err)
+
+			recording, err := parsing.FromBSON(data)
+			assert.EqualError(t, err, tc.err)
+			assert.Nil(t, recording)
+		})
+	}
+}
+
+func Test_BSON_InvalidDocument(t *testing.T) {
+	// ACT ====================================================================
+	recording, err := parsing.FromBSON([]byte{1, 2, 3})
+
+	// ASSERT =================================================================
+	assert.Error(t, err)
+	assert.Nil(t, recording)
+}
@@ -1,6 +1,8 @@
 package parsing
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -8,10 +10,18 @@ import (
 	"github.com/EliCDavis/vector"
 	"github.com/Jeffail/gabs"
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/boolean"
 	"github.com/recolude/rap/format/collection/enum"
 	"github.com/recolude/rap/format/collection/euler"
 	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
 	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
 	"github.com/recolude/rap/format/io"
 	"github.com/recolude/rap/format/metadata"
 )
@@ -167,13 +177,26 @@ func parseRequiredStringKey(jsonObj *gabs.Container, thing string, key string) (
 // 	return parsedInt, nil
 // }
 
+// toFloat interprets a JSON number. Numbers are parsed as json.Number to
+// preserve the precision of large integers.
+func toFloat(data interface{}) (float64, bool) {
+	switch n := data.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func parseRequiredFloatKey(jsonObj *gabs.Container, thing string, key string) (float64, error) {
 	node := jsonObj.Path(key)
 	if node == nil {
 		return 0, fmt.Errorf("%s requires %s property", thing, key)
 	}
 
-	id, idParsed := node.Data().(float64)
+	id, idParsed := toFloat(node.Data())
 	if !idParsed {
 		return 0, fmt.Errorf("%s %s must be number", thing, key)
 	}
@@ -187,7 +210,7 @@ func parseCaptureTime(jsonCapture *gabs.Container) (float64, error) {
 		return -1, fmt.Errorf("capture object must contain time property")
 	}
 
-	time, correct := timeNode.Data().(float64)
+	time, correct := toFloat(timeNode.Data())
 	if !correct {
 		return -1, fmt.Errorf("capture object's time property must be a number")
 	}
@@ -196,17 +219,21 @@ func parseCaptureTime(jsonCapture *gabs.Container) (float64, error) {
 }
 
 func parseVector3(jsonObj *gabs.Container) (vector.Vector3, error) {
-	x, err := parseRequiredFloatKey(jsonObj, "position capture", "x")
+	return parseNamedVector3(jsonObj, "position capture")
+}
+
+func parseNamedVector3(jsonObj *gabs.Container, thing string) (vector.Vector3, error) {
+	x, err := parseRequiredFloatKey(jsonObj, thing, "x")
 	if err != nil {
 		return vector.Vector3Zero(), err
 	}
 
-	y, err := parseRequiredFloatKey(jsonObj, "position capture", "y")
+	y, err := parseRequiredFloatKey(jsonObj, thing, "y")
 	if err != nil {
 		return vector.Vector3Zero(), err
 	}
 
-	z, err := parseRequiredFloatKey(jsonObj, "position capture", "z")
+	z, err := parseRequiredFloatKey(jsonObj, thing, "z")
 	if err != nil {
 		return vector.Vector3Zero(), err
 	}
@@ -234,6 +261,56 @@ func parsePositionCollection(name string, jsonCaptures []*gabs.Container) (forma
 	return position.NewCollection(name, captures), nil
 }
 
+func parseVector2Collection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	captures := make([]vector2.Capture, len(jsonCaptures))
+
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := parseRequiredFloatKey(jsonCapture.Path("data"), "vector2 capture", "x")
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := parseRequiredFloatKey(jsonCapture.Path("data"), "vector2 capture", "y")
+		if err != nil {
+			return nil, err
+		}
+
+		captures[i] = vector2.NewCapture(time, x, y)
+	}
+
+	return vector2.NewCollection(name, captures), nil
+}
+
+func parseBoolCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	captures := make([]boolean.Capture, len(jsonCaptures))
+
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("bool capture requires data property")
+		}
+
+		value, ok := dataNode.Data().(bool)
+		if !ok {
+			return nil, errors.New("bool capture data must be boolean")
+		}
+
+		captures[i] = boolean.NewCapture(time, value)
+	}
+
+	return boolean.NewCollection(name, captures), nil
+}
+
 func parseEulerCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
 	captures := make([]euler.Capture, len(jsonCaptures))
 
@@ -284,6 +361,36 @@ func parseEnumCollection(name string, jsonCaptures []*gabs.Container) (format.Ca
 	return enum.NewCollection(name, allEnumEntries, captures), nil
 }
 
+func parseIntCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	captures := make([]integer.Capture, len(jsonCaptures))
+
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("int capture requires data property")
+		}
+
+		number, ok := dataNode.Data().(json.Number)
+		if !ok {
+			return nil, errors.New("int capture data must be integer")
+		}
+
+		value, err := strconv.ParseInt(number.String(), 10, 64)
+		if err != nil {
+			return nil, errors.New("int capture data must be integer")
+		}
+
+		captures[i] = integer.NewCapture(time, value)
+	}
+
+	return integer.NewCollection(name, captures), nil
+}
+
 func parseEventCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
 	captures := make([]event.Capture, len(jsonCaptures))
 
@@ -314,6 +421,392 @@ func parseEventCollection(name string, jsonCaptures []*gabs.Container) (format.C
 	return event.NewCollection(name, captures), nil
 }
 
+func parseSpanCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	captures := make([]span.Capture, len(jsonCaptures))
+
+	for i, jsonCapture := range jsonCaptures {
+		start, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("span capture requires data property object")
+		}
+
+		end, err := parseRequiredFloatKey(dataNode, "span capture", "end")
+		if err != nil {
+			return nil, err
+		}
+
+		if end < start {
+			return nil, errors.New("span capture end must not come before its time")
+		}
+
+		label, err := parseRequiredStringKey(dataNode, "span capture", "label")
+		if err != nil {
+			return nil, err
+		}
+
+		parsedMetadata, err := parseMetadata(dataNode)
+		if err != nil {
+			return nil, err
+		}
+
+		captures[i] = span.NewCapture(start, end, label, parsedMetadata)
+	}
+
+	return span.NewCollection(name, captures), nil
+}
+
+func parseSkeletonDefinition(jsonObj *gabs.Container) (skeleton.Skeleton, error) {
+	skeletonNode := jsonObj.Path("skeleton")
+	if skeletonNode == nil {
+		return skeleton.Skeleton{}, errors.New("skeleton collection requires skeleton property")
+	}
+
+	jointNodes, err := skeletonNode.Children()
+	if _, properInternal := skeletonNode.Data().([]interface{}); err != nil || !properInternal {
+		return skeleton.Skeleton{}, errors.New("skeleton property must be an array")
+	}
+
+	joints := make([]skeleton.Joint, len(jointNodes))
+	for i, jointNode := range jointNodes {
+		name, err := parseRequiredStringKey(jointNode, "skeleton joint", "name")
+		if err != nil {
+			return skeleton.Skeleton{}, err
+		}
+
+		parent, err := parseRequiredFloatKey(jointNode, "skeleton joint", "parent")
+		if err != nil {
+			return skeleton.Skeleton{}, err
+		}
+
+		if parent != float64(int(parent)) {
+			return skeleton.Skeleton{}, errors.New("skeleton joint parent must be integer")
+		}
+
+		offset := vector.Vector3Zero()
+		if offsetNode := jointNode.Path("offset"); offsetNode != nil {
+			offset, err = parseNamedVector3(offsetNode, "skeleton joint offset")
+			if err != nil {
+				return skeleton.Skeleton{}, err
+			}
+		}
+
+		joints[i] = skeleton.Joint{Name: name, Parent: int(parent), Offset: offset}
+	}
+
+	return skeleton.NewSkeleton(joints)
+}
+
+func parseSkeletonCollection(name string, jsonObj *gabs.Container, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	definition, err := parseSkeletonDefinition(jsonObj)
+	if err != nil {
+		return nil, err
+	}
+
+	captures := make([]skeleton.Capture, len(jsonCaptures))
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("skeleton capture requires data property object")
+		}
+
+		rootNode := dataNode.Path("root")
+		if rootNode == nil {
+			return nil, errors.New("skeleton capture requires root property")
+		}
+
+		root, err := parseNamedVector3(rootNode, "skeleton capture root")
+		if err != nil {
+			return nil, err
+		}
+
+		rotationsNode := dataNode.Path("rotations")
+		if rotationsNode == nil {
+			return nil, errors.New("skeleton capture requires rotations property")
+		}
+
+		rotationNodes, err := rotationsNode.Children()
+		if _, properInternal := rotationsNode.Data().([]interface{}); err != nil || !properInternal {
+			return nil, errors.New("skeleton capture rotations must be an array")
+		}
+
+		if len(rotationNodes) != definition.JointCount() {
+			return nil, fmt.Errorf("skeleton capture has %d rotations but skeleton has %d joints", len(rotationNodes), definition.JointCount())
+		}
+
+		rotations := make([]vector.Vector3, len(rotationNodes))
+		for j, rotationNode := range rotationNodes {
+			rotations[j], err = parseNamedVector3(rotationNode, "skeleton capture rotation")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		captures[i] = skeleton.NewCapture(time, root, rotations)
+	}
+
+	return skeleton.NewCollection(name, definition, captures), nil
+}
+
+func parseGazeCollection(name string, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	captures := make([]gaze.Capture, len(jsonCaptures))
+
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("gaze capture requires data property object")
+		}
+
+		originNode := dataNode.Path("origin")
+		if originNode == nil {
+			return nil, errors.New("gaze capture requires origin property")
+		}
+
+		origin, err := parseNamedVector3(originNode, "gaze capture origin")
+		if err != nil {
+			return nil, err
+		}
+
+		directionNode := dataNode.Path("direction")
+		if directionNode == nil {
+			return nil, errors.New("gaze capture requires direction property")
+		}
+
+		direction, err := parseNamedVector3(directionNode, "gaze capture direction")
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]float64, 4)
+		for valueIndex, key := range []string{"leftOpenness", "rightOpenness", "pupilDiameter", "confidence"} {
+			values[valueIndex], err = parseRequiredFloatKey(dataNode, "gaze capture", key)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		captures[i] = gaze.NewCapture(time, origin, direction, values[0], values[1], values[2], values[3])
+	}
+
+	return gaze.NewCollection(name, captures), nil
+}
+
+func parseFloatVecCollection(name string, jsonObj *gabs.Container, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	channelsNode := jsonObj.Path("channels")
+	if channelsNode == nil {
+		return nil, errors.New("floatvec collection requires channels property")
+	}
+
+	channelNodes, err := channelsNode.Children()
+	if _, properInternal := channelsNode.Data().([]interface{}); err != nil || !properInternal {
+		return nil, errors.New("floatvec collection channels must be an array")
+	}
+
+	channels := make([]string, len(channelNodes))
+	for i, channelNode := range channelNodes {
+		channel, ok := channelNode.Data().(string)
+		if !ok {
+			return nil, errors.New("floatvec collection channels must be strings")
+		}
+		channels[i] = channel
+	}
+
+	captures := make([]floatvec.Capture, len(jsonCaptures))
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil {
+			return nil, errors.New("floatvec capture requires data property")
+		}
+
+		valueNodes, err := dataNode.Children()
+		if _, properInternal := dataNode.Data().([]interface{}); err != nil || !properInternal {
+			return nil, errors.New("floatvec capture data must be an array")
+		}
+
+		if len(valueNodes) != len(channels) {
+			return nil, fmt.Errorf("floatvec capture has %d values but collection has %d channels", len(valueNodes), len(channels))
+		}
+
+		values := make([]float64, len(valueNodes))
+		for valueIndex, valueNode := range valueNodes {
+			value, ok := toFloat(valueNode.Data())
+			if !ok {
+				return nil, errors.New("floatvec capture data must be numbers")
+			}
+			values[valueIndex] = value
+		}
+
+		captures[i] = floatvec.NewCapture(time, values)
+	}
+
+	return floatvec.NewCollection(name, channels, captures), nil
+}
+
+func parseStructSchema(jsonObj *gabs.Container) (structure.Schema, error) {
+	schemaNode := jsonObj.Path("schema")
+	if schemaNode == nil {
+		return structure.Schema{}, errors.New("struct collection requires schema property")
+	}
+
+	fieldNodes, err := schemaNode.Children()
+	if _, properInternal := schemaNode.Data().([]interface{}); err != nil || !properInternal {
+		return structure.Schema{}, errors.New("struct collection schema must be an array")
+	}
+
+	fields := make([]structure.Field, len(fieldNodes))
+	for i, fieldNode := range fieldNodes {
+		name, err := parseRequiredStringKey(fieldNode, "struct field", "name")
+		if err != nil {
+			return structure.Schema{}, err
+		}
+
+		typeName, err := parseRequiredStringKey(fieldNode, "struct field", "type")
+		if err != nil {
+			return structure.Schema{}, err
+		}
+
+		fieldType, err := structure.ParseFieldType(typeName)
+		if err != nil {
+			return structure.Schema{}, err
+		}
+
+		fields[i] = structure.Field{Name: name, Type: fieldType}
+
+		optionsNode := fieldNode.Path("options")
+		if optionsNode == nil {
+			continue
+		}
+
+		optionNodes, err := optionsNode.Children()
+		if _, properInternal := optionsNode.Data().([]interface{}); err != nil || !properInternal {
+			return structure.Schema{}, fmt.Errorf("struct field %s options must be an array", name)
+		}
+
+		fields[i].Options = make([]string, len(optionNodes))
+		for optionIndex, optionNode := range optionNodes {
+			option, ok := optionNode.Data().(string)
+			if !ok {
+				return structure.Schema{}, fmt.Errorf("struct field %s options must be strings", name)
+			}
+			fields[i].Options[optionIndex] = option
+		}
+	}
+
+	return structure.NewSchema(fields)
+}
+
+func parseStructValue(field structure.Field, node *gabs.Container) (interface{}, error) {
+	switch field.Type {
+	case structure.Float:
+		value, ok := toFloat(node.Data())
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be number", field.Name)
+		}
+		return value, nil
+
+	case structure.Int:
+		number, ok := node.Data().(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be integer", field.Name)
+		}
+
+		value, err := strconv.ParseInt(number.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("struct field %s must be integer", field.Name)
+		}
+		return value, nil
+
+	case structure.Bool:
+		value, ok := node.Data().(bool)
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be boolean", field.Name)
+		}
+		return value, nil
+
+	case structure.Vector3:
+		return parseNamedVector3(node, "struct field "+field.Name)
+
+	case structure.String:
+		value, ok := node.Data().(string)
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be string", field.Name)
+		}
+		return value, nil
+
+	case structure.Enum:
+		value, ok := node.Data().(string)
+		if !ok {
+			return nil, fmt.Errorf("struct field %s must be string", field.Name)
+		}
+
+		for i, option := range field.Options {
+			if option == value {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("struct field %s has no option '%s'", field.Name, value)
+	}
+
+	return nil, fmt.Errorf("struct field %s has unknown type %d", field.Name, int(field.Type))
+}
+
+func parseStructCollection(name string, jsonObj *gabs.Container, jsonCaptures []*gabs.Container) (format.CaptureCollection, error) {
+	schema, err := parseStructSchema(jsonObj)
+	if err != nil {
+		return nil, err
+	}
+
+	captures := make([]structure.Capture, len(jsonCaptures))
+	for i, jsonCapture := range jsonCaptures {
+		time, err := parseCaptureTime(jsonCapture)
+		if err != nil {
+			return nil, err
+		}
+
+		dataNode := jsonCapture.Path("data")
+		if dataNode == nil || isNotJSONObj(dataNode) {
+			return nil, errors.New("struct capture requires data property object")
+		}
+
+		values := make([]interface{}, len(schema.Fields()))
+		for fieldIndex, field := range schema.Fields() {
+			// Search rather than Path, as field names may contain periods
+			valueNode := dataNode.Search(field.Name)
+			if valueNode == nil {
+				return nil, fmt.Errorf("struct capture requires %s property", field.Name)
+			}
+
+			values[fieldIndex], err = parseStructValue(field, valueNode)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		captures[i] = structure.NewCapture(time, values)
+	}
+
+	return structure.NewCollection(name, schema, captures), nil
+}
+
 func parseCollectionFromJSON(jsonObj *gabs.Container) (format.CaptureCollection, error) {
 	name, err := parseRequiredStringKey(jsonObj, "collection", "name")
 	if err != nil {
@@ -340,20 +833,58 @@ func parseCollectionFromJSON(jsonObj *gabs.Container) (format.CaptureCollection,
 		return nil, errors.New("collection's captures property must be an array")
 	}
 
+	collectionMetadata, err := parseMetadata(jsonObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection format.CaptureCollection
 	switch collectionType {
 	case "recolude.position":
-		return parsePositionCollection(name, childCaptures)
+		collection, err = parsePositionCollection(name, childCaptures)
 
 	case "recolude.euler":
-		return parseEulerCollection(name, childCaptures)
+		collection, err = parseEulerCollection(name, childCaptures)
 
 	case "recolude.event":
-		return parseEventCollection(name, childCaptures)
+		collection, err = parseEventCollection(name, childCaptures)
 
 	case "recolude.enum":
-		return parseEnumCollection(name, childCaptures)
+		collection, err = parseEnumCollection(name, childCaptures)
+
+	case "recolude.int":
+		collection, err = parseIntCollection(name, childCaptures)
+
+	case "recolude.vector2":
+		collection, err = parseVector2Collection(name, childCaptures)
+
+	case "recolude.bool":
+		collection, err = parseBoolCollection(name, childCaptures)
+
+	case "recolude.span":
+		collection, err = parseSpanCollection(name, childCaptures)
+
+	case "recolude.skeleton":
+		collection, err = parseSkeletonCollection(name, jsonObj, childCaptures)
+
+	case "recolude.gaze":
+		collection, err = parseGazeCollection(name, childCaptures)
+
+	case "recolude.floatvec":
+		collection, err = parseFloatVecCollection(name, jsonObj, childCaptures)
+
+	case "recolude.struct":
+		collection, err = parseStructCollection(name, jsonObj, childCaptures)
+
+	default:
+		return nil, fmt.Errorf("unrecognized collection type: '%s'", collectionType)
+	}
+
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unrecognized collection type: '%s'", collectionType)
+
+	return collection.WithMetadata(collectionMetadata), nil
 }
 
 func parseCollectionsFromJSON(jsonObj *gabs.Container) ([]format.CaptureCollection, error) {
@@ -440,7 +971,9 @@ func parseRecordingFromJSON(jsonObj *gabs.Container) (format.Recording, error) {
 }
 
 func FromJSON(jsonData []byte) (format.Recording, error) {
-	rootObj, err := gabs.ParseJSON(jsonData)
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	rootObj, err := gabs.ParseJSONDecoder(decoder)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,18 @@ package parsing_test
 import (
 	"testing"
 
+	"github.com/EliCDavis/vector"
+
+	"github.com/recolude/rap/format/collection/boolean"
 	"github.com/recolude/rap/format/collection/enum"
 	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/floatvec"
+	"github.com/recolude/rap/format/collection/gaze"
+	"github.com/recolude/rap/format/collection/integer"
+	"github.com/recolude/rap/format/collection/skeleton"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/collection/structure"
+	"github.com/recolude/rap/format/collection/vector2"
 	"github.com/recolude/rap/format/metadata"
 	"github.com/recolude/rap/format/parsing"
 
@@ -974,3 +984,651 @@ func Test_JSONObj_EnumCollectionCaptures(t *testing.T) {
 	assert.True(t, isEnum)
 	assert.Equal(t, 0, capture.Value())
 }
+
+func Test_JSONObj_IntCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.int",
+				"name": "Network Tick",
+				"captures": [
+					{
+						"time": 1.3,
+						"data": 9007199254740993
+					},
+					{
+						"time": 1.4,
+						"data": -12
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	assert.NotNil(t, recording)
+
+	assert.Equal(t, 1, len(recording.CaptureCollections()))
+	assert.Equal(t, "Network Tick", recording.CaptureCollections()[0].Name())
+	assert.Equal(t, "recolude.int", recording.CaptureCollections()[0].Signature())
+	if assert.Equal(t, 2, len(recording.CaptureCollections()[0].Captures())) {
+		assert.Equal(t, integer.NewCapture(1.3, 9007199254740993), recording.CaptureCollections()[0].Captures()[0])
+		assert.Equal(t, integer.NewCapture(1.4, -12), recording.CaptureCollections()[0].Captures()[1])
+	}
+}
+
+func Test_JSONObj_IntCollectionFloatCapture_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.int",
+				"name": "Network Tick",
+				"captures": [
+					{
+						"time": 1.3,
+						"data": 1.5
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "int capture data must be integer")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_Vector2CollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.vector2",
+				"name": "Cursor",
+				"captures": [
+					{
+						"time": 1.3,
+						"data": { "x": 12, "y": -3.5 }
+					},
+					{
+						"time": 1.4,
+						"data": { "x": 13, "y": -2 }
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	assert.NotNil(t, recording)
+
+	assert.Equal(t, 1, len(recording.CaptureCollections()))
+	assert.Equal(t, "Cursor", recording.CaptureCollections()[0].Name())
+	assert.Equal(t, "recolude.vector2", recording.CaptureCollections()[0].Signature())
+	if assert.Equal(t, 2, len(recording.CaptureCollections()[0].Captures())) {
+		assert.Equal(t, vector2.NewCapture(1.3, 12, -3.5), recording.CaptureCollections()[0].Captures()[0])
+		assert.Equal(t, vector2.NewCapture(1.4, 13, -2), recording.CaptureCollections()[0].Captures()[1])
+	}
+}
+
+func Test_JSONObj_Vector2CollectionMissingY_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.vector2",
+				"name": "Cursor",
+				"captures": [
+					{
+						"time": 1.3,
+						"data": { "x": 12 }
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "vector2 capture requires y property")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_BoolCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.bool",
+				"name": "Grounded",
+				"captures": [
+					{ "time": 1.3, "data": true },
+					{ "time": 1.4, "data": true },
+					{ "time": 2, "data": false }
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	assert.NotNil(t, recording)
+
+	assert.Equal(t, 1, len(recording.CaptureCollections()))
+	assert.Equal(t, "Grounded", recording.CaptureCollections()[0].Name())
+	assert.Equal(t, "recolude.bool", recording.CaptureCollections()[0].Signature())
+	if assert.Equal(t, 2, len(recording.CaptureCollections()[0].Captures())) {
+		assert.Equal(t, boolean.NewCapture(1.3, true), recording.CaptureCollections()[0].Captures()[0])
+		assert.Equal(t, boolean.NewCapture(2, false), recording.CaptureCollections()[0].Captures()[1])
+	}
+}
+
+func Test_JSONObj_BoolCollectionNonBoolCapture_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.bool",
+				"name": "Grounded",
+				"captures": [
+					{ "time": 1.3, "data": 1 }
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "bool capture data must be boolean")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_SpanCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.span",
+				"name": "Dialog",
+				"captures": [
+					{
+						"time": 1.5,
+						"data": {
+							"end": 4,
+							"label": "Greeting",
+							"metadata": { "speaker": "Guard" }
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	assert.NotNil(t, recording)
+
+	assert.Equal(t, 1, len(recording.CaptureCollections()))
+	assert.Equal(t, "Dialog", recording.CaptureCollections()[0].Name())
+	assert.Equal(t, "recolude.span", recording.CaptureCollections()[0].Signature())
+	if assert.Equal(t, 1, len(recording.CaptureCollections()[0].Captures())) {
+		capture := recording.CaptureCollections()[0].Captures()[0].(span.Capture)
+		assert.Equal(t, 1.5, capture.Start())
+		assert.Equal(t, 4.0, capture.End())
+		assert.Equal(t, "Greeting", capture.Label())
+		assert.Equal(t, "Guard", capture.Metadata().Mapping()["speaker"].String())
+	}
+}
+
+func Test_JSONObj_SpanCollectionEndsBeforeStart_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.span",
+				"name": "Dialog",
+				"captures": [
+					{ "time": 4, "data": { "end": 1, "label": "Greeting" } }
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "span capture end must not come before its time")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_SkeletonCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.skeleton",
+				"name": "Avatar",
+				"skeleton": [
+					{ "name": "Hips", "parent": -1 },
+					{ "name": "Spine", "parent": 0, "offset": { "x": 0, "y": 0.5, "z": 0 } }
+				],
+				"captures": [
+					{
+						"time": 1,
+						"data": {
+							"root": { "x": 1, "y": 2, "z": 3 },
+							"rotations": [
+								{ "x": 0, "y": 90, "z": 0 },
+								{ "x": 10, "y": 0, "z": 0 }
+							]
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.CaptureCollections(), 1) {
+		collection := recording.CaptureCollections()[0].(skeleton.Collection)
+		assert.Equal(t, "Avatar", collection.Name())
+		assert.Equal(t, "Spine", collection.Skeleton().Joints()[1].Name)
+		assert.Equal(t, 0, collection.Skeleton().Joints()[1].Parent)
+		assert.Equal(t, 0.5, collection.Skeleton().Joints()[1].Offset.Y())
+		if assert.Equal(t, 1, collection.Length()) {
+			capture := collection.CaptureAt(0).(skeleton.Capture)
+			assert.Equal(t, 3.0, capture.Root().Z())
+			assert.Equal(t, 90.0, capture.Rotations()[0].Y())
+			assert.Equal(t, 10.0, capture.Rotations()[1].X())
+		}
+	}
+}
+
+func Test_JSONObj_SkeletonCollectionWrongRotationCount_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.skeleton",
+				"name": "Avatar",
+				"skeleton": [ { "name": "Hips", "parent": -1 } ],
+				"captures": [
+					{
+						"time": 1,
+						"data": {
+							"root": { "x": 1, "y": 2, "z": 3 },
+							"rotations": []
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "skeleton capture has 0 rotations but skeleton has 1 joints")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_GazeCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.gaze",
+				"name": "Eyes",
+				"captures": [
+					{
+						"time": 1,
+						"data": {
+							"origin": { "x": 0, "y": 1.6, "z": 0 },
+							"direction": { "x": 0, "y": 0, "z": 2 },
+							"leftOpenness": 0.9,
+							"rightOpenness": 0.8,
+							"pupilDiameter": 3.5,
+							"confidence": 0.95
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.CaptureCollections(), 1) {
+		collection := recording.CaptureCollections()[0]
+		assert.Equal(t, "recolude.gaze", collection.Signature())
+		if assert.Equal(t, 1, collection.Length()) {
+			capture := collection.CaptureAt(0).(gaze.Capture)
+			assert.Equal(t, 1.6, capture.Origin().Y())
+			assert.Equal(t, 1.0, capture.Direction().Z())
+			assert.Equal(t, 0.9, capture.LeftOpenness())
+			assert.Equal(t, 0.8, capture.RightOpenness())
+			assert.Equal(t, 3.5, capture.PupilDiameter())
+			assert.Equal(t, 0.95, capture.Confidence())
+		}
+	}
+}
+
+func Test_JSONObj_GazeCollectionMissingConfidence_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.gaze",
+				"name": "Eyes",
+				"captures": [
+					{
+						"time": 1,
+						"data": {
+							"origin": { "x": 0, "y": 1.6, "z": 0 },
+							"direction": { "x": 0, "y": 0, "z": 1 },
+							"leftOpenness": 0.9,
+							"rightOpenness": 0.8,
+							"pupilDiameter": 3.5
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "gaze capture requires confidence property")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_FloatVecCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.floatvec",
+				"name": "Face",
+				"channels": ["jawOpen", "blinkLeft"],
+				"captures": [
+					{ "time": 1, "data": [0.25, 1] },
+					{ "time": 2, "data": [0.5, 0] }
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.CaptureCollections(), 1) {
+		collection := recording.CaptureCollections()[0].(floatvec.Collection)
+		assert.Equal(t, "recolude.floatvec", collection.Signature())
+		assert.Equal(t, []string{"jawOpen", "blinkLeft"}, collection.Channels())
+		if assert.Equal(t, 2, collection.Length()) {
+			assert.Equal(t, []float64{0.25, 1}, collection.CaptureAt(0).(floatvec.Capture).Values())
+			assert.Equal(t, []float64{0.5, 0}, collection.CaptureAt(1).(floatvec.Capture).Values())
+		}
+	}
+}
+
+func Test_JSONObj_FloatVecCollectionWrongValueCount_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.floatvec",
+				"name": "Face",
+				"channels": ["jawOpen", "blinkLeft"],
+				"captures": [
+					{ "time": 1, "data": [0.25] }
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "floatvec capture has 1 values but collection has 2 channels")
+	assert.Nil(t, recording)
+}
+
+func Test_JSONObj_StructCollectionCaptures(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.struct",
+				"name": "Player",
+				"schema": [
+					{ "name": "health", "type": "float" },
+					{ "name": "ammo", "type": "int" },
+					{ "name": "grounded", "type": "bool" },
+					{ "name": "velocity", "type": "vector3" },
+					{ "name": "zone", "type": "string" },
+					{ "name": "weapon", "type": "enum", "options": ["sword", "bow"] }
+				],
+				"captures": [
+					{
+						"time": 1,
+						"data": {
+							"health": 87.5,
+							"ammo": 12,
+							"grounded": true,
+							"velocity": { "x": 1, "y": 0, "z": -2 },
+							"zone": "castle",
+							"weapon": "bow"
+						}
+					}
+				]
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.CaptureCollections(), 1) {
+		collection := recording.CaptureCollections()[0].(structure.Collection)
+		assert.Equal(t, "recolude.struct", collection.Signature())
+		if assert.Len(t, collection.Schema().Fields(), 6) {
+			assert.Equal(t, structure.Enum, collection.Schema().Fields()[5].Type)
+			assert.Equal(t, []string{"sword", "bow"}, collection.Schema().Fields()[5].Options)
+		}
+		if assert.Equal(t, 1, collection.Length()) {
+			capture := collection.CaptureAt(0).(structure.Capture)
+			assert.Equal(t, 87.5, capture.Value(0))
+			assert.Equal(t, int64(12), capture.Value(1))
+			assert.Equal(t, true, capture.Value(2))
+			assert.Equal(t, vector.NewVector3(1, 0, -2), capture.Value(3))
+			assert.Equal(t, "castle", capture.Value(4))
+			assert.Equal(t, 1, capture.Value(5))
+		}
+	}
+}
+
+func Test_JSONObj_StructCollection_Errors(t *testing.T) {
+	tests := map[string]struct {
+		schema string
+		data   string
+		err    string
+	}{
+		"missing schema": {
+			data: `{}`,
+			err:  "struct collection requires schema property",
+		},
+		"unknown type": {
+			schema: `"schema": [{ "name": "a", "type": "quaternion" }],`,
+			data:   `{}`,
+			err:    "unknown struct field type: 'quaternion'",
+		},
+		"missing field": {
+			schema: `"schema": [{ "name": "a", "type": "float" }],`,
+			data:   `{}`,
+			err:    "struct capture requires a property",
+		},
+		"wrong value type": {
+			schema: `"schema": [{ "name": "a", "type": "int" }],`,
+			data:   `{ "a": 1.5 }`,
+			err:    "struct field a must be integer",
+		},
+		"unknown option": {
+			schema: `"schema": [{ "name": "a", "type": "enum", "options": ["x"] }],`,
+			data:   `{ "a": "y" }`,
+			err:    "struct field a has no option 'y'",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// ARRANGE ========================================================
+			payload := []byte(`{ 
+				"id": "my id", 
+				"name": "my name",
+				"collections": [
+					{
+						"type": "recolude.struct",
+						"name": "Player",
+						` + tc.schema + `
+						"captures": [{ "time": 1, "data": ` + tc.data + ` }]
+					}
+				]
+			}`)
+
+			// ACT ============================================================
+			recording, err := parsing.FromJSON(payload)
+
+			// ASSERT =========================================================
+			assert.EqualError(t, err, tc.err)
+			assert.Nil(t, recording)
+		})
+	}
+}
+
+func Test_JSONObj_CollectionMetadata(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.position",
+				"name": "Position",
+				"metadata": {
+					"units": "meters",
+					"device": "tracker 2"
+				},
+				"captures": []
+			},
+			{
+				"type": "recolude.int",
+				"name": "Score",
+				"captures": []
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.Nil(t, err)
+	if assert.NotNil(t, recording) && assert.Len(t, recording.CaptureCollections(), 2) {
+		assert.Equal(t, metadata.NewStringProperty("meters"), recording.CaptureCollections()[0].Metadata().Mapping()["units"])
+		assert.Equal(t, metadata.NewStringProperty("tracker 2"), recording.CaptureCollections()[0].Metadata().Mapping()["device"])
+		assert.Len(t, recording.CaptureCollections()[1].Metadata().Mapping(), 0)
+	}
+}
+
+func Test_JSONObj_CollectionMetadataNotObject_Errors(t *testing.T) {
+	// ARRANGE ================================================================
+	payload := []byte(`{ 
+		"id": "my id", 
+		"name": "my name",
+		"collections": [
+			{
+				"type": "recolude.position",
+				"name": "Position",
+				"metadata": "meters",
+				"captures": []
+			}
+		]
+	}`)
+
+	// ACT ====================================================================
+	recording, err := parsing.FromJSON(payload)
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "metadata should be object")
+	assert.Nil(t, recording)
+}
@@ -0,0 +1,254 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/metadata"
+)
+
+// Source is what a query selects rows from.
+type Source int
+
+const (
+	// Recordings yields a row for every recording in the tree
+	Recordings Source = iota
+
+	// Collections yields a row for every capture collection in the tree
+	Collections
+
+	// Captures yields a row for every capture of every collection in the tree
+	Captures
+)
+
+func (s Source) String() string {
+	switch s {
+	case Recordings:
+		return "recordings"
+	case Collections:
+		return "collections"
+	case Captures:
+		return "captures"
+	}
+	return fmt.Sprintf("Source(%d)", int(s))
+}
+
+// row is everything a single result of a query can refer to. Collection is
+// nil when selecting from recordings, and capture is nil unless selecting
+// from captures.
+type row struct {
+	path       string
+	depth      int
+	recording  format.Recording
+	collection format.CaptureCollection
+	capture    format.Capture
+	index      int
+}
+
+// accessor pulls a value out of a row. Values are always one of nil, float64,
+// string or bool, with nil meaning the row doesn't have the field.
+type accessor func(r row) interface{}
+
+func metadataValue(block metadata.Block, path string) interface{} {
+	prop, ok := block.Get(path)
+	if !ok {
+		return nil
+	}
+
+	if number, ok := block.GetFloat(path); ok {
+		return number
+	}
+
+	if boolean, ok := block.GetBool(path); ok {
+		return boolean
+	}
+
+	if _, ok := prop.(metadata.NullProperty); ok {
+		return nil
+	}
+
+	return prop.String()
+}
+
+// captureValue is the single value that best describes the capture. Numbers
+// and bools are kept as is, enum captures resolve to their member's name, and
+// everything else falls back to the capture's string form.
+func captureValue(capture format.Capture, collection format.CaptureCollection) interface{} {
+	switch c := capture.(type) {
+	case interface{ Value() float64 }:
+		return c.Value()
+
+	case interface{ Value() int64 }:
+		return float64(c.Value())
+
+	case interface{ Value() bool }:
+		return c.Value()
+
+	case interface{ Value() int }:
+		if enum, ok := collection.(interface{ EnumMembers() []string }); ok {
+			if c.Value() >= 0 && c.Value() < len(enum.EnumMembers()) {
+				return enum.EnumMembers()[c.Value()]
+			}
+		}
+		return float64(c.Value())
+	}
+	return capture.String()
+}
+
+// captureVector is the vector held by position, euler and vector2 captures.
+func captureVector(capture format.Capture) (vector.Vector3, bool) {
+	switch c := capture.(type) {
+	case interface{ Position() vector.Vector3 }:
+		return c.Position(), true
+
+	case interface{ EulerZXY() vector.Vector3 }:
+		return c.EulerZXY(), true
+
+	case interface{ Value() vector.Vector2 }:
+		return vector.NewVector3(c.Value().X(), c.Value().Y(), 0), true
+	}
+	return vector.Vector3{}, false
+}
+
+func componentAccessor(component func(v vector.Vector3) float64, only2D bool) accessor {
+	return func(r row) interface{} {
+		if only2D {
+			if _, is2D := r.capture.(interface{ Value() vector.Vector2 }); is2D {
+				return nil
+			}
+		}
+		v, ok := captureVector(r.capture)
+		if !ok {
+			return nil
+		}
+		return component(v)
+	}
+}
+
+func recordingField(name string) (accessor, bool) {
+	if strings.HasPrefix(name, "metadata.") {
+		key := strings.TrimPrefix(name, "metadata.")
+		return func(r row) interface{} { return metadataValue(r.recording.Metadata(), key) }, true
+	}
+
+	switch name {
+	case "id":
+		return func(r row) interface{} { return r.recording.ID() }, true
+	case "name":
+		return func(r row) interface{} { return r.recording.Name() }, true
+	case "path":
+		return func(r row) interface{} { return r.path }, true
+	case "depth":
+		return func(r row) interface{} { return float64(r.depth) }, true
+	}
+	return nil, false
+}
+
+func collectionField(name string) (accessor, bool) {
+	if strings.HasPrefix(name, "metadata.") {
+		key := strings.TrimPrefix(name, "metadata.")
+		return func(r row) interface{} { return metadataValue(r.collection.Metadata(), key) }, true
+	}
+
+	switch name {
+	case "name":
+		return func(r row) interface{} { return r.collection.Name() }, true
+	case "signature":
+		return func(r row) interface{} { return r.collection.Signature() }, true
+	case "length":
+		return func(r row) interface{} { return float64(r.collection.Length()) }, true
+	case "start":
+		return func(r row) interface{} { return r.collection.Start() }, true
+	case "end":
+		return func(r row) interface{} { return r.collection.End() }, true
+	}
+	return nil, false
+}
+
+func captureField(name string) (accessor, bool) {
+	if strings.HasPrefix(name, "metadata.") {
+		key := strings.TrimPrefix(name, "metadata.")
+		return func(r row) interface{} {
+			if c, ok := r.capture.(interface{ Metadata() metadata.Block }); ok {
+				return metadataValue(c.Metadata(), key)
+			}
+			return nil
+		}, true
+	}
+
+	switch name {
+	case "time":
+		return func(r row) interface{} { return r.capture.Time() }, true
+	case "index":
+		return func(r row) interface{} { return float64(r.index) }, true
+	case "value":
+		return func(r row) interface{} { return captureValue(r.capture, r.collection) }, true
+	case "name":
+		return func(r row) interface{} {
+			if c, ok := r.capture.(interface{ Name() string }); ok {
+				return c.Name()
+			}
+			return nil
+		}, true
+	case "label":
+		return func(r row) interface{} {
+			if c, ok := r.capture.(interface{ Label() string }); ok {
+				return c.Label()
+			}
+			return nil
+		}, true
+	case "duration":
+		return func(r row) interface{} {
+			if c, ok := r.capture.(interface{ Duration() float64 }); ok {
+				return c.Duration()
+			}
+			return nil
+		}, true
+	case "x":
+		return componentAccessor(vector.Vector3.X, false), true
+	case "y":
+		return componentAccessor(vector.Vector3.Y, false), true
+	case "z":
+		return componentAccessor(vector.Vector3.Z, true), true
+	}
+	return nil, false
+}
+
+var scopes = []struct {
+	name    string
+	source  Source
+	resolve func(name string) (accessor, bool)
+}{
+	{name: "recording", source: Recordings, resolve: recordingField},
+	{name: "collection", source: Collections, resolve: collectionField},
+	{name: "capture", source: Captures, resolve: captureField},
+}
+
+// resolveField builds an accessor for the field named. Fields can be scoped
+// with "recording.", "collection." or "capture.", and unscoped fields belong
+// to whatever the query selects from. A scope is only available if the
+// source provides it, so captures can refer to all three while recordings
+// can only refer to themselves.
+func resolveField(field string, source Source) (accessor, error) {
+	for _, scope := range scopes {
+		if !strings.HasPrefix(field, scope.name+".") {
+			continue
+		}
+
+		if scope.source > source {
+			return nil, fmt.Errorf("field '%s' is not available when selecting from %s", field, source)
+		}
+
+		if resolved, ok := scope.resolve(strings.TrimPrefix(field, scope.name+".")); ok {
+			return resolved, nil
+		}
+		return nil, fmt.Errorf("unknown field '%s'", field)
+	}
+
+	if resolved, ok := scopes[source].resolve(field); ok {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("unknown field '%s'", field)
+}
@@ -0,0 +1,142 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenOpenParen
+	tokenCloseParen
+	tokenComma
+	tokenStar
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+
+	// number is the parsed value of number tokens
+	number float64
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// keyword reports whether the token is the keyword provided, ignoring case.
+func (t token) keyword(word string) bool {
+	return t.kind == tokenIdentifier && strings.EqualFold(t.text, word)
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "<", ">", "~"}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return r == '_' || r == '.' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNumberStart(runes []rune, i int) bool {
+	if unicode.IsDigit(runes[i]) {
+		return true
+	}
+	return (runes[i] == '-' || runes[i] == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])
+}
+
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	tokens := make([]token, 0)
+
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, text: "(", position: start})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, text: ")", position: start})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: start})
+			i++
+
+		case r == '*':
+			tokens = append(tokens, token{kind: tokenStar, text: "*", position: start})
+			i++
+
+		case r == '\'' || r == '"':
+			str := strings.Builder{}
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				str.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("string starting at position %d is never closed", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: str.String(), position: start})
+
+		case isNumberStart(runes, i):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			text := string(runes[start:i])
+			number, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, position: start, number: number})
+
+		case isIdentifierStart(r):
+			for i < len(runes) && isIdentifierPart(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:i]), position: start})
+
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[i:]), operator) {
+					tokens = append(tokens, token{kind: tokenOperator, text: operator, position: start})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, start)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: len(runes)}), nil
+}
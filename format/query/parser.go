@@ -0,0 +1,351 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type expression interface {
+	evaluate(r row) interface{}
+}
+
+type literalExpression struct {
+	value interface{}
+}
+
+func (e literalExpression) evaluate(r row) interface{} {
+	return e.value
+}
+
+type fieldExpression struct {
+	field accessor
+}
+
+func (e fieldExpression) evaluate(r row) interface{} {
+	return e.field(r)
+}
+
+type notExpression struct {
+	operand expression
+}
+
+func (e notExpression) evaluate(r row) interface{} {
+	return !truthy(e.operand.evaluate(r))
+}
+
+type logicalExpression struct {
+	and         bool
+	left, right expression
+}
+
+func (e logicalExpression) evaluate(r row) interface{} {
+	if e.and {
+		return truthy(e.left.evaluate(r)) && truthy(e.right.evaluate(r))
+	}
+	return truthy(e.left.evaluate(r)) || truthy(e.right.evaluate(r))
+}
+
+type comparisonExpression struct {
+	operator    string
+	left, right expression
+}
+
+func (e comparisonExpression) evaluate(r row) interface{} {
+	return compare(e.operator, e.left.evaluate(r), e.right.evaluate(r))
+}
+
+func truthy(value interface{}) bool {
+	b, ok := value.(bool)
+	return ok && b
+}
+
+// compare applies the operator to the two values. Comparisons involving a
+// missing value are always false. Values of different types are never equal,
+// and can't be ordered.
+func compare(operator string, left, right interface{}) bool {
+	if left == nil || right == nil {
+		return false
+	}
+
+	if operator == "~" {
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return false
+		}
+		matched, _ := path.Match(r, l)
+		return matched
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		}
+	}
+
+	return false
+}
+
+var aggregates = []string{"count", "min", "max", "avg"}
+
+// projection is an entry of the select list as written, before its field has
+// been resolved against the source.
+type projection struct {
+	aggregate string
+	field     string
+	at        token
+}
+
+func (p projection) name() string {
+	if p.aggregate == "" {
+		return p.field
+	}
+	return fmt.Sprintf("%s(%s)", p.aggregate, p.field)
+}
+
+type parser struct {
+	tokens   []token
+	position int
+	source   Source
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEOF {
+		p.position++
+	}
+	return t
+}
+
+func unexpected(t token, expected string) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("expected %s but reached the end of the query", expected)
+	}
+	return fmt.Errorf("expected %s but found %s at position %d", expected, t, t.position)
+}
+
+func (p *parser) expectKeyword(word string) error {
+	if t := p.next(); !t.keyword(word) {
+		return unexpected(t, fmt.Sprintf("'%s'", word))
+	}
+	return nil
+}
+
+func (p *parser) parseProjection() ([]projection, error) {
+	if p.peek().kind == tokenStar {
+		p.next()
+		return nil, nil
+	}
+
+	projections := make([]projection, 0)
+	for {
+		t := p.next()
+		if t.kind != tokenIdentifier {
+			return nil, unexpected(t, "a field or aggregate")
+		}
+
+		isAggregate := false
+		for _, aggregate := range aggregates {
+			if t.keyword(aggregate) && p.peek().kind == tokenOpenParen {
+				isAggregate = true
+			}
+		}
+
+		if !isAggregate {
+			projections = append(projections, projection{field: t.text, at: t})
+		} else {
+			p.next()
+			argument := p.next()
+			if argument.kind == tokenStar && !t.keyword("count") {
+				return nil, fmt.Errorf("%s at position %d needs a field, only count accepts '*'", strings.ToLower(t.text), t.position)
+			}
+			if argument.kind != tokenStar && argument.kind != tokenIdentifier {
+				return nil, unexpected(argument, "a field")
+			}
+			if closing := p.next(); closing.kind != tokenCloseParen {
+				return nil, unexpected(closing, "')'")
+			}
+			projections = append(projections, projection{aggregate: strings.ToLower(t.text), field: argument.text, at: t})
+		}
+
+		if p.peek().kind != tokenComma {
+			return projections, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseSource() error {
+	t := p.next()
+	for _, source := range []Source{Recordings, Collections, Captures} {
+		if t.keyword(source.String()) {
+			p.source = source
+			return nil
+		}
+	}
+	return unexpected(t, "'recordings', 'collections' or 'captures'")
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().keyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if p.peek().keyword("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenOperator {
+		return left, nil
+	}
+	operator := p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if pattern, ok := right.(literalExpression); ok && operator.text == "~" {
+		str, isString := pattern.value.(string)
+		if !isString {
+			return nil, fmt.Errorf("'~' at position %d must be followed by a string pattern", operator.position)
+		}
+		if _, err := path.Match(str, ""); err != nil {
+			return nil, fmt.Errorf("malformed pattern '%s' at position %d", str, operator.position)
+		}
+	}
+
+	return comparisonExpression{operator: operator.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literalExpression{value: t.text}, nil
+
+	case tokenNumber:
+		return literalExpression{value: t.number}, nil
+
+	case tokenOpenParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenCloseParen {
+			return nil, unexpected(closing, "')'")
+		}
+		return inner, nil
+
+	case tokenIdentifier:
+		switch {
+		case t.keyword("true"):
+			return literalExpression{value: true}, nil
+		case t.keyword("false"):
+			return literalExpression{value: false}, nil
+		}
+
+		field, err := resolveField(t.text, p.source)
+		if err != nil {
+			return nil, fmt.Errorf("%s at position %d", err.Error(), t.position)
+		}
+		return fieldExpression{field: field}, nil
+	}
+	return nil, unexpected(t, "a field or value")
+}
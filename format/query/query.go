@@ -0,0 +1,283 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/recolude/rap/format"
+)
+
+// Query is a parsed query, ready to be run against any number of recordings.
+//
+// Queries take the form:
+//
+//	select <fields> from <recordings|collections|captures> [where <condition>] [limit <n>]
+//
+// The select list is either "*", a comma separated list of fields, or a comma
+// separated list of the aggregates count, min, max and avg, such as
+// "count(*), avg(value)". Plain fields and aggregates can't be mixed.
+//
+// Fields are scoped with "recording.", "collection." or "capture.", and
+// unscoped fields belong to whatever is being selected from. Recordings have
+// id, name, path, depth and metadata.<key>. Collections have name, signature,
+// length, start, end and metadata.<key>. Captures have time, index, value,
+// name and metadata.<key> (events), label and duration (spans), and x, y and
+// z (positions, eulers and vector2s). Metadata keys can step into nested
+// blocks and arrays, such as metadata.player.scores.0.
+//
+// Conditions compare fields and values with ==, !=, <, <=, >, >= and ~, which
+// matches a string against a glob, and are combined with and, or, not and
+// parentheses. Strings are quoted with either ' or ". A comparison involving a
+// field the row doesn't have is always false.
+//
+// For example, every Damage event over 50 dealt to the red team:
+//
+//	select recording.path, time, metadata.amount from captures
+//	where name == 'Damage' and metadata.amount > 50 and recording.metadata.team == 'red'
+type Query struct {
+	source  Source
+	columns []column
+	where   expression
+	limit   int
+}
+
+type column struct {
+	name      string
+	aggregate string
+	field     accessor
+}
+
+// Result is the table produced by running a query. Every value is one of
+// nil, float64, string or bool, with nil standing in for fields a row doesn't
+// have.
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+var defaultColumns = map[Source][]string{
+	Recordings:  {"path", "id", "name"},
+	Collections: {"recording.path", "name", "signature", "length"},
+	Captures:    {"recording.path", "collection.name", "time", "value"},
+}
+
+// Parse turns the text of a query into a Query, checking that every field it
+// refers to exists.
+func Parse(text string) (Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return Query{}, err
+	}
+
+	p := &parser{tokens: tokens}
+	if err := p.expectKeyword("select"); err != nil {
+		return Query{}, err
+	}
+
+	projections, err := p.parseProjection()
+	if err != nil {
+		return Query{}, err
+	}
+
+	if err := p.expectKeyword("from"); err != nil {
+		return Query{}, err
+	}
+
+	if err := p.parseSource(); err != nil {
+		return Query{}, err
+	}
+
+	q := Query{source: p.source, limit: -1}
+
+	if p.peek().keyword("where") {
+		p.next()
+		q.where, err = p.parseOr()
+		if err != nil {
+			return Query{}, err
+		}
+	}
+
+	if p.peek().keyword("limit") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenNumber || t.number < 0 || t.number != math.Trunc(t.number) {
+			return Query{}, unexpected(t, "a whole number after 'limit'")
+		}
+		q.limit = int(t.number)
+	}
+
+	if t := p.next(); t.kind != tokenEOF {
+		return Query{}, unexpected(t, "end of query")
+	}
+
+	if projections == nil {
+		for _, field := range defaultColumns[q.source] {
+			projections = append(projections, projection{field: field})
+		}
+	}
+
+	aggregated := 0
+	for _, projected := range projections {
+		col := column{name: projected.name(), aggregate: projected.aggregate}
+		if projected.field != "*" {
+			col.field, err = resolveField(projected.field, q.source)
+			if err != nil {
+				return Query{}, fmt.Errorf("%s at position %d", err.Error(), projected.at.position)
+			}
+		}
+		if col.aggregate != "" {
+			aggregated++
+		}
+		q.columns = append(q.columns, col)
+	}
+
+	if aggregated > 0 && aggregated < len(q.columns) {
+		return Query{}, errors.New("select can not mix aggregates with plain fields")
+	}
+
+	return q, nil
+}
+
+func (q Query) aggregating() bool {
+	return len(q.columns) > 0 && q.columns[0].aggregate != ""
+}
+
+type aggregation struct {
+	count   int
+	numbers int
+	sum     float64
+	min     float64
+	max     float64
+}
+
+func (a *aggregation) add(col column, r row) {
+	if col.field == nil {
+		a.count++
+		return
+	}
+
+	value := col.field(r)
+	if value == nil {
+		return
+	}
+	a.count++
+
+	number, ok := value.(float64)
+	if !ok {
+		return
+	}
+	if a.numbers == 0 || number < a.min {
+		a.min = number
+	}
+	if a.numbers == 0 || number > a.max {
+		a.max = number
+	}
+	a.sum += number
+	a.numbers++
+}
+
+func (a aggregation) result(aggregate string) interface{} {
+	if aggregate == "count" {
+		return float64(a.count)
+	}
+
+	if a.numbers == 0 {
+		return nil
+	}
+
+	switch aggregate {
+	case "min":
+		return a.min
+	case "max":
+		return a.max
+	}
+	return a.sum / float64(a.numbers)
+}
+
+var errLimitReached = errors.New("limit reached")
+
+// Run evaluates the query against the recording and everything nested within
+// it. Rows come out in the order format.Walk visits recordings, with
+// collections and captures in the order they're stored.
+func (q Query) Run(rec format.Recording) Result {
+	result := Result{
+		Columns: make([]string, len(q.columns)),
+		Rows:    make([][]interface{}, 0),
+	}
+	for i, col := range q.columns {
+		result.Columns[i] = col.name
+	}
+
+	aggregations := make([]aggregation, len(q.columns))
+
+	visit := func(r row) error {
+		if q.where != nil && !truthy(q.where.evaluate(r)) {
+			return nil
+		}
+
+		if q.aggregating() {
+			for i, col := range q.columns {
+				aggregations[i].add(col, r)
+			}
+			return nil
+		}
+
+		if len(result.Rows) == q.limit {
+			return errLimitReached
+		}
+
+		values := make([]interface{}, len(q.columns))
+		for i, col := range q.columns {
+			values[i] = col.field(r)
+		}
+		result.Rows = append(result.Rows, values)
+		return nil
+	}
+
+	// The only error the walk can end with is the limit being reached
+	format.Walk(rec, func(current format.Recording, path string, depth int) error {
+		r := row{path: path, depth: depth, recording: current}
+		if q.source == Recordings {
+			return visit(r)
+		}
+
+		for _, collection := range current.CaptureCollections() {
+			r.collection = collection
+			if q.source == Collections {
+				if err := visit(r); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for i, capture := range collection.Captures() {
+				r.capture = capture
+				r.index = i
+				if err := visit(r); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if q.aggregating() && q.limit != 0 {
+		values := make([]interface{}, len(q.columns))
+		for i, col := range q.columns {
+			values[i] = aggregations[i].result(col.aggregate)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+
+	return result
+}
+
+// Run parses the query and runs it against the recording.
+func Run(rec format.Recording, text string) (Result, error) {
+	q, err := Parse(text)
+	if err != nil {
+		return Result{}, err
+	}
+	return q.Run(rec), nil
+}
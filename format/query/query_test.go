@@ -0,0 +1,169 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/format/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func damage(time float64, amount int) event.Capture {
+	return event.NewCapture(time, "Damage", metadata.NewBlock(map[string]metadata.Property{
+		"amount": metadata.NewIntProperty(amount),
+	}))
+}
+
+func subject(name, team string, events []event.Capture) format.Recording {
+	return format.NewBuilder().
+		SetID(name+"-id").
+		SetName(name).
+		SetMetadata(metadata.NewBlock(map[string]metadata.Property{
+			"team": metadata.NewStringProperty(team),
+		})).
+		AddCollection(
+			event.NewCollection("Events", events),
+			position.NewCollection("Position", []position.Capture{
+				position.NewCapture(1, 1, 2, 3),
+				position.NewCapture(2, 4, 5, 6),
+			}),
+			enum.NewCollection("State", []string{"idle", "running"}, []enum.Capture{
+				enum.NewCapture(1, 0),
+				enum.NewCapture(2, 1),
+			}),
+		).
+		Build()
+}
+
+func queryTestRecording() format.Recording {
+	return format.NewBuilder().
+		SetName("match").
+		AddChild(
+			subject("alice", "red", []event.Capture{
+				damage(1, 60),
+				damage(2, 20),
+				event.NewCapture(3, "Heal", metadata.NewBlock(map[string]metadata.Property{
+					"amount": metadata.NewIntProperty(80),
+				})),
+			}),
+			subject("bob", "blue", []event.Capture{
+				damage(1, 90),
+			}),
+			subject("carol", "red", []event.Capture{
+				damage(4, 55),
+			}),
+		).
+		Build()
+}
+
+func Test_Run(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		columns []string
+		rows    [][]interface{}
+	}{
+		"recordings": {
+			query:   "select * from recordings where metadata.team == 'red'",
+			columns: []string{"path", "id", "name"},
+			rows: [][]interface{}{
+				{"match/alice", "alice-id", "alice"},
+				{"match/carol", "carol-id", "carol"},
+			},
+		},
+		"collections by signature": {
+			query:   "select recording.name, name from collections where signature == 'recolude.enum' and recording.name ~ '[ab]*'",
+			columns: []string{"recording.name", "name"},
+			rows: [][]interface{}{
+				{"alice", "State"},
+				{"bob", "State"},
+			},
+		},
+		"damage events in the red team": {
+			query:   "SELECT recording.path, time, metadata.amount FROM captures WHERE name == 'Damage' AND metadata.amount > 50 AND recording.metadata.team == \"red\"",
+			columns: []string{"recording.path", "time", "metadata.amount"},
+			rows: [][]interface{}{
+				{"match/alice", 1.0, 60.0},
+				{"match/carol", 4.0, 55.0},
+			},
+		},
+		"capture components": {
+			query:   "select x, y, z from captures where collection.name == 'Position' and time >= 2 and recording.name == 'bob'",
+			columns: []string{"x", "y", "z"},
+			rows:    [][]interface{}{{4.0, 5.0, 6.0}},
+		},
+		"enum values": {
+			query:   "select value from captures where collection.name == 'State' and recording.name == 'alice'",
+			columns: []string{"value"},
+			rows:    [][]interface{}{{"idle"}, {"running"}},
+		},
+		"missing fields": {
+			query:   "select name, x from captures where recording.name == 'bob' and time == 1 and not (collection.name == 'State')",
+			columns: []string{"name", "x"},
+			rows: [][]interface{}{
+				{"Damage", nil},
+				{nil, 1.0},
+			},
+		},
+		"or": {
+			query:   "select name from recordings where name == 'bob' or depth == 0",
+			columns: []string{"name"},
+			rows:    [][]interface{}{{"match"}, {"bob"}},
+		},
+		"limit": {
+			query:   "select name from recordings limit 2",
+			columns: []string{"name"},
+			rows:    [][]interface{}{{"match"}, {"alice"}},
+		},
+		"aggregates": {
+			query:   "select count(*), count(metadata.amount), min(metadata.amount), max(metadata.amount), avg(metadata.amount) from captures where name == 'Damage'",
+			columns: []string{"count(*)", "count(metadata.amount)", "min(metadata.amount)", "max(metadata.amount)", "avg(metadata.amount)"},
+			rows:    [][]interface{}{{4.0, 4.0, 20.0, 90.0, 56.25}},
+		},
+		"aggregates over nothing": {
+			query:   "select count(*), avg(time) from captures where name == 'Jump'",
+			columns: []string{"count(*)", "avg(time)"},
+			rows:    [][]interface{}{{0.0, nil}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := query.Run(queryTestRecording(), tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.columns, result.Columns)
+			assert.Equal(t, tc.rows, result.Rows)
+		})
+	}
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		err   string
+	}{
+		"missing select":     {query: "from recordings", err: "expected 'select' but found 'from' at position 0"},
+		"unknown source":     {query: "select * from binaries", err: "expected 'recordings', 'collections' or 'captures' but found 'binaries' at position 14"},
+		"unknown field":      {query: "select colour from recordings", err: "unknown field 'colour' at position 7"},
+		"unavailable scope":  {query: "select * from collections where capture.time > 1", err: "field 'capture.time' is not available when selecting from collections at position 32"},
+		"unclosed string":    {query: "select * from recordings where name == 'bob", err: "string starting at position 39 is never closed"},
+		"dangling operator":  {query: "select * from recordings where name ==", err: "expected a field or value but reached the end of the query"},
+		"mixed aggregates":   {query: "select name, count(*) from recordings", err: "select can not mix aggregates with plain fields"},
+		"star aggregate":     {query: "select avg(*) from captures", err: "avg at position 7 needs a field, only count accepts '*'"},
+		"malformed glob":     {query: "select * from recordings where name ~ '['", err: "malformed pattern '[' at position 36"},
+		"trailing tokens":    {query: "select * from recordings bob", err: "expected end of query but found 'bob' at position 25"},
+		"fractional limit":   {query: "select * from recordings limit 1.5", err: "expected a whole number after 'limit' but found '1.5' at position 31"},
+		"unexpected symbol":  {query: "select * from recordings where name = 'bob'", err: "unexpected character '=' at position 36"},
+		"unclosed aggregate": {query: "select count(* from recordings", err: "expected ')' but found 'from' at position 15"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := query.Parse(tc.query)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
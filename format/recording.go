@@ -29,15 +29,45 @@ type BinaryReference interface {
 
 type CaptureCollection interface {
 	Name() string
+
+	// Metadata describes the collection as a whole, such as units, coordinate
+	// system or the device that sourced it.
+	Metadata() metadata.Block
+
+	// WithMetadata returns a copy of the collection with its metadata
+	// replaced by the block provided.
+	WithMetadata(block metadata.Block) CaptureCollection
+
 	Captures() []Capture
 	Signature() string
 	Slice(beginning, end float64) CaptureCollection
+
 	Start() float64
 	End() float64
 	Length() int
 	CaptureAt(index int) Capture
 }
 
+// TimeMapper is implemented by capture collections whose captures can be moved
+// in time. Every collection in this module implements it, and Retime requires
+// it of every collection it moves.
+type TimeMapper interface {
+	// MapTime returns a copy of the collection with the time of every capture
+	// passed through the mapping. The mapping must never decrease, so captures
+	// stay in order.
+	MapTime(mapping func(time float64) float64) CaptureCollection
+}
+
+// Appender is implemented by capture collections that can be joined with
+// another collection of the same type. Every collection in this module
+// implements it, and Concat requires it of every collection it joins.
+type Appender interface {
+	// Append returns a copy of the collection with the captures of the other
+	// collection added after its own. The other collection must be of the
+	// same type, and captures are not re-sorted.
+	Append(other CaptureCollection) (CaptureCollection, error)
+}
+
 type Recording interface {
 	ID() string
 	Name() string
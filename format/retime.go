@@ -0,0 +1,232 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/recolude/rap/format/metadata"
+)
+
+// BinaryStartKey is the metadata key binaries and binary references can use
+// to record when they begin within the recording, such as the moment an audio
+// or video capture starts playing. The value is a Float32Property or
+// Float64Property holding the time in seconds, on the same clock as the
+// recording's captures. Retime, Merge and Concat move it along with the
+// captures, and leave values of any other type untouched. The key is
+// namespaced so it never collides with an application's own "start".
+const BinaryStartKey = "recolude.start"
+
+// TimeMapping transforms the time of a capture. Mappings must never decrease,
+// so that captures stay in order.
+type TimeMapping func(time float64) float64
+
+// Then creates a mapping that applies this mapping followed by the next.
+func (m TimeMapping) Then(next TimeMapping) TimeMapping {
+	return func(time float64) float64 {
+		return next(m(time))
+	}
+}
+
+// Shift moves every time by the seconds provided.
+func Shift(seconds float64) TimeMapping {
+	return func(time float64) float64 {
+		return time + seconds
+	}
+}
+
+func finite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// Scale multiplies every time by the factor provided, which must be greater
+// than zero. Factors above one slow playback down, and factors below one
+// speed it up.
+func Scale(factor float64) (TimeMapping, error) {
+	if factor <= 0 || !finite(factor) {
+		return nil, errors.New("scale must be greater than zero")
+	}
+
+	return func(time float64) float64 {
+		return time * factor
+	}, nil
+}
+
+// Rezero shifts time so the recording provided begins at zero. Recordings
+// without any captures are left where they are.
+func Rezero(rec Recording) TimeMapping {
+	start := RecordingStart(rec)
+	if start > RecordingEnd(rec) {
+		return Shift(0)
+	}
+	return Shift(-start)
+}
+
+// WarpPoint pins a time in the original recording to the time it should be
+// moved to.
+type WarpPoint struct {
+	From float64
+	To   float64
+}
+
+// Warp creates a piecewise linear mapping passing through every point
+// provided. Times between two points are interpolated, and times before the
+// first or after the last point keep their distance to it. Points must be
+// finite and ordered by From, and To must never decrease.
+func Warp(points ...WarpPoint) (TimeMapping, error) {
+	if len(points) == 0 {
+		return nil, errors.New("warp requires at least one point")
+	}
+
+	for _, point := range points {
+		if !finite(point.From) || !finite(point.To) {
+			return nil, errors.New("warp points must be finite")
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		if points[i].From <= points[i-1].From {
+			return nil, errors.New("warp points must be ordered by increasing from time")
+		}
+		if points[i].To < points[i-1].To {
+			return nil, errors.New("warp points must not map to earlier times")
+		}
+	}
+
+	pinned := append(make([]WarpPoint, 0, len(points)), points...)
+	return func(time float64) float64 {
+		next := sort.Search(len(pinned), func(i int) bool { return pinned[i].From >= time })
+
+		if next == 0 {
+			return time + pinned[0].To - pinned[0].From
+		}
+
+		if next == len(pinned) {
+			last := pinned[len(pinned)-1]
+			return time + last.To - last.From
+		}
+
+		before, after := pinned[next-1], pinned[next]
+		progress := (time - before.From) / (after.From - before.From)
+		return before.To + progress*(after.To-before.To)
+	}, nil
+}
+
+// Pause is a stretch of the recording to cut out of its timeline.
+type Pause struct {
+	Start float64
+	End   float64
+}
+
+// RemovePauses creates a mapping that closes the gaps left by the pauses
+// provided, pulling everything after each pause earlier by its length.
+// Captures made during a pause are moved to the moment it began. Pauses must
+// not overlap.
+func RemovePauses(pauses ...Pause) (TimeMapping, error) {
+	sorted := make([]Pause, 0, len(pauses))
+	for _, pause := range pauses {
+		if pause.End < pause.Start {
+			return nil, errors.New("pauses must end after they start")
+		}
+		if pause.End > pause.Start {
+			sorted = append(sorted, pause)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	if len(sorted) == 0 {
+		return Shift(0), nil
+	}
+
+	points := make([]WarpPoint, 0, len(sorted)*2)
+	removed := 0.0
+	for i, pause := range sorted {
+		if i > 0 && pause.Start < sorted[i-1].End {
+			return nil, errors.New("pauses must not overlap")
+		}
+
+		if i == 0 || pause.Start > sorted[i-1].End {
+			points = append(points, WarpPoint{From: pause.Start, To: pause.Start - removed})
+		}
+		points = append(points, WarpPoint{From: pause.End, To: pause.Start - removed})
+		removed += pause.End - pause.Start
+	}
+
+	return Warp(points...)
+}
+
+// retimeBlock moves the start recorded in the block, reporting whether the
+// block held one.
+func retimeBlock(block metadata.Block, mapping TimeMapping) (metadata.Block, bool) {
+	switch start := block.Mapping()[BinaryStartKey].(type) {
+	case metadata.Float32Property:
+		moved := metadata.NewFloat32Property(float32(mapping(float64(start.Value()))))
+		return metadata.BuilderFrom(block).With(BinaryStartKey, moved).Build(), true
+
+	case metadata.Float64Property:
+		moved := metadata.NewFloat64Property(mapping(start.Value()))
+		return metadata.BuilderFrom(block).With(BinaryStartKey, moved).Build(), true
+	}
+	return block, false
+}
+
+type retimedBinary struct {
+	Binary
+	metadata metadata.Block
+}
+
+func (b retimedBinary) Metadata() metadata.Block {
+	return b.metadata
+}
+
+type retimedBinaryReference struct {
+	BinaryReference
+	metadata metadata.Block
+}
+
+func (b retimedBinaryReference) Metadata() metadata.Block {
+	return b.metadata
+}
+
+// Retime passes the time of every capture in the recording and all
+// recordings nested within it through the mapping. Binaries and binary
+// references that record when they begin under BinaryStartKey are moved
+// along with the captures. Every collection must implement TimeMapper.
+func Retime(rec Recording, mapping TimeMapping) (Recording, error) {
+	collections := make([]CaptureCollection, len(rec.CaptureCollections()))
+	for i, collection := range rec.CaptureCollections() {
+		mapper, ok := collection.(TimeMapper)
+		if !ok {
+			return nil, fmt.Errorf("%s#%s: %s collections can not be retimed", rec.Name(), collection.Name(), collection.Signature())
+		}
+		collections[i] = mapper.MapTime(mapping)
+	}
+
+	children := make([]Recording, len(rec.Recordings()))
+	for i, child := range rec.Recordings() {
+		var err error
+		children[i], err = Retime(child, mapping)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%w", rec.Name(), err)
+		}
+	}
+
+	binaries := make([]Binary, len(rec.Binaries()))
+	for i, binary := range rec.Binaries() {
+		binaries[i] = binary
+		if block, moved := retimeBlock(binary.Metadata(), mapping); moved {
+			binaries[i] = retimedBinary{Binary: binary, metadata: block}
+		}
+	}
+
+	references := make([]BinaryReference, len(rec.BinaryReferences()))
+	for i, reference := range rec.BinaryReferences() {
+		references[i] = reference
+		if block, moved := retimeBlock(reference.Metadata(), mapping); moved {
+			references[i] = retimedBinaryReference{BinaryReference: reference, metadata: block}
+		}
+	}
+
+	return NewRecording(rec.ID(), rec.Name(), collections, children, rec.Metadata(), binaries, references), nil
+}
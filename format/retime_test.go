@@ -0,0 +1,183 @@
+package format_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/collection/span"
+	"github.com/recolude/rap/format/io"
+	"github.com/recolude/rap/format/metadata"
+	"github.com/recolude/rap/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureTimes(collection format.CaptureCollection) []float64 {
+	times := make([]float64, collection.Length())
+	for i, capture := range collection.Captures() {
+		times[i] = capture.Time()
+	}
+	return times
+}
+
+func timedRecording(times ...float64) format.Recording {
+	captures := make([]position.Capture, len(times))
+	for i, time := range times {
+		captures[i] = position.NewCapture(time, 0, 0, 0)
+	}
+	return format.NewBuilder().
+		SetName("session").
+		AddCollection(position.NewCollection("Position", captures)).
+		AddChild(format.NewBuilder().
+			SetName("child").
+			AddCollection(span.NewCollection("Abilities", []span.Capture{
+				span.NewCapture(times[0], times[len(times)-1], "Shield", metadata.EmptyBlock()),
+			})).
+			Build()).
+		AddBinary(
+			io.NewBinary("audio", []byte{1}, metadata.NewBlock(map[string]metadata.Property{
+				format.BinaryStartKey: metadata.NewFloat32Property(float32(times[0])),
+			})),
+			io.NewBinary("thumbnail", []byte{1}, metadata.EmptyBlock()),
+		).
+		AddBinaryReference(
+			io.NewBinaryReference("video", "uri", 1, metadata.NewBlock(map[string]metadata.Property{
+				format.BinaryStartKey: metadata.NewFloat64Property(times[0]),
+			})),
+			io.NewBinaryReference("frames", "uri", 1, metadata.NewBlock(map[string]metadata.Property{
+				format.BinaryStartKey: metadata.NewIntProperty(12),
+				"start":               metadata.NewFloat64Property(times[0]),
+			})),
+		).
+		Build()
+}
+
+func Test_Retime(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := timedRecording(100, 101, 103)
+
+	// ACT ====================================================================
+	scale, scaleErr := format.Scale(2)
+	retimed, err := format.Retime(rec, format.Rezero(rec).Then(scale).Then(format.Shift(1)))
+
+	// ASSERT =================================================================
+	assert.NoError(t, scaleErr)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 3, 7}, captureTimes(retimed.CaptureCollections()[0]))
+
+	abilities := retimed.Recordings()[0].CaptureCollections()[0]
+	assert.Equal(t, 1.0, abilities.CaptureAt(0).(span.Capture).Start())
+	assert.Equal(t, 7.0, abilities.CaptureAt(0).(span.Capture).End())
+
+	audioStart := retimed.Binaries()[0].Metadata().Mapping()[format.BinaryStartKey]
+	assert.Equal(t, metadata.NewFloat32Property(1), audioStart)
+	assert.Equal(t, "audio", retimed.Binaries()[0].Name())
+	assert.Equal(t, rec.Binaries()[1], retimed.Binaries()[1])
+
+	videoStart := retimed.BinaryReferences()[0].Metadata().Mapping()[format.BinaryStartKey]
+	assert.Equal(t, metadata.NewFloat64Property(1), videoStart)
+	assert.Equal(t, "uri", retimed.BinaryReferences()[0].URI())
+	assert.Equal(t, rec.BinaryReferences()[1], retimed.BinaryReferences()[1])
+
+	assert.Equal(t, []float64{100, 101, 103}, captureTimes(rec.CaptureCollections()[0]))
+}
+
+func Test_Retime_CollectionWithoutTimeMapper(t *testing.T) {
+	// ARRANGE ================================================================
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collection := mocks.NewMockCaptureCollection(ctrl)
+	collection.EXPECT().Name().AnyTimes().Return("custom")
+	collection.EXPECT().Signature().AnyTimes().Return("studio.custom")
+
+	rec := format.NewBuilder().
+		SetName("root").
+		AddChild(format.NewBuilder().SetName("child").AddCollection(collection).Build()).
+		Build()
+
+	// ACT ====================================================================
+	retimed, err := format.Retime(rec, format.Shift(1))
+
+	// ASSERT =================================================================
+	assert.EqualError(t, err, "root/child#custom: studio.custom collections can not be retimed")
+	assert.Nil(t, retimed)
+}
+
+func Test_Scale_InvalidFactor(t *testing.T) {
+	// ACT ====================================================================
+	_, zeroErr := format.Scale(0)
+	_, negativeErr := format.Scale(-1)
+	_, infErr := format.Scale(math.Inf(1))
+
+	// ASSERT =================================================================
+	assert.EqualError(t, zeroErr, "scale must be greater than zero")
+	assert.EqualError(t, negativeErr, "scale must be greater than zero")
+	assert.EqualError(t, infErr, "scale must be greater than zero")
+}
+
+func Test_Rezero_Empty(t *testing.T) {
+	// ACT ====================================================================
+	mapping := format.Rezero(format.NewBuilder().Build())
+
+	// ASSERT =================================================================
+	assert.Equal(t, 5.0, mapping(5))
+}
+
+func Test_Warp(t *testing.T) {
+	// ARRANGE ================================================================
+	mapping, err := format.Warp(
+		format.WarpPoint{From: 10, To: 0},
+		format.WarpPoint{From: 20, To: 5},
+		format.WarpPoint{From: 30, To: 25},
+	)
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, -2.0, mapping(8))
+	assert.Equal(t, 0.0, mapping(10))
+	assert.Equal(t, 2.5, mapping(15))
+	assert.Equal(t, 5.0, mapping(20))
+	assert.Equal(t, 15.0, mapping(25))
+	assert.Equal(t, 30.0, mapping(35))
+}
+
+func Test_Warp_InvalidPoints(t *testing.T) {
+	// ACT ====================================================================
+	_, emptyErr := format.Warp()
+	_, orderErr := format.Warp(format.WarpPoint{From: 2, To: 0}, format.WarpPoint{From: 1, To: 1})
+	_, backwardsErr := format.Warp(format.WarpPoint{From: 1, To: 1}, format.WarpPoint{From: 2, To: 0})
+	_, nanErr := format.Warp(format.WarpPoint{From: 1, To: 1}, format.WarpPoint{From: math.NaN(), To: 2})
+	_, infErr := format.Warp(format.WarpPoint{From: 1, To: math.Inf(1)})
+
+	// ASSERT =================================================================
+	assert.EqualError(t, emptyErr, "warp requires at least one point")
+	assert.EqualError(t, orderErr, "warp points must be ordered by increasing from time")
+	assert.EqualError(t, backwardsErr, "warp points must not map to earlier times")
+	assert.EqualError(t, nanErr, "warp points must be finite")
+	assert.EqualError(t, infErr, "warp points must be finite")
+}
+
+func Test_RemovePauses(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := timedRecording(1, 2, 3, 6, 7, 9, 12)
+
+	// ACT ====================================================================
+	mapping, err := format.RemovePauses(
+		format.Pause{Start: 8, End: 10},
+		format.Pause{Start: 3, End: 6},
+		format.Pause{Start: 6, End: 6.5},
+	)
+	_, overlapErr := format.RemovePauses(format.Pause{Start: 1, End: 3}, format.Pause{Start: 2, End: 4})
+	_, backwardsErr := format.RemovePauses(format.Pause{Start: 3, End: 1})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	retimed, retimeErr := format.Retime(rec, mapping)
+	assert.NoError(t, retimeErr)
+	assert.Equal(t, []float64{1, 2, 3, 3, 3.5, 4.5, 6.5}, captureTimes(retimed.CaptureCollections()[0]))
+	assert.EqualError(t, overlapErr, "pauses must not overlap")
+	assert.EqualError(t, backwardsErr, "pauses must end after they start")
+}
@@ -20,7 +20,9 @@ func TestSlice(t *testing.T) {
 				position.NewCapture(4, 5, 6, 7),
 				position.NewCapture(10, 11, 12, 13),
 				position.NewCapture(11, 12, 13, 14),
-			}),
+			}).WithMetadata(metadata.NewBlock(map[string]metadata.Property{
+				"units": metadata.NewStringProperty("meters"),
+			})),
 		},
 		[]format.Recording{
 			format.NewRecording("", "", []format.CaptureCollection{
@@ -54,6 +56,7 @@ func TestSlice(t *testing.T) {
 	assert.Len(t, recSliced.CaptureCollections()[0].Captures(), 2)
 	assert.Equal(t, 3.0, recSliced.CaptureCollections()[0].Captures()[0].Time())
 	assert.Equal(t, 4.0, recSliced.CaptureCollections()[0].Captures()[1].Time())
+	assert.Equal(t, metadata.NewStringProperty("meters"), recSliced.CaptureCollections()[0].Metadata().Mapping()["units"])
 
 	assert.Len(t, recSliced.Recordings()[0].CaptureCollections(), 1)
 	assert.Len(t, recSliced.Recordings()[0].CaptureCollections()[0].Captures(), 2)
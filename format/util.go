@@ -5,21 +5,17 @@ import "math"
 func RecordingStart(rec Recording) float64 {
 	min := math.Inf(0)
 
-	for _, rec := range rec.Recordings() {
-		childMin := RecordingStart(rec)
-		if childMin < min {
-			min = childMin
-		}
-	}
-
-	for _, coll := range rec.CaptureCollections() {
-		if coll.Length() > 0 {
-			cap := coll.CaptureAt(0)
-			if cap.Time() < min {
-				min = cap.Time()
+	Walk(rec, func(current Recording, path string, depth int) error {
+		for _, coll := range current.CaptureCollections() {
+			if coll.Length() > 0 {
+				cap := coll.CaptureAt(0)
+				if cap.Time() < min {
+					min = cap.Time()
+				}
 			}
 		}
-	}
+		return nil
+	})
 
 	return min
 }
@@ -27,21 +23,17 @@ func RecordingStart(rec Recording) float64 {
 func RecordingEnd(rec Recording) float64 {
 	max := math.Inf(-1)
 
-	for _, rec := range rec.Recordings() {
-		childMax := RecordingEnd(rec)
-		if childMax > max {
-			max = childMax
-		}
-	}
-
-	for _, coll := range rec.CaptureCollections() {
-		if coll.Length() > 0 {
-			cap := coll.CaptureAt(coll.Length() - 1)
-			if cap.Time() > max {
-				max = cap.Time()
+	Walk(rec, func(current Recording, path string, depth int) error {
+		for _, coll := range current.CaptureCollections() {
+			if coll.Length() > 0 {
+				cap := coll.CaptureAt(coll.Length() - 1)
+				if cap.Time() > max {
+					max = cap.Time()
+				}
 			}
 		}
-	}
+		return nil
+	})
 
 	return max
 }
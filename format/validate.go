@@ -2,51 +2,527 @@ package format
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/EliCDavis/vector"
+	"github.com/recolude/rap/format/metadata"
 )
 
 type ValidateOption func(options *validateOptions)
 
+type recordingSchema struct {
+	depth   int
+	pattern *regexp.Regexp
+	schema  metadata.Schema
+}
+
+func (rs recordingSchema) appliesTo(rec Recording, depth int) bool {
+	if rs.pattern != nil {
+		return rs.pattern.MatchString(rec.Name())
+	}
+	return rs.depth == depth
+}
+
 type validateOptions struct {
-	requireChronologicalCapture bool
+	requireChronologicalCapture     bool
+	requireFiniteValues             bool
+	requireUniqueRecordingIDs       bool
+	requireNonEmptyCollections      bool
+	requireUniqueCollectionNames    bool
+	requireValidBinarySizes         bool
+	requireEnumValuesInRange        bool
+	requireChildrenWithinParentTime bool
+	recordingSchemas                []recordingSchema
+	eventSchemas                    map[string][]metadata.Schema
 }
 
+// RequireChronologicalCapture reports an error for every capture collection
+// whose captures are not sorted by time. Enabled by default.
 func RequireChronologicalCapture(required bool) ValidateOption {
 	return func(options *validateOptions) {
 		options.requireChronologicalCapture = required
 	}
 }
 
-// Validate ensures that a given recording meets all criteria specified
-func Validate(rec Recording, options ...ValidateOption) error {
-	finalOpts := &validateOptions{
-		requireChronologicalCapture: true,
+// RequireFiniteValues reports an error for every capture whose time or
+// values contain NaN or Inf.
+func RequireFiniteValues(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireFiniteValues = required
 	}
+}
 
-	// Loop through each option
-	for _, opt := range options {
-		opt(finalOpts)
+// RequireUniqueRecordingIDs reports an error for every recording sharing a
+// non-empty ID with a recording found earlier in the tree.
+func RequireUniqueRecordingIDs(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireUniqueRecordingIDs = required
 	}
+}
 
-	for _, child := range rec.Recordings() {
-		err := Validate(child, options...)
-		if err != nil {
-			return err
+// RequireNonEmptyCollections reports a warning for every capture collection
+// without captures.
+func RequireNonEmptyCollections(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireNonEmptyCollections = required
+	}
+}
+
+// RequireUniqueCollectionNames reports a warning for every capture collection
+// that shares a name with another collection in the same recording.
+func RequireUniqueCollectionNames(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireUniqueCollectionNames = required
+	}
+}
+
+// RequireValidBinarySizes reports a warning for every binary reference with a
+// size of zero, and an error for every binary whose data doesn't match its
+// reported size. Binary data is measured by seeking, and never read, so
+// binaries whose data can't seek are skipped.
+func RequireValidBinarySizes(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireValidBinarySizes = required
+	}
+}
+
+// RequireEnumValuesInRange reports an error for every enum capture whose
+// value doesn't index into its collection's members.
+func RequireEnumValuesInRange(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireEnumValuesInRange = required
+	}
+}
+
+// RequireChildrenWithinParentTime reports a warning for every child recording
+// whose captures start before or end after the captures of its parent.
+// Parents without captures of their own are skipped.
+func RequireChildrenWithinParentTime(required bool) ValidateOption {
+	return func(options *validateOptions) {
+		options.requireChildrenWithinParentTime = required
+	}
+}
+
+// RecordingMetadataSchemaAtDepth checks the metadata of every recording found
+// at the depth provided against the schema. The recording being validated
+// sits at depth 0, its children at depth 1, and so on.
+func RecordingMetadataSchemaAtDepth(depth int, schema metadata.Schema) ValidateOption {
+	return func(options *validateOptions) {
+		options.recordingSchemas = append(options.recordingSchemas, recordingSchema{depth: depth, schema: schema})
+	}
+}
+
+// RecordingMetadataSchemaMatching checks the metadata of every recording whose
+// name matches the pattern against the schema.
+func RecordingMetadataSchemaMatching(pattern *regexp.Regexp, schema metadata.Schema) ValidateOption {
+	return func(options *validateOptions) {
+		options.recordingSchemas = append(options.recordingSchemas, recordingSchema{pattern: pattern, schema: schema})
+	}
+}
+
+// EventMetadataSchema checks the metadata of every event capture with the
+// name provided against the schema.
+func EventMetadataSchema(eventName string, schema metadata.Schema) ValidateOption {
+	return func(options *validateOptions) {
+		if options.eventSchemas == nil {
+			options.eventSchemas = make(map[string][]metadata.Schema)
 		}
+		options.eventSchemas[eventName] = append(options.eventSchemas[eventName], schema)
 	}
+}
 
-	if finalOpts.requireChronologicalCapture {
-		for _, col := range rec.CaptureCollections() {
-			if col.Length() < 2 {
-				continue
+// Severity is how serious a validation finding is. Only errors cause
+// Validate to fail.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Finding is a single problem found while validating a recording.
+type Finding struct {
+	Severity Severity
+
+	// Rule names the check that produced the finding
+	Rule string
+
+	RecordingID   string
+	RecordingName string
+
+	// Recording is the names of all recordings leading to the one the
+	// finding is about, escaped and joined by "/" as Walk does
+	Recording string
+
+	// Collection and Capture locate the finding within the recording. They're
+	// empty and -1 when they don't apply.
+	Collection string
+	Capture    int
+
+	// Key is a JSON pointer to the offending metadata key, for metadata
+	// findings.
+	Key string
+
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.RecordingID, f.RecordingName, f.Message)
+}
+
+// ValidationReport is every finding from validating a recording, in the
+// order the recording tree was walked.
+type ValidationReport struct {
+	Findings []Finding
+}
+
+// WithSeverity returns all findings of the severity provided.
+func (vr ValidationReport) WithSeverity(severity Severity) []Finding {
+	findings := make([]Finding, 0)
+	for _, finding := range vr.Findings {
+		if finding.Severity == severity {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// HasErrors is true when any finding has a severity of Error.
+func (vr ValidationReport) HasErrors() bool {
+	return len(vr.WithSeverity(Error)) > 0
+}
+
+func (vr ValidationReport) Error() string {
+	if len(vr.Findings) == 1 {
+		return vr.Findings[0].String()
+	}
+
+	findings := make([]string, len(vr.Findings))
+	for i, finding := range vr.Findings {
+		findings[i] = finding.String()
+	}
+	return fmt.Sprintf("%d validation findings: %s", len(vr.Findings), strings.Join(findings, "; "))
+}
+
+// eventCapture is satisfied by event captures, which can't be referenced
+// directly without an import cycle.
+type eventCapture interface {
+	Name() string
+	Metadata() metadata.Block
+}
+
+type enumCollection interface {
+	EnumMembers() []string
+}
+
+type enumCapture interface {
+	Value() int
+}
+
+// captureComponents pulls out every floating point number making up the
+// capture's value, for capture types known to contain them.
+func captureComponents(capture Capture) []float64 {
+	switch c := capture.(type) {
+	case interface{ Value() float64 }:
+		return []float64{c.Value()}
+
+	case interface{ Values() []float64 }:
+		return c.Values()
+
+	case interface{ Value() vector.Vector2 }:
+		return []float64{c.Value().X(), c.Value().Y()}
+
+	case interface{ Position() vector.Vector3 }:
+		p := c.Position()
+		return []float64{p.X(), p.Y(), p.Z()}
+
+	case interface{ EulerZXY() vector.Vector3 }:
+		e := c.EulerZXY()
+		return []float64{e.X(), e.Y(), e.Z()}
+
+	case interface {
+		Root() vector.Vector3
+		Rotations() []vector.Vector3
+	}:
+		components := []float64{c.Root().X(), c.Root().Y(), c.Root().Z()}
+		for _, r := range c.Rotations() {
+			components = append(components, r.X(), r.Y(), r.Z())
+		}
+		return components
+
+	case interface {
+		Origin() vector.Vector3
+		Direction() vector.Vector3
+		LeftOpenness() float64
+		RightOpenness() float64
+		PupilDiameter() float64
+		Confidence() float64
+	}:
+		return []float64{
+			c.Origin().X(), c.Origin().Y(), c.Origin().Z(),
+			c.Direction().X(), c.Direction().Y(), c.Direction().Z(),
+			c.LeftOpenness(), c.RightOpenness(), c.PupilDiameter(), c.Confidence(),
+		}
+
+	case interface{ Duration() float64 }:
+		return []float64{c.Duration()}
+
+	case interface{ Values() []interface{} }:
+		components := make([]float64, 0)
+		for _, v := range c.Values() {
+			switch value := v.(type) {
+			case float64:
+				components = append(components, value)
+			case vector.Vector3:
+				components = append(components, value.X(), value.Y(), value.Z())
 			}
+		}
+		return components
+	}
+	return nil
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// ownTimeRange is the span of time covered by the recording's own captures,
+// ignoring its children.
+func ownTimeRange(rec Recording) (float64, float64, bool) {
+	start, end := math.Inf(1), math.Inf(-1)
+	found := false
+	for _, col := range rec.CaptureCollections() {
+		if col.Length() == 0 {
+			continue
+		}
+		found = true
+		start = math.Min(start, col.CaptureAt(0).Time())
+		end = math.Max(end, col.CaptureAt(col.Length()-1).Time())
+	}
+	return start, end, found
+}
 
+type validator struct {
+	options     *validateOptions
+	seenIDs     map[string]bool
+	findings    []Finding
+	currentRec  Recording
+	currentPath string
+}
+
+func (v *validator) report(severity Severity, rule, collection string, capture int, key, message string) {
+	v.findings = append(v.findings, Finding{
+		Severity:      severity,
+		Rule:          rule,
+		RecordingID:   v.currentRec.ID(),
+		RecordingName: v.currentRec.Name(),
+		Recording:     v.currentPath,
+		Collection:    collection,
+		Capture:       capture,
+		Key:           key,
+		Message:       message,
+	})
+}
+
+func (v *validator) checkCollections(rec Recording) {
+	names := make(map[string]bool)
+
+	for _, col := range rec.CaptureCollections() {
+		if v.options.requireUniqueCollectionNames {
+			if names[col.Name()] {
+				v.report(Warning, "unique-collection-names", col.Name(), -1, "", fmt.Sprintf("%s capture collection name is used more than once", col.Name()))
+			}
+			names[col.Name()] = true
+		}
+
+		if v.options.requireNonEmptyCollections && col.Length() == 0 {
+			v.report(Warning, "non-empty-collections", col.Name(), -1, "", fmt.Sprintf("%s capture collection has no captures", col.Name()))
+		}
+
+		if v.options.requireChronologicalCapture {
 			for i := 1; i < col.Length(); i++ {
 				if col.CaptureAt(i).Time() < col.CaptureAt(i-1).Time() {
-					return fmt.Errorf("[%s] %s: %s capture collection violates chronological event validator", rec.ID(), rec.Name(), col.Name())
+					v.report(Error, "chronological-capture", col.Name(), i, "", fmt.Sprintf("%s capture collection violates chronological event validator", col.Name()))
+					break
+				}
+			}
+		}
+
+		if v.options.requireFiniteValues {
+			for i := 0; i < col.Length(); i++ {
+				capture := col.CaptureAt(i)
+				if !isFinite(capture.Time()) {
+					v.report(Error, "finite-values", col.Name(), i, "", fmt.Sprintf("%s[%d] has a time of %v", col.Name(), i, capture.Time()))
+					continue
+				}
+				for _, component := range captureComponents(capture) {
+					if !isFinite(component) {
+						v.report(Error, "finite-values", col.Name(), i, "", fmt.Sprintf("%s[%d] contains %v", col.Name(), i, component))
+						break
+					}
+				}
+			}
+		}
+
+		if enumCol, ok := col.(enumCollection); ok && v.options.requireEnumValuesInRange {
+			members := len(enumCol.EnumMembers())
+			for i := 0; i < col.Length(); i++ {
+				enumCap, ok := col.CaptureAt(i).(enumCapture)
+				if !ok {
+					continue
+				}
+				if enumCap.Value() < 0 || enumCap.Value() >= members {
+					v.report(Error, "enum-values-in-range", col.Name(), i, "", fmt.Sprintf("%s[%d] value %d out of range of %d members", col.Name(), i, enumCap.Value(), members))
 				}
 			}
 		}
 	}
+}
+
+func (v *validator) checkBinaries(rec Recording) {
+	if !v.options.requireValidBinarySizes {
+		return
+	}
+
+	for _, ref := range rec.BinaryReferences() {
+		if ref.Size() == 0 {
+			v.report(Warning, "valid-binary-sizes", "", -1, "", fmt.Sprintf("binary reference %s has a size of zero", ref.Name()))
+		}
+	}
+
+	for _, bin := range rec.Binaries() {
+		// Only seekable data can be measured without consuming it
+		data, ok := bin.Data().(io.Seeker)
+		if !ok {
+			continue
+		}
+
+		start, err := data.Seek(0, io.SeekCurrent)
+		if err != nil {
+			v.report(Error, "valid-binary-sizes", "", -1, "", fmt.Sprintf("binary %s could not be measured: %s", bin.Name(), err.Error()))
+			continue
+		}
+		end, err := data.Seek(0, io.SeekEnd)
+		if err != nil {
+			v.report(Error, "valid-binary-sizes", "", -1, "", fmt.Sprintf("binary %s could not be measured: %s", bin.Name(), err.Error()))
+			continue
+		}
+		if _, err := data.Seek(start, io.SeekStart); err != nil {
+			v.report(Error, "valid-binary-sizes", "", -1, "", fmt.Sprintf("binary %s could not be measured: %s", bin.Name(), err.Error()))
+			continue
+		}
 
+		if actual := end - start; uint64(actual) != bin.Size() {
+			v.report(Error, "valid-binary-sizes", "", -1, "", fmt.Sprintf("binary %s reports a size of %d but contains %d bytes", bin.Name(), bin.Size(), actual))
+		}
+	}
+}
+
+func (v *validator) checkMetadata(rec Recording, depth int) {
+	for _, recSchema := range v.options.recordingSchemas {
+		if !recSchema.appliesTo(rec, depth) {
+			continue
+		}
+		for _, violation := range recSchema.schema.Validate(rec.Metadata()) {
+			v.report(Error, "metadata-schema", "", -1, violation.Path, "metadata "+violation.String())
+		}
+	}
+
+	if len(v.options.eventSchemas) == 0 {
+		return
+	}
+
+	for _, col := range rec.CaptureCollections() {
+		if col.Signature() != "recolude.event" {
+			continue
+		}
+		for i := 0; i < col.Length(); i++ {
+			event, ok := col.CaptureAt(i).(eventCapture)
+			if !ok {
+				continue
+			}
+			for _, schema := range v.options.eventSchemas[event.Name()] {
+				for _, violation := range schema.Validate(event.Metadata()) {
+					v.report(Error, "metadata-schema", col.Name(), i, violation.Path, fmt.Sprintf("%s[%d] metadata %s", col.Name(), i, violation))
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) checkRecording(rec Recording, path string, depth int) {
+	v.currentRec = rec
+	v.currentPath = path
+
+	if v.options.requireUniqueRecordingIDs && rec.ID() != "" {
+		if v.seenIDs[rec.ID()] {
+			v.report(Error, "unique-recording-ids", "", -1, "", fmt.Sprintf("recording ID %s is used more than once", rec.ID()))
+		}
+		v.seenIDs[rec.ID()] = true
+	}
+
+	v.checkCollections(rec)
+	v.checkBinaries(rec)
+	v.checkMetadata(rec, depth)
+
+	parentStart, parentEnd, parentHasCaptures := ownTimeRange(rec)
+	for _, child := range rec.Recordings() {
+		if v.options.requireChildrenWithinParentTime && parentHasCaptures {
+			childStart, childEnd := RecordingStart(child), RecordingEnd(child)
+			if childStart < parentStart || childEnd > parentEnd {
+				v.currentRec = child
+				v.currentPath = path + "/" + EscapePathName(child.Name())
+				v.report(Warning, "children-within-parent-time", "", -1, "", fmt.Sprintf("captures span [%v, %v] outside of parent's [%v, %v]", childStart, childEnd, parentStart, parentEnd))
+			}
+		}
+		v.checkRecording(child, path+"/"+EscapePathName(child.Name()), depth+1)
+	}
+}
+
+// Check runs every rule enabled by the options provided against the
+// recording and reports everything found.
+func Check(rec Recording, options ...ValidateOption) ValidationReport {
+	finalOpts := &validateOptions{
+		requireChronologicalCapture: true,
+	}
+
+	// Loop through each option
+	for _, opt := range options {
+		opt(finalOpts)
+	}
+
+	v := &validator{
+		options:  finalOpts,
+		seenIDs:  make(map[string]bool),
+		findings: make([]Finding, 0),
+	}
+	v.checkRecording(rec, EscapePathName(rec.Name()), 0)
+
+	return ValidationReport{Findings: v.findings}
+}
+
+// Validate ensures that a given recording meets all criteria specified. If any
+// finding is an error, the full ValidationReport is returned as the error.
+func Validate(rec Recording, options ...ValidateOption) error {
+	report := Check(rec, options...)
+	if report.HasErrors() {
+		return report
+	}
 	return nil
 }
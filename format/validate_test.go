@@ -1,10 +1,19 @@
 package format_test
 
 import (
+	"bytes"
+	goio "io"
+	"io/ioutil"
+	"math"
+	"regexp"
 	"testing"
 
 	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/enum"
+	"github.com/recolude/rap/format/collection/event"
+	"github.com/recolude/rap/format/collection/float"
 	"github.com/recolude/rap/format/collection/position"
+	"github.com/recolude/rap/format/io"
 	"github.com/recolude/rap/format/metadata"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +55,283 @@ func TestValidate(t *testing.T) {
 
 	assert.EqualError(t, err, "[123] child: Position capture collection violates chronological event validator")
 }
+
+func TestValidate_MetadataSchemas(t *testing.T) {
+	// ARRANGE ================================================================
+	requireStudio := metadata.Schema{
+		Keys: map[string]metadata.KeySchema{
+			"studio": {Type: metadata.StringType, Required: true},
+		},
+	}
+
+	requireDamage := metadata.Schema{
+		Keys: map[string]metadata.KeySchema{
+			"damage": {Type: metadata.NumberType, Required: true, Range: &metadata.ValueRange{Min: 0, Max: 10}},
+		},
+	}
+
+	rec := format.NewRecording(
+		"root-id",
+		"root",
+		nil,
+		[]format.Recording{
+			format.NewRecording("a", "player-1", []format.CaptureCollection{
+				event.NewCollection("events", []event.Capture{
+					event.NewCapture(1, "hit", metadata.NewBlock(map[string]metadata.Property{
+						"damage": metadata.NewIntProperty(5),
+					})),
+					event.NewCapture(2, "hit", metadata.NewBlock(map[string]metadata.Property{
+						"damage": metadata.NewIntProperty(50),
+					})),
+					event.NewCapture(3, "jump", metadata.EmptyBlock()),
+				}),
+			}, nil, metadata.EmptyBlock(), nil, nil),
+			format.NewRecording("b", "camera", nil, nil, metadata.EmptyBlock(), nil, nil),
+		},
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	)
+
+	// ACT ====================================================================
+	err := format.Validate(
+		rec,
+		format.RecordingMetadataSchemaAtDepth(0, requireStudio),
+		format.RecordingMetadataSchemaMatching(regexp.MustCompile("^player-"), requireStudio),
+		format.EventMetadataSchema("hit", requireDamage),
+	)
+
+	// ASSERT =================================================================
+	report, ok := err.(format.ValidationReport)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, []format.Finding{
+		{
+			Severity:      format.Error,
+			Rule:          "metadata-schema",
+			RecordingID:   "root-id",
+			RecordingName: "root",
+			Recording:     "root",
+			Capture:       -1,
+			Key:           "/studio",
+			Message:       "metadata /studio: required key is missing",
+		},
+		{
+			Severity:      format.Error,
+			Rule:          "metadata-schema",
+			RecordingID:   "a",
+			RecordingName: "player-1",
+			Recording:     "root/player-1",
+			Capture:       -1,
+			Key:           "/studio",
+			Message:       "metadata /studio: required key is missing",
+		},
+		{
+			Severity:      format.Error,
+			Rule:          "metadata-schema",
+			RecordingID:   "a",
+			RecordingName: "player-1",
+			Recording:     "root/player-1",
+			Collection:    "events",
+			Capture:       1,
+			Key:           "/damage",
+			Message:       "events[1] metadata /damage: 50 is outside of range [0, 10]",
+		},
+	}, report.Findings)
+	assert.EqualError(t, err, "3 validation findings: [root-id] root: metadata /studio: required key is missing; [a] player-1: metadata /studio: required key is missing; [a] player-1: events[1] metadata /damage: 50 is outside of range [0, 10]")
+}
+
+func TestValidate_MetadataSchemasSatisfied(t *testing.T) {
+	rec := format.NewRecording(
+		"root-id",
+		"root",
+		nil,
+		nil,
+		metadata.NewBlock(map[string]metadata.Property{
+			"studio": metadata.NewStringProperty("us"),
+		}),
+		nil,
+		nil,
+	)
+
+	err := format.Validate(rec, format.RecordingMetadataSchemaAtDepth(0, metadata.Schema{
+		Keys: map[string]metadata.KeySchema{
+			"studio": {Type: metadata.StringType, Required: true},
+		},
+	}))
+
+	assert.NoError(t, err)
+}
+
+func findingMessages(findings []format.Finding) []string {
+	messages := make([]string, len(findings))
+	for i, finding := range findings {
+		messages[i] = finding.Severity.String() + " " + finding.Rule + ": " + finding.Message
+	}
+	return messages
+}
+
+func TestCheck_ReportsEveryRule(t *testing.T) {
+	// ARRANGE ================================================================
+	child := format.NewRecording(
+		"dup",
+		"child",
+		[]format.CaptureCollection{
+			position.NewCollection("late", []position.Capture{
+				position.NewCapture(5, 0, 0, 0),
+				position.NewCapture(math.Inf(1), 0, 0, 0),
+			}),
+		},
+		nil,
+		metadata.EmptyBlock(),
+		[]format.Binary{
+			brokenBinary{name: "lying", size: 10, data: []byte{1, 2}},
+		},
+		[]format.BinaryReference{
+			io.NewBinaryReference("nothing", "uri", 0, metadata.EmptyBlock()),
+		},
+	)
+
+	rec := format.NewRecording(
+		"dup",
+		"root",
+		[]format.CaptureCollection{
+			position.NewCollection("pos", []position.Capture{
+				position.NewCapture(1, 0, 0, 0),
+				position.NewCapture(2, math.NaN(), 0, 0),
+			}),
+			position.NewCollection("pos", nil),
+			float.NewCollection("float", []float.Capture{
+				float.NewCapture(1, math.Inf(-1)),
+			}),
+			enum.NewCollection("enum", []string{"a", "b"}, []enum.Capture{
+				enum.NewCapture(1, 1),
+				enum.NewCapture(2, 2),
+			}),
+		},
+		[]format.Recording{child},
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	)
+
+	// ACT ====================================================================
+	report := format.Check(
+		rec,
+		format.RequireChronologicalCapture(false),
+		format.RequireFiniteValues(true),
+		format.RequireUniqueRecordingIDs(true),
+		format.RequireNonEmptyCollections(true),
+		format.RequireUniqueCollectionNames(true),
+		format.RequireValidBinarySizes(true),
+		format.RequireEnumValuesInRange(true),
+		format.RequireChildrenWithinParentTime(true),
+	)
+
+	// ASSERT =================================================================
+	assert.Equal(t, []string{
+		"error finite-values: pos[1] contains NaN",
+		"warning unique-collection-names: pos capture collection name is used more than once",
+		"warning non-empty-collections: pos capture collection has no captures",
+		"error finite-values: float[0] contains -Inf",
+		"error enum-values-in-range: enum[1] value 2 out of range of 2 members",
+		"warning children-within-parent-time: captures span [5, +Inf] outside of parent's [1, 2]",
+		"error unique-recording-ids: recording ID dup is used more than once",
+		"error finite-values: late[1] has a time of +Inf",
+		"warning valid-binary-sizes: binary reference nothing has a size of zero",
+		"error valid-binary-sizes: binary lying reports a size of 10 but contains 2 bytes",
+	}, findingMessages(report.Findings))
+	assert.True(t, report.HasErrors())
+	assert.Len(t, report.WithSeverity(format.Warning), 4)
+	assert.Equal(t, "root/child", report.Findings[5].Recording)
+}
+
+func TestCheck_DoesNotConsumeBinaries(t *testing.T) {
+	// ARRANGE ================================================================
+	stream := &streamingBinary{data: bytes.NewBufferString("stream")}
+	rec := format.NewRecording(
+		"id",
+		"root",
+		nil,
+		nil,
+		metadata.EmptyBlock(),
+		[]format.Binary{
+			stream,
+			brokenBinary{name: "seekable", size: 2, data: []byte{1, 2}},
+		},
+		nil,
+	)
+
+	// ACT ====================================================================
+	report := format.Check(rec, format.RequireValidBinarySizes(true))
+	remaining, err := ioutil.ReadAll(stream.Data())
+
+	// ASSERT =================================================================
+	assert.Empty(t, report.Findings)
+	assert.NoError(t, err)
+	assert.Equal(t, "stream", string(remaining))
+}
+
+func TestValidate_WarningsDoNotFail(t *testing.T) {
+	rec := format.NewRecording(
+		"id",
+		"root",
+		[]format.CaptureCollection{position.NewCollection("empty", nil)},
+		nil,
+		metadata.EmptyBlock(),
+		nil,
+		nil,
+	)
+
+	report := format.Check(rec, format.RequireNonEmptyCollections(true))
+	err := format.Validate(rec, format.RequireNonEmptyCollections(true))
+
+	assert.Len(t, report.Findings, 1)
+	assert.False(t, report.HasErrors())
+	assert.NoError(t, err)
+}
+
+type brokenBinary struct {
+	name string
+	size uint64
+	data []byte
+}
+
+func (b brokenBinary) Name() string {
+	return b.name
+}
+
+func (b brokenBinary) Data() goio.Reader {
+	return bytes.NewReader(b.data)
+}
+
+func (b brokenBinary) Size() uint64 {
+	return b.size
+}
+
+func (b brokenBinary) Metadata() metadata.Block {
+	return metadata.EmptyBlock()
+}
+
+// streamingBinary hands out the same reader every time, so its data can only
+// be read once.
+type streamingBinary struct {
+	data *bytes.Buffer
+}
+
+func (b *streamingBinary) Name() string {
+	return "stream"
+}
+
+func (b *streamingBinary) Data() goio.Reader {
+	return b.data
+}
+
+func (b *streamingBinary) Size() uint64 {
+	return 100
+}
+
+func (b *streamingBinary) Metadata() metadata.Block {
+	return metadata.EmptyBlock()
+}
@@ -0,0 +1,211 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SkipChildren can be returned from a WalkFunc to keep Walk from visiting
+// the children of the recording just visited. The walk carries on with the
+// rest of the tree.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called for every recording Walk visits. The path is the names
+// of all recordings from the root down to and including the one visited,
+// escaped by EscapePathName and joined by "/", and depth is 0 for the root.
+type WalkFunc func(rec Recording, path string, depth int) error
+
+var pathNameEscaper = strings.NewReplacer(`\`, `\\`, "/", `\/`, "#", `\#`)
+
+// EscapePathName escapes the characters of a recording or collection name
+// that carry meaning within a path by placing a backslash before them, so
+// that names containing "/" or "#" can still be selected.
+func EscapePathName(name string) string {
+	return pathNameEscaper.Replace(name)
+}
+
+func walk(rec Recording, recPath string, depth int, fn WalkFunc) error {
+	err := fn(rec, recPath, depth)
+	if errors.Is(err, SkipChildren) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, child := range rec.Recordings() {
+		if err := walk(child, recPath+"/"+EscapePathName(child.Name()), depth+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk visits the recording and all recordings nested within it, parents
+// before their children. Any error returned by fn other than SkipChildren
+// stops the walk and is returned.
+func Walk(rec Recording, fn WalkFunc) error {
+	return walk(rec, EscapePathName(rec.Name()), 0, fn)
+}
+
+// Selection is a recording, or a single collection of a recording, picked
+// out by a path.
+type Selection struct {
+	// Path is the names of all recordings leading to the selection, escaped
+	// and joined by "/", the same as the paths handed out by Walk
+	Path string
+
+	Recording Recording
+
+	// Collection is nil when the path selects recordings rather than
+	// collections
+	Collection CaptureCollection
+}
+
+type selectionPath struct {
+	recordings    []string
+	collection    string
+	hasCollection bool
+}
+
+// unescapedIndexes finds every occurrence of the character in the selector
+// that isn't escaped by a backslash.
+func unescapedIndexes(selector string, target byte) []int {
+	indexes := make([]int, 0)
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '\\':
+			i++
+		case target:
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func parseSelectionPath(selector string) (selectionPath, error) {
+	parsed := selectionPath{}
+
+	recordingPart := selector
+	if hashes := unescapedIndexes(selector, '#'); len(hashes) > 0 {
+		hashIndex := hashes[len(hashes)-1]
+		recordingPart = selector[:hashIndex]
+		parsed.collection = selector[hashIndex+1:]
+		parsed.hasCollection = true
+		if parsed.collection == "" {
+			return parsed, fmt.Errorf("path '%s' is missing a collection after '#'", selector)
+		}
+	}
+
+	if recordingPart == "" {
+		return parsed, fmt.Errorf("path '%s' does not select any recording", selector)
+	}
+
+	start := 0
+	for _, slashIndex := range unescapedIndexes(recordingPart, '/') {
+		parsed.recordings = append(parsed.recordings, recordingPart[start:slashIndex])
+		start = slashIndex + 1
+	}
+	parsed.recordings = append(parsed.recordings, recordingPart[start:])
+
+	for _, pattern := range append(append([]string{}, parsed.recordings...), parsed.collection) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return parsed, fmt.Errorf("path '%s' has malformed pattern '%s'", selector, pattern)
+		}
+	}
+
+	return parsed, nil
+}
+
+// matchName reports whether a single name satisfies the pattern. Wildcards
+// never match "/" in path.Match, but within a name it's just another
+// character, so it's swapped out of both beforehand. Any "/" left in the
+// pattern is escaped and so still only matches a "/".
+func matchName(pattern, name string) bool {
+	matched, _ := path.Match(strings.ReplaceAll(pattern, "/", "\x00"), strings.ReplaceAll(name, "/", "\x00"))
+	return matched
+}
+
+func segmentMatches(pattern string, rec Recording) bool {
+	if matchName(pattern, rec.Name()) {
+		return true
+	}
+	return rec.ID() != "" && matchName(pattern, rec.ID())
+}
+
+// matchRecordings reports whether the recording at the end of the chain of
+// recordings provided satisfies the patterns.
+func matchRecordings(patterns []string, chain []Recording) bool {
+	if len(patterns) == 0 {
+		return len(chain) == 0
+	}
+
+	if patterns[0] == "**" {
+		for skip := 0; skip <= len(chain); skip++ {
+			if matchRecordings(patterns[1:], chain[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(chain) == 0 || !segmentMatches(patterns[0], chain[0]) {
+		return false
+	}
+	return matchRecordings(patterns[1:], chain[1:])
+}
+
+func selectWithin(rec Recording, recPath string, chain []Recording, parsed selectionPath, selections []Selection) []Selection {
+	chain = append(chain, rec)
+
+	if matchRecordings(parsed.recordings, chain) {
+		if !parsed.hasCollection {
+			selections = append(selections, Selection{Path: recPath, Recording: rec})
+		} else {
+			for _, collection := range rec.CaptureCollections() {
+				if matchName(parsed.collection, collection.Name()) {
+					selections = append(selections, Selection{Path: recPath, Recording: rec, Collection: collection})
+				}
+			}
+		}
+	}
+
+	for _, child := range rec.Recordings() {
+		selections = selectWithin(child, recPath+"/"+EscapePathName(child.Name()), chain, parsed, selections)
+	}
+	return selections
+}
+
+// Select resolves a path to every recording or collection it matches. Paths
+// start at the root recording and step down through children, one "/"
+// separated segment per level. A segment matches a recording by either its
+// name or its ID, and may be a glob as understood by path.Match. A segment
+// of "**" matches any number of levels, including none. Ending a path with
+// "#" followed by a collection name (or glob) selects the matching
+// collections of the recordings instead of the recordings themselves. A "/",
+// "#" or backslash that is part of a name must be escaped with a backslash,
+// as EscapePathName does.
+//
+// For example, "*/Enemies/enemy-12#Position" selects the Position collection
+// of the enemy-12 subject in the Enemies group, and "**/enemy-*" selects every
+// enemy wherever it appears. Selections are returned in the order Walk would
+// visit them.
+func Select(rec Recording, selector string) ([]Selection, error) {
+	parsed, err := parseSelectionPath(selector)
+	if err != nil {
+		return nil, err
+	}
+	return selectWithin(rec, EscapePathName(rec.Name()), nil, parsed, make([]Selection, 0)), nil
+}
+
+// Find resolves a path to the first recording or collection it matches. See
+// Select for the path syntax.
+func Find(rec Recording, selector string) (Selection, bool, error) {
+	selections, err := Select(rec, selector)
+	if err != nil || len(selections) == 0 {
+		return Selection{}, false, err
+	}
+	return selections[0], true, nil
+}
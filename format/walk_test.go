@@ -0,0 +1,250 @@
+package format_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/recolude/rap/format"
+	"github.com/recolude/rap/format/collection/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func walkTestRecording() format.Recording {
+	enemy := func(id, name string) format.Recording {
+		return format.NewBuilder().
+			SetID(id).
+			SetName(name).
+			AddCollection(position.NewCollection("Position", nil), position.NewCollection("Rotation", nil)).
+			Build()
+	}
+
+	return format.NewBuilder().
+		SetID("root-id").
+		SetName("session").
+		AddChild(
+			format.NewBuilder().
+				SetName("Enemies").
+				AddChild(enemy("e-11", "enemy-11"), enemy("e-12", "enemy-12")).
+				Build(),
+			format.NewBuilder().
+				SetName("Players").
+				AddChild(
+					format.NewBuilder().
+						SetName("squad").
+						AddChild(enemy("p-1", "enemy-turned-player")).
+						Build(),
+				).
+				Build(),
+		).
+		Build()
+}
+
+func selectionPaths(selections []format.Selection) []string {
+	paths := make([]string, len(selections))
+	for i, selection := range selections {
+		paths[i] = selection.Path
+		if selection.Collection != nil {
+			paths[i] += "#" + selection.Collection.Name()
+		}
+	}
+	return paths
+}
+
+func Test_Walk(t *testing.T) {
+	// ARRANGE ================================================================
+	visited := make([]string, 0)
+	depths := make([]int, 0)
+
+	// ACT ====================================================================
+	err := format.Walk(walkTestRecording(), func(rec format.Recording, path string, depth int) error {
+		visited = append(visited, path)
+		depths = append(depths, depth)
+		return nil
+	})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"session",
+		"session/Enemies",
+		"session/Enemies/enemy-11",
+		"session/Enemies/enemy-12",
+		"session/Players",
+		"session/Players/squad",
+		"session/Players/squad/enemy-turned-player",
+	}, visited)
+	assert.Equal(t, []int{0, 1, 2, 2, 1, 2, 3}, depths)
+}
+
+func Test_Walk_SkipChildren(t *testing.T) {
+	// ARRANGE ================================================================
+	visited := make([]string, 0)
+
+	// ACT ====================================================================
+	err := format.Walk(walkTestRecording(), func(rec format.Recording, path string, depth int) error {
+		visited = append(visited, path)
+		if rec.Name() == "Enemies" {
+			return format.SkipChildren
+		}
+		return nil
+	})
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"session",
+		"session/Enemies",
+		"session/Players",
+		"session/Players/squad",
+		"session/Players/squad/enemy-turned-player",
+	}, visited)
+}
+
+func Test_Walk_StopsOnError(t *testing.T) {
+	// ARRANGE ================================================================
+	stop := errors.New("stop")
+	visited := 0
+
+	// ACT ====================================================================
+	err := format.Walk(walkTestRecording(), func(rec format.Recording, path string, depth int) error {
+		visited++
+		if depth == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	// ASSERT =================================================================
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 3, visited)
+}
+
+func Test_Select(t *testing.T) {
+	tests := map[string]struct {
+		selector string
+		want     []string
+	}{
+		"root by name": {selector: "session", want: []string{"session"}},
+		"root by id":   {selector: "root-id", want: []string{"session"}},
+		"by name":      {selector: "session/Enemies/enemy-12", want: []string{"session/Enemies/enemy-12"}},
+		"by id":        {selector: "*/Enemies/e-11", want: []string{"session/Enemies/enemy-11"}},
+		"glob":         {selector: "*/*/enemy-*", want: []string{"session/Enemies/enemy-11", "session/Enemies/enemy-12"}},
+		"any depth": {selector: "**/enemy-*", want: []string{
+			"session/Enemies/enemy-11",
+			"session/Enemies/enemy-12",
+			"session/Players/squad/enemy-turned-player",
+		}},
+		"collection": {selector: "*/Enemies/enemy-12#Position", want: []string{"session/Enemies/enemy-12#Position"}},
+		"collection glob": {selector: "**/e-1*#*", want: []string{
+			"session/Enemies/enemy-11#Position",
+			"session/Enemies/enemy-11#Rotation",
+			"session/Enemies/enemy-12#Position",
+			"session/Enemies/enemy-12#Rotation",
+		}},
+		"no match":        {selector: "session/Allies", want: []string{}},
+		"too deep":        {selector: "session/Enemies/enemy-12/child", want: []string{}},
+		"wrong root name": {selector: "Enemies", want: []string{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			selections, err := format.Select(walkTestRecording(), tc.selector)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, selectionPaths(selections))
+		})
+	}
+}
+
+func Test_Select_MalformedPaths(t *testing.T) {
+	tests := map[string]struct {
+		selector string
+		err      string
+	}{
+		"empty":              {selector: "", err: "path '' does not select any recording"},
+		"only collection":    {selector: "#Position", err: "path '#Position' does not select any recording"},
+		"missing collection": {selector: "session#", err: "path 'session#' is missing a collection after '#'"},
+		"bad glob":           {selector: "session/[", err: "path 'session/[' has malformed pattern '['"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			selections, err := format.Select(walkTestRecording(), tc.selector)
+			assert.EqualError(t, err, tc.err)
+			assert.Nil(t, selections)
+		})
+	}
+}
+
+func Test_Find(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := walkTestRecording()
+
+	// ACT ====================================================================
+	found, ok, err := format.Find(rec, "**/enemy-*#Rotation")
+	_, missingOK, missingErr := format.Find(rec, "**/boss")
+
+	// ASSERT =================================================================
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "session/Enemies/enemy-11", found.Path)
+	assert.Equal(t, "enemy-11", found.Recording.Name())
+	if assert.NotNil(t, found.Collection) {
+		assert.Equal(t, "Rotation", found.Collection.Name())
+	}
+
+	assert.NoError(t, missingErr)
+	assert.False(t, missingOK)
+}
+
+func Test_Select_EscapedNames(t *testing.T) {
+	// ARRANGE ================================================================
+	rec := format.NewBuilder().
+		SetName("session").
+		AddChild(
+			format.NewBuilder().
+				SetName("a/b").
+				AddCollection(position.NewCollection("x#y", nil), position.NewCollection("y", nil)).
+				Build(),
+			format.NewBuilder().
+				SetName("a").
+				AddChild(format.NewBuilder().SetName("b").Build()).
+				Build(),
+		).
+		Build()
+
+	walked := make([]string, 0)
+	format.Walk(rec, func(rec format.Recording, path string, depth int) error {
+		walked = append(walked, path)
+		return nil
+	})
+
+	// ACT ====================================================================
+	slashed, slashedErr := format.Select(rec, `session/a\/b`)
+	nested, nestedErr := format.Select(rec, "session/a/b")
+	globbed, globbedErr := format.Select(rec, "session/*")
+	hashed, hashedErr := format.Select(rec, `session/a\/b#x\#y`)
+
+	// ASSERT =================================================================
+	assert.Equal(t, []string{"session", `session/a\/b`, "session/a", "session/a/b"}, walked)
+	assert.Equal(t, `a\/b`, format.EscapePathName("a/b"))
+	assert.Equal(t, `x\#y\\z`, format.EscapePathName(`x#y\z`))
+
+	assert.NoError(t, slashedErr)
+	if assert.Len(t, slashed, 1) {
+		assert.Equal(t, `session/a\/b`, slashed[0].Path)
+		assert.Equal(t, "a/b", slashed[0].Recording.Name())
+	}
+
+	assert.NoError(t, nestedErr)
+	if assert.Len(t, nested, 1) {
+		assert.Equal(t, "b", nested[0].Recording.Name())
+	}
+
+	assert.NoError(t, globbedErr)
+	assert.Equal(t, []string{`session/a\/b`, "session/a"}, selectionPaths(globbed))
+
+	assert.NoError(t, hashedErr)
+	if assert.Len(t, hashed, 1) {
+		assert.Equal(t, "x#y", hashed[0].Collection.Name())
+	}
+}
@@ -0,0 +1,87 @@
+package binary
+
+import (
+	"bytes"
+	"io"
+)
+
+// BitsRequired returns the minimum number of bits needed to represent the
+// value provided.
+func BitsRequired(value uint64) int {
+	bits := 0
+	for value > 0 {
+		bits++
+		value >>= 1
+	}
+	return bits
+}
+
+// BitWriter packs values of arbitrary bit widths into a byte buffer, least
+// significant bit first.
+type BitWriter struct {
+	buf     bytes.Buffer
+	current byte
+	used    int
+}
+
+func NewBitWriter() *BitWriter {
+	return &BitWriter{}
+}
+
+// Write appends the lowest numBits bits of value to the buffer.
+func (bw *BitWriter) Write(value uint64, numBits int) {
+	for i := 0; i < numBits; i++ {
+		bw.current |= byte((value>>i)&1) << bw.used
+		bw.used++
+		if bw.used == 8 {
+			bw.buf.WriteByte(bw.current)
+			bw.current = 0
+			bw.used = 0
+		}
+	}
+}
+
+// Bytes flushes any partially filled byte and returns everything written.
+func (bw *BitWriter) Bytes() []byte {
+	if bw.used > 0 {
+		bw.buf.WriteByte(bw.current)
+		bw.current = 0
+		bw.used = 0
+	}
+	return bw.buf.Bytes()
+}
+
+// BitReader reads values of arbitrary bit widths written by a BitWriter.
+type BitReader struct {
+	in      io.Reader
+	current byte
+	left    int
+	err     error
+}
+
+func NewBitReader(in io.Reader) *BitReader {
+	return &BitReader{in: in}
+}
+
+// Read pulls the next numBits bits from the underlying reader.
+func (br *BitReader) Read(numBits int) (uint64, error) {
+	var value uint64
+	buf := []byte{0}
+	for i := 0; i < numBits; i++ {
+		if br.left == 0 {
+			if br.err != nil {
+				return value, br.err
+			}
+			_, br.err = io.ReadFull(br.in, buf)
+			if br.err != nil {
+				return value, br.err
+			}
+			br.current = buf[0]
+			br.left = 8
+		}
+		value |= uint64(br.current&1) << i
+		br.current >>= 1
+		br.left--
+	}
+	return value, nil
+}
@@ -0,0 +1,62 @@
+package binary_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/recolude/rap/internal/io/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BitsRequired(t *testing.T) {
+	assert.Equal(t, 0, binary.BitsRequired(0))
+	assert.Equal(t, 1, binary.BitsRequired(1))
+	assert.Equal(t, 2, binary.BitsRequired(2))
+	assert.Equal(t, 2, binary.BitsRequired(3))
+	assert.Equal(t, 4, binary.BitsRequired(15))
+	assert.Equal(t, 5, binary.BitsRequired(16))
+	assert.Equal(t, 64, binary.BitsRequired(^uint64(0)))
+}
+
+func Test_BitWriterReader(t *testing.T) {
+	tests := map[string]struct {
+		values  []uint64
+		numBits int
+	}{
+		"empty":            {values: []uint64{}, numBits: 3},
+		"single bit":       {values: []uint64{1, 0, 1, 1, 0, 0, 0, 1, 1}, numBits: 1},
+		"three bits":       {values: []uint64{7, 0, 5, 2, 3, 1, 6}, numBits: 3},
+		"byte aligned":     {values: []uint64{255, 0, 128}, numBits: 8},
+		"crosses boundary": {values: []uint64{1000, 4095, 0, 2048}, numBits: 12},
+		"full width":       {values: []uint64{^uint64(0), 0, 12345678901234}, numBits: 64},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			writer := binary.NewBitWriter()
+			for _, v := range tc.values {
+				writer.Write(v, tc.numBits)
+			}
+			data := writer.Bytes()
+			assert.Len(t, data, (len(tc.values)*tc.numBits+7)/8)
+
+			reader := binary.NewBitReader(bytes.NewReader(data))
+			for _, v := range tc.values {
+				back, err := reader.Read(tc.numBits)
+				if assert.NoError(t, err) {
+					assert.Equal(t, v, back)
+				}
+			}
+		})
+	}
+}
+
+func Test_BitReader_RunsOutOfData(t *testing.T) {
+	reader := binary.NewBitReader(bytes.NewReader([]byte{0b101}))
+
+	_, err := reader.Read(8)
+	assert.NoError(t, err)
+
+	_, err = reader.Read(1)
+	assert.Error(t, err)
+}
@@ -213,6 +213,20 @@ func (mr *MockCaptureCollectionMockRecorder) Length() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Length", reflect.TypeOf((*MockCaptureCollection)(nil).Length))
 }
 
+// Metadata mocks base method.
+func (m *MockCaptureCollection) Metadata() metadata.Block {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata")
+	ret0, _ := ret[0].(metadata.Block)
+	return ret0
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockCaptureCollectionMockRecorder) Metadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockCaptureCollection)(nil).Metadata))
+}
+
 // Name mocks base method.
 func (m *MockCaptureCollection) Name() string {
 	m.ctrl.T.Helper()
@@ -269,6 +283,20 @@ func (mr *MockCaptureCollectionMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockCaptureCollection)(nil).Start))
 }
 
+// WithMetadata mocks base method.
+func (m *MockCaptureCollection) WithMetadata(arg0 metadata.Block) format.CaptureCollection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithMetadata", arg0)
+	ret0, _ := ret[0].(format.CaptureCollection)
+	return ret0
+}
+
+// WithMetadata indicates an expected call of WithMetadata.
+func (mr *MockCaptureCollectionMockRecorder) WithMetadata(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMetadata", reflect.TypeOf((*MockCaptureCollection)(nil).WithMetadata), arg0)
+}
+
 // MockBinary is a mock of Binary interface.
 type MockBinary struct {
 	ctrl     *gomock.Controller